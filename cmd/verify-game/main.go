@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mat285/boardgames/games"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	"github.com/spf13/cobra"
+)
+
+func run() error {
+	commitment := ""
+	cmd := &cobra.Command{
+		Use:           "verify-game <record.json>",
+		Short:         "Verify the deal and move log of a finished game against its commitment",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var record game.Record
+			err = json.Unmarshal(data, &record)
+			if err != nil {
+				return err
+			}
+			if len(commitment) > 0 && commitment != record.Commitment {
+				return fmt.Errorf("Record commitment %s is not the one announced at the start %s", record.Commitment, commitment)
+			}
+			rg, has := games.RegisteredGames()[record.Game]
+			if !has {
+				return fmt.Errorf("Unknown game %s", record.Game)
+			}
+			g, err := rg.New(nil)
+			if err != nil {
+				return err
+			}
+			err = engine.Verify(g, record)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("game %s verified: %d moves match commitment %s\n", record.ID, len(record.Moves), record.Commitment)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&commitment,
+		"commitment",
+		commitment,
+		"The commitment announced when the game started, checked against the one in the record",
+	)
+
+	return cmd.Execute()
+}
+
+func main() {
+	err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
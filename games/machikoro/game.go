@@ -4,24 +4,30 @@ import (
 	"fmt"
 
 	"github.com/blend/go-sdk/uuid"
-	"github.com/mat285/boardgames/games/splendor/meta"
-	"github.com/mat285/boardgames/games/splendor/pkg/game"
-	"github.com/mat285/boardgames/games/splendor/serializer"
+	"github.com/mat285/boardgames/games/machikoro/meta"
+	"github.com/mat285/boardgames/games/machikoro/pkg/game"
+	"github.com/mat285/boardgames/games/machikoro/serializer"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
 	"github.com/mat285/boardgames/pkg/game/v1alpha1"
 )
 
 const (
 	Name = "machikoro"
+
+	VariantStandard = "standard"
 )
 
 var (
-	ID = uuid.V4()
+	ID = meta.ID
 )
 
 var (
-	_ v1alpha1.Game = new(Game)
+	_ v1alpha1.Game      = new(Game)
+	_ v1alpha1.LobbyGame = new(Game)
 )
 
+// Game is Machi Koro, it isn't in the registered games so the server
+// doesn't offer it, the engine and conformance tests play it
 type Game struct {
 	meta.Meta
 	serializer.Serializer
@@ -54,7 +60,15 @@ func (g Game) Name() string {
 	return Name
 }
 
-func (g *Game) Initialize(pids []uuid.UUID) (v1alpha1.StateData, error) {
+func (g Game) Seats() (int, int) {
+	return 2, 4
+}
+
+func (g Game) Variant() string {
+	return VariantStandard
+}
+
+func (g *Game) Initialize(pids []uuid.UUID, r common.Random) (v1alpha1.StateData, error) {
 	players := make([]game.Player, len(pids))
 	for i := range pids {
 		players[i] = game.NewPlayer(pids[i])
@@ -0,0 +1,18 @@
+package machikoro_test
+
+import (
+	"testing"
+
+	"github.com/mat285/boardgames/games/machikoro"
+	"github.com/mat285/boardgames/pkg/game/v1alpha1/gametest"
+)
+
+func TestConformance(t *testing.T) {
+	g, err := machikoro.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gametest.Run(t, g, gametest.Options{
+		Games: 100,
+	})
+}
@@ -10,7 +10,7 @@ const (
 )
 
 var (
-	ID = uuid.V4()
+	ID = uuid.MustParse("b1e8f3d2-7c4a-4e19-8f5d-6a2c9b0e7d13")
 )
 
 type Object struct {
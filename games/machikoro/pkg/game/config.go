@@ -1,4 +1,9 @@
 package game
 
 type Config struct {
+	StartingPlayer int
+}
+
+func StandardConfig() Config {
+	return Config{}
 }
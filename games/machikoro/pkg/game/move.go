@@ -11,8 +11,51 @@ var (
 	_ v1alpha1.Move = new(Move)
 )
 
+const (
+	MoveKindRoll = "roll"
+	MoveKindDice = "dice"
+	MoveKindKeep = "keep"
+	MoveKindBuy  = "buy"
+	MoveKindPass = "pass"
+)
+
+// Move is one step of a turn, exactly one of the fields is set. The
+// player picks how many dice to roll, the system actor decides what
+// they come up as, the player may keep or reroll them with the radio
+// tower and then buys a card or passes
 type Move struct {
 	meta.Object
+	Roll *RollMove
+	Dice *DiceMove
+	Keep *KeepMove
+	Buy  *BuyMove
+	Pass *PassMove
+}
+
+type RollMove struct {
+	Dice int
+}
+
+// DiceMove is the outcome of a roll, only the system actor makes it
+type DiceMove struct {
+	Values []int
+}
+
+// KeepMove keeps the roll instead of using the radio tower to roll again
+type KeepMove struct{}
+
+type BuyMove struct {
+	Card string
+}
+
+type PassMove struct{}
+
+func MoveSliceToMoveSlice(moves []*Move) []v1alpha1.Move {
+	ret := make([]v1alpha1.Move, len(moves))
+	for i := range moves {
+		ret[i] = moves[i]
+	}
+	return ret
 }
 
 func (m *Move) Apply(raw v1alpha1.StateData) (*v1alpha1.MoveResult, error) {
@@ -20,7 +63,57 @@ func (m *Move) Apply(raw v1alpha1.StateData) (*v1alpha1.MoveResult, error) {
 	if !ok {
 		return nil, fmt.Errorf("Invalid State Type")
 	}
-	state = state
-	return nil, nil
+	valid, err := m.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return &v1alpha1.MoveResult{
+			Valid: false,
+			State: state,
+		}, nil
+	}
+	res := &v1alpha1.MoveResult{}
+	res.State, res.Valid, err = state.apply(*m)
+	return res, err
+}
+
+// Kind names the field that is set, matching the moves a phase takes
+func (m *Move) Kind() string {
+	switch {
+	case m.Roll != nil:
+		return MoveKindRoll
+	case m.Dice != nil:
+		return MoveKindDice
+	case m.Keep != nil:
+		return MoveKindKeep
+	case m.Buy != nil:
+		return MoveKindBuy
+	case m.Pass != nil:
+		return MoveKindPass
+	}
+	return ""
+}
 
+func (m *Move) Validate() (bool, error) {
+	nonNil := 0
+	if m.Roll != nil {
+		nonNil++
+	}
+	if m.Dice != nil {
+		nonNil++
+	}
+	if m.Keep != nil {
+		nonNil++
+	}
+	if m.Buy != nil {
+		nonNil++
+	}
+	if m.Pass != nil {
+		nonNil++
+	}
+	if nonNil != 1 {
+		return false, fmt.Errorf("Invalid Move")
+	}
+	return true, nil
 }
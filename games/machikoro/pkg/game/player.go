@@ -1,7 +1,26 @@
 package game
 
-import "github.com/mat285/boardgames/pkg/game/v1alpha1"
+import (
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/machikoro/pkg/types"
+	"github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
 
 type Player struct {
 	v1alpha1.Player
+	Hand types.Hand
+}
+
+func NewPlayer(id uuid.UUID) Player {
+	return Player{
+		Player: v1alpha1.Player{
+			ID: id,
+		},
+		Hand: types.StartingHand(),
+	}
+}
+
+func (p Player) Clone() Player {
+	p.Hand = p.Hand.Clone()
+	return p
 }
@@ -5,32 +5,316 @@ import (
 
 	"github.com/blend/go-sdk/uuid"
 	"github.com/mat285/boardgames/games/machikoro/meta"
+	"github.com/mat285/boardgames/games/machikoro/pkg/types"
 	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
 	"github.com/mat285/boardgames/pkg/game/v1alpha1"
 )
 
 var (
-	_ v1alpha1.StateData = new(State)
+	_ v1alpha1.Chance     = new(State)
+	_ v1alpha1.Phased     = new(State)
+	_ v1alpha1.Scored     = new(State)
+	_ v1alpha1.Resignable = new(State)
+	_ v1alpha1.Rotated    = new(State)
+)
+
+const (
+	ScoreLandmarks = "landmarks"
+)
+
+type Step string
+
+const (
+	// StepRoll waits on the current player to pick how many dice to roll
+	StepRoll Step = "roll"
+	// StepDice is a chance step, the system actor decides the roll
+	StepDice Step = "dice"
+	// StepReroll lets a player with the radio tower keep the roll or roll
+	// again once a turn
+	StepReroll Step = "reroll"
+	// StepBuy waits on the current player to buy a card or pass
+	StepBuy Step = "buy"
 )
 
 type State struct {
 	meta.Object
 	Config
 
-	Players []Player
-	Turn    common.TurnCounter
+	Players  []Player
+	Turn     common.TurnCounter
+	Supply   []types.CardCount
+	Step     Step
+	Dice     int
+	LastRoll []int
+	Rerolled bool
 }
 
 func NewState(players []Player, config Config) State {
+	start := 0
+	if len(players) > 0 {
+		start = config.StartingPlayer % len(players)
+	}
 	return State{
 		Players: players,
 		Config:  config,
-		Turn:    common.NewTurnCounter(len(players), 0),
-		// Board:   items.NewBoard(),
+		Turn:    common.NewTurnCounter(len(players), start),
+		Supply:  types.CardCounts(),
+		Step:    StepRoll,
+	}
+}
+
+func (s State) Clone() v1alpha1.StateData {
+	s.Players = common.CloneSliceFunc(s.Players, Player.Clone)
+	s.Supply = common.CloneSlice(s.Supply)
+	s.LastRoll = common.CloneSlice(s.LastRoll)
+	return s
+}
+
+func (s State) apply(move Move) (State, bool, error) {
+	switch {
+	case move.Roll != nil:
+		return s.applyRoll(*move.Roll)
+	case move.Dice != nil:
+		return s.applyDice(*move.Dice)
+	case move.Keep != nil:
+		return s.applyKeep()
+	case move.Buy != nil:
+		return s.applyBuy(*move.Buy)
+	case move.Pass != nil:
+		if s.Step != StepBuy {
+			return s, false, nil
+		}
+		return s.endTurn(), true, nil
 	}
+	return s, false, fmt.Errorf("No move")
+}
+
+func (s State) applyRoll(move RollMove) (State, bool, error) {
+	if s.Step != StepRoll && s.Step != StepReroll {
+		return s, false, nil
+	}
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return s, false, err
+	}
+	if !s.canRoll(player, move.Dice) {
+		return s, false, nil
+	}
+	s.Rerolled = s.Step == StepReroll
+	s.Step = StepDice
+	s.Dice = move.Dice
+	return s, true, nil
+}
+
+func (s State) applyDice(move DiceMove) (State, bool, error) {
+	if s.Step != StepDice || len(move.Values) != s.Dice {
+		return s, false, nil
+	}
+	die := common.DieN(6)
+	for _, v := range move.Values {
+		if v < die.Value(0) || v > die.Value(die.Faces()-1) {
+			return s, false, nil
+		}
+	}
+	s.LastRoll = common.CloneSlice(move.Values)
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return s, false, err
+	}
+	if !s.Rerolled && player.Hand.HasCard(types.LandmarkRadioTower) {
+		s.Step = StepReroll
+		return s, true, nil
+	}
+	return s.resolve(s.LastRoll), true, nil
+}
+
+func (s State) applyKeep() (State, bool, error) {
+	if s.Step != StepReroll {
+		return s, false, nil
+	}
+	return s.resolve(s.LastRoll), true, nil
+}
+
+func (s State) applyBuy(move BuyMove) (State, bool, error) {
+	if s.Step != StepBuy {
+		return s, false, nil
+	}
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return s, false, err
+	}
+	if !s.canBuy(player, move.Card) {
+		return s, false, nil
+	}
+	return s.buy(move.Card).endTurn(), true, nil
+}
+
+// endTurn passes to the next player, doubles with the amusement park
+// give the same player another turn
+func (s State) endTurn() State {
+	doubles := len(s.LastRoll) == 2 && s.LastRoll[0] == s.LastRoll[1]
+	player, _ := s.GetCurrentPlayer()
+	if !doubles || !player.Hand.HasCard(types.LandmarkAmusementPark) {
+		s.Turn = s.Turn.Advance()
+	}
+	s.Step = StepRoll
+	s.Dice = 0
+	s.Rerolled = false
+	return s
+}
+
+// resolve pays out every activated establishment, restaurants first,
+// then primary and secondary industry, then major establishments, and
+// moves on to buying
+func (s State) resolve(roll []int) State {
+	total := 0
+	for _, r := range roll {
+		total += r
+	}
+	s.LastRoll = roll
+	s.Step = StepBuy
+	s.Players = common.CloneSlice(s.Players)
+	roller := s.Turn.CurrentPlayer()
+	n := len(s.Players)
+
+	for i := 1; i < n; i++ {
+		// restaurants collect counter clockwise from the roller
+		owner := (roller - i + n) % n
+		if s.Turn.IsOut(owner) {
+			continue
+		}
+		for _, card := range s.Players[owner].Hand.Cards[types.CardTypeRestaurant] {
+			if !card.Activates(total) {
+				continue
+			}
+			s = s.transfer(roller, owner, s.cardIncome(owner, card))
+		}
+	}
+
+	for i := range s.Players {
+		if s.Turn.IsOut(i) {
+			continue
+		}
+		earned := 0
+		for _, card := range s.Players[i].Hand.Cards[types.CardTypePrimaryIndustry] {
+			if card.Activates(total) {
+				earned += s.cardIncome(i, card)
+			}
+		}
+		if i == roller {
+			for _, card := range s.Players[i].Hand.Cards[types.CardTypeSecondaryIndustry] {
+				if card.Activates(total) {
+					earned += s.cardIncome(i, card)
+				}
+			}
+		}
+		if earned > 0 {
+			s.Players[i].Hand = s.Players[i].Hand.Collect(earned)
+		}
+	}
+
+	for _, card := range s.Players[roller].Hand.Cards[types.CardTypeMajorEstablishment] {
+		if !card.Activates(total) {
+			continue
+		}
+		for i := range s.Players {
+			if i == roller || s.Turn.IsOut(i) {
+				continue
+			}
+			s = s.transfer(i, roller, card.Coins)
+		}
+	}
+	return s
+}
+
+func (s State) cardIncome(owner int, card types.Card) int {
+	hand := s.Players[owner].Hand
+	income := card.Coins
+	if card.Multiplied {
+		income *= hand.CountIcon(card.PerIcon)
+	}
+	if (card.Icon == types.CardIconCup || card.Icon == types.CardIconBread) && hand.HasCard(types.LandmarkShoppingMall) {
+		income++
+	}
+	return income
+}
+
+// transfer moves as many coins as the payer can afford, s.Players must
+// already be a copy owned by s
+func (s State) transfer(from, to, amount int) State {
+	if amount > s.Players[from].Hand.Money {
+		amount = s.Players[from].Hand.Money
+	}
+	if amount <= 0 {
+		return s
+	}
+	s.Players[from].Hand = s.Players[from].Hand.Subtract(amount)
+	s.Players[to].Hand = s.Players[to].Hand.Collect(amount)
+	return s
+}
+
+func (s State) canRoll(player Player, dice int) bool {
+	return dice == 1 || (dice == 2 && player.Hand.HasCard(types.LandmarkTrainStation))
+}
+
+func (s State) canBuy(player Player, name string) bool {
+	card, ok := types.CardByName(name)
+	if !ok || !player.Hand.CanPurchase(card) {
+		return false
+	}
+	if card.Type == types.CardTypeLandMark || card.Type == types.CardTypeMajorEstablishment {
+		if player.Hand.HasCard(name) {
+			return false
+		}
+	}
+	if card.Type == types.CardTypeLandMark {
+		return true
+	}
+	for _, count := range s.Supply {
+		if count.Name == name {
+			return count.Count > 0
+		}
+	}
+	return false
+}
+
+func (s State) buy(name string) State {
+	card, _ := types.CardByName(name)
+	if card.Type != types.CardTypeLandMark {
+		s.Supply = common.CloneSlice(s.Supply)
+		for i := range s.Supply {
+			if s.Supply[i].Name == name {
+				s.Supply[i].Count--
+			}
+		}
+	}
+	player, _ := s.GetCurrentPlayer()
+	player.Hand = player.Hand.Purchase(card)
+	return s.setCurrentPlayer(player)
+}
+
+// Phase names the step of the turn and the kinds of move it takes
+func (s State) Phase() v1alpha1.Phase {
+	pid, _ := s.CurrentPlayer()
+	phase := v1alpha1.Phase{Name: string(s.Step), Actor: pid}
+	switch s.Step {
+	case StepDice:
+		phase.Moves = []string{MoveKindDice}
+	case StepReroll:
+		phase.Moves = []string{MoveKindKeep, MoveKindRoll}
+	case StepBuy:
+		phase.Moves = []string{MoveKindBuy, MoveKindPass}
+	default:
+		phase.Name = string(StepRoll)
+		phase.Moves = []string{MoveKindRoll}
+	}
+	return phase
 }
 
 func (s State) CurrentPlayer() (uuid.UUID, error) {
+	if s.Step == StepDice {
+		return v1alpha1.SystemActor, nil
+	}
 	player, err := s.GetCurrentPlayer()
 	if err != nil {
 		return nil, err
@@ -49,6 +333,7 @@ func (s State) GetCurrentPlayer() (Player, error) {
 func (s State) setCurrentPlayer(p Player) State {
 	idx := s.Turn.CurrentPlayer()
 	if len(s.Players) > idx {
+		s.Players = common.CloneSlice(s.Players)
 		s.Players[idx] = p
 	}
 	return s
@@ -58,6 +343,71 @@ func (s State) IsDone() bool {
 	return len(s.Winners()) > 0
 }
 
+// Winners built every landmark, or are the last player left
 func (s State) Winners() []uuid.UUID {
-	return nil
+	if len(s.Players) > 1 && s.Turn.Remaining() == 1 {
+		return []uuid.UUID{s.Players[s.Turn.Next()].ID}
+	}
+	var winners []uuid.UUID
+	landmarks := len(types.Landmarks())
+	for _, p := range s.Players {
+		if p.Hand.LandmarksCount() >= landmarks {
+			winners = append(winners, p.ID)
+		}
+	}
+	return winners
+}
+
+// Scores counts landmarks built, coins in hand break ties
+func (s State) Scores() ([]v1alpha1.Score, error) {
+	scores := make([]v1alpha1.Score, len(s.Players))
+	for i, p := range s.Players {
+		landmarks := p.Hand.LandmarksCount()
+		scores[i] = v1alpha1.Score{
+			Player:    p.ID,
+			Points:    landmarks,
+			Breakdown: map[string]int{ScoreLandmarks: landmarks},
+			TieBreak:  []int{p.Hand.Money},
+		}
+	}
+	return scores, nil
+}
+
+// Resign takes the player out, their turns are skipped and their cards stop
+// paying out or costing them anything
+// StartAt hands the first turn to the seat
+func (s State) StartAt(seat int) (v1alpha1.StateData, error) {
+	if seat < 0 || seat >= len(s.Players) {
+		return nil, fmt.Errorf("Invalid start seat %d", seat)
+	}
+	s.Turn = common.NewTurnCounter(len(s.Players), seat)
+	return s, nil
+}
+
+func (s State) Resign(player uuid.UUID) (v1alpha1.StateData, error) {
+	for i, p := range s.Players {
+		if !p.ID.Equal(player) {
+			continue
+		}
+		s.Turn = s.Turn.Eliminate(i)
+		if s.Turn.CurrentPlayer() == i {
+			s.Step = StepRoll
+			s.Dice = 0
+			s.Rerolled = false
+			s.Turn = s.Turn.Advance()
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("Player %s is not in the game", player)
+}
+
+// Eliminated lists the players that resigned in the order they left
+func (s State) Eliminated() []uuid.UUID {
+	out := make([]uuid.UUID, 0, len(s.Turn.Out))
+	for _, idx := range s.Turn.Out {
+		if idx < len(s.Players) {
+			out = append(out, s.Players[idx].ID)
+		}
+	}
+	return out
 }
@@ -1,7 +1,92 @@
 package game
 
-import "github.com/mat285/boardgames/pkg/game/v1alpha1"
+import (
+	"github.com/mat285/boardgames/games/machikoro/pkg/types"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+	"github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
 
 func (s State) ValidMoves() ([]v1alpha1.Move, error) {
-	return nil, nil
+	switch s.Step {
+	case StepDice:
+		outcomes, err := s.ChanceOutcomes()
+		if err != nil {
+			return nil, err
+		}
+		moves := make([]v1alpha1.Move, len(outcomes))
+		for i := range outcomes {
+			moves[i] = outcomes[i].Move
+		}
+		return moves, nil
+	case StepReroll:
+		moves := append([]*Move{{Keep: &KeepMove{}}}, s.ValidRollMoves()...)
+		return MoveSliceToMoveSlice(moves), nil
+	case StepBuy:
+		return MoveSliceToMoveSlice(s.ValidBuyMoves()), nil
+	}
+	return MoveSliceToMoveSlice(s.ValidRollMoves()), nil
+}
+
+func (s State) ValidRollMoves() []*Move {
+	moves := []*Move{}
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return moves
+	}
+	for dice := 1; dice <= 2; dice++ {
+		if s.canRoll(player, dice) {
+			moves = append(moves, &Move{Roll: &RollMove{Dice: dice}})
+		}
+	}
+	return moves
+}
+
+func (s State) ValidBuyMoves() []*Move {
+	moves := []*Move{{Pass: &PassMove{}}}
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return moves
+	}
+	for _, count := range s.Supply {
+		if s.canBuy(player, count.Name) {
+			moves = append(moves, &Move{Buy: &BuyMove{Card: count.Name}})
+		}
+	}
+	for _, card := range types.Landmarks() {
+		if s.canBuy(player, card.Name) {
+			moves = append(moves, &Move{Buy: &BuyMove{Card: card.Name}})
+		}
+	}
+	return moves
+}
+
+// ChanceOutcomes lists every way the dice can come up, the order of two
+// dice only matters for doubles so the rest are folded together
+func (s State) ChanceOutcomes() ([]v1alpha1.ChanceOutcome, error) {
+	if s.Step != StepDice {
+		return nil, nil
+	}
+	die := common.DieN(6)
+	outcomes := []v1alpha1.ChanceOutcome{}
+	for i := 0; i < die.Faces(); i++ {
+		if s.Dice == 1 {
+			outcomes = append(outcomes, diceOutcome(1, die.Value(i)))
+			continue
+		}
+		for j := i; j < die.Faces(); j++ {
+			weight := 2
+			if i == j {
+				weight = 1
+			}
+			outcomes = append(outcomes, diceOutcome(weight, die.Value(i), die.Value(j)))
+		}
+	}
+	return outcomes, nil
+}
+
+func diceOutcome(weight int, values ...int) v1alpha1.ChanceOutcome {
+	return v1alpha1.ChanceOutcome{
+		Move:   &Move{Dice: &DiceMove{Values: values}},
+		Weight: weight,
+	}
 }
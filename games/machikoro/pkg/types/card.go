@@ -11,6 +11,9 @@ type Card struct {
 	Icon        CardIcon
 	Cost        Cost
 	Activation  common.IntRange
+	Coins       int
+	PerIcon     CardIcon
+	Multiplied  bool
 	Description string
 }
 
@@ -21,6 +24,10 @@ func (c Card) GetCost(modifiers ...CostModifier) int {
 	return c.Cost.Cost()
 }
 
+func (c Card) Activates(roll int) bool {
+	return c.Type != CardTypeLandMark && c.Activation.Includes(roll)
+}
+
 type CardType int
 
 const (
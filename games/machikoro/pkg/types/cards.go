@@ -2,6 +2,19 @@ package types
 
 import common "github.com/mat285/boardgames/pkg/common/v1alpha1"
 
+const (
+	LandmarkTrainStation  = "Train Station"
+	LandmarkShoppingMall  = "Shopping Mall"
+	LandmarkAmusementPark = "Amusement Park"
+	LandmarkRadioTower    = "Radio Tower"
+
+	EstablishmentWheatField = "Wheat Field"
+	EstablishmentBakery     = "Bakery"
+	EstablishmentStadium    = "Stadium"
+
+	StartingMoney = 3
+)
+
 type CardCount struct {
 	Card
 	Count int
@@ -12,7 +25,7 @@ func Cards() []Card {
 	id := 0
 	cards := make([]Card, 0, len(counts)*5)
 	for _, count := range counts {
-		for i := 0; i <= count.Count; i++ {
+		for i := 0; i < count.Count; i++ {
 			card := count.Card
 			card.ID = id
 			cards = append(cards, card)
@@ -22,6 +35,20 @@ func Cards() []Card {
 	return cards
 }
 
+func CardByName(name string) (Card, bool) {
+	for _, count := range CardCounts() {
+		if count.Name == name {
+			return count.Card, true
+		}
+	}
+	for _, card := range Landmarks() {
+		if card.Name == name {
+			return card, true
+		}
+	}
+	return Card{}, false
+}
+
 func sortPiles(cards []Card) [][]Card {
 	ret := make([][]Card, 3)
 	for _, card := range cards {
@@ -38,16 +65,200 @@ func sortPiles(cards []Card) [][]Card {
 	return ret
 }
 
+func Landmarks() []Card {
+	return []Card{
+		{
+			Name:        LandmarkTrainStation,
+			Type:        CardTypeLandMark,
+			Icon:        CardIconTower,
+			Cost:        NewCost(4),
+			Description: "You may roll 1 or 2 dice",
+		},
+		{
+			Name:        LandmarkShoppingMall,
+			Type:        CardTypeLandMark,
+			Icon:        CardIconTower,
+			Cost:        NewCost(10),
+			Description: "Each of your cup and bread establishments earn +1 coin",
+		},
+		{
+			Name:        LandmarkAmusementPark,
+			Type:        CardTypeLandMark,
+			Icon:        CardIconTower,
+			Cost:        NewCost(16),
+			Description: "If you roll doubles, take another turn after this one",
+		},
+		{
+			Name:        LandmarkRadioTower,
+			Type:        CardTypeLandMark,
+			Icon:        CardIconTower,
+			Cost:        NewCost(22),
+			Description: "Once every turn, you can choose to re-roll your dice",
+		},
+	}
+}
+
 func CardCounts() []CardCount {
 	return []CardCount{
 		{
 			Card: Card{
-				Name:        "Wheat Field",
+				Name:        EstablishmentWheatField,
 				Type:        CardTypePrimaryIndustry,
 				Icon:        CardIconWheat,
 				Cost:        NewCost(1),
-				Activation:  common.NewIntRange(1, 2),
-				Description: "",
+				Activation:  common.NewIntRange(1, 1),
+				Coins:       1,
+				Description: "Get 1 coin from the bank, on anyone's turn",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Ranch",
+				Type:        CardTypePrimaryIndustry,
+				Icon:        CardIconCow,
+				Cost:        NewCost(1),
+				Activation:  common.NewIntRange(2, 2),
+				Coins:       1,
+				Description: "Get 1 coin from the bank, on anyone's turn",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        EstablishmentBakery,
+				Type:        CardTypeSecondaryIndustry,
+				Icon:        CardIconBread,
+				Cost:        NewCost(1),
+				Activation:  common.NewIntRange(2, 3),
+				Coins:       1,
+				Description: "Get 1 coin from the bank, on your turn only",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Cafe",
+				Type:        CardTypeRestaurant,
+				Icon:        CardIconCup,
+				Cost:        NewCost(2),
+				Activation:  common.NewIntRange(3, 3),
+				Coins:       1,
+				Description: "Get 1 coin from the player who rolled the dice",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Convenience Store",
+				Type:        CardTypeSecondaryIndustry,
+				Icon:        CardIconBread,
+				Cost:        NewCost(2),
+				Activation:  common.NewIntRange(4, 4),
+				Coins:       3,
+				Description: "Get 3 coins from the bank, on your turn only",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Forest",
+				Type:        CardTypePrimaryIndustry,
+				Icon:        CardIconGear,
+				Cost:        NewCost(3),
+				Activation:  common.NewIntRange(5, 5),
+				Coins:       1,
+				Description: "Get 1 coin from the bank, on anyone's turn",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        EstablishmentStadium,
+				Type:        CardTypeMajorEstablishment,
+				Icon:        CardIconTower,
+				Cost:        NewCost(6),
+				Activation:  common.NewIntRange(6, 6),
+				Coins:       2,
+				Description: "Get 2 coins from all players, on your turn only",
+			},
+			Count: 4,
+		},
+		{
+			Card: Card{
+				Name:        "Cheese Factory",
+				Type:        CardTypeSecondaryIndustry,
+				Icon:        CardIconFactory,
+				Cost:        NewCost(5),
+				Activation:  common.NewIntRange(7, 7),
+				Coins:       3,
+				PerIcon:     CardIconCow,
+				Multiplied:  true,
+				Description: "Get 3 coins from the bank for each cow establishment that you own, on your turn only",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Furniture Factory",
+				Type:        CardTypeSecondaryIndustry,
+				Icon:        CardIconFactory,
+				Cost:        NewCost(3),
+				Activation:  common.NewIntRange(8, 8),
+				Coins:       3,
+				PerIcon:     CardIconGear,
+				Multiplied:  true,
+				Description: "Get 3 coins from the bank for each gear establishment that you own, on your turn only",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Mine",
+				Type:        CardTypePrimaryIndustry,
+				Icon:        CardIconGear,
+				Cost:        NewCost(6),
+				Activation:  common.NewIntRange(9, 9),
+				Coins:       5,
+				Description: "Get 5 coins from the bank, on anyone's turn",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Family Restaurant",
+				Type:        CardTypeRestaurant,
+				Icon:        CardIconCup,
+				Cost:        NewCost(3),
+				Activation:  common.NewIntRange(9, 10),
+				Coins:       2,
+				Description: "Get 2 coins from the player who rolled the dice",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Apple Orchard",
+				Type:        CardTypePrimaryIndustry,
+				Icon:        CardIconWheat,
+				Cost:        NewCost(3),
+				Activation:  common.NewIntRange(10, 10),
+				Coins:       3,
+				Description: "Get 3 coins from the bank, on anyone's turn",
+			},
+			Count: 6,
+		},
+		{
+			Card: Card{
+				Name:        "Fruit and Vegetable Market",
+				Type:        CardTypeSecondaryIndustry,
+				Icon:        CardIconFruit,
+				Cost:        NewCost(2),
+				Activation:  common.NewIntRange(11, 12),
+				Coins:       2,
+				PerIcon:     CardIconWheat,
+				Multiplied:  true,
+				Description: "Get 2 coins from the bank for each wheat establishment that you own, on your turn only",
 			},
 			Count: 6,
 		},
@@ -31,6 +31,9 @@ func LandmarkModifier(num int) CostModifier {
 			return c.Cost
 		}
 		cost := c.Cost
+		if num >= len(cost.Values) {
+			num = len(cost.Values) - 1
+		}
 		cost.C = cost.Values[num]
 		return cost
 	}
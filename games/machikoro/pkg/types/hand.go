@@ -1,5 +1,7 @@
 package types
 
+import common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+
 type Hand struct {
 	Money int
 	Cards map[CardType][]Card
@@ -12,35 +14,73 @@ func NewHand(start int) Hand {
 	}
 }
 
+// StartingHand is the wheat field and bakery every player begins with
+func StartingHand() Hand {
+	h := NewHand(StartingMoney)
+	for _, name := range []string{EstablishmentWheatField, EstablishmentBakery} {
+		card, _ := CardByName(name)
+		h.Cards[card.Type] = append(h.Cards[card.Type], card)
+	}
+	return h
+}
+
+func (h Hand) Clone() Hand {
+	h.Cards = common.CloneMapFunc(h.Cards, common.CloneSlice[Card])
+	return h
+}
+
 func (h Hand) LandmarksCount() int {
 	return len(h.Cards[CardTypeLandMark])
 }
 
+func (h Hand) HasCard(name string) bool {
+	return h.CountCard(name) > 0
+}
+
+func (h Hand) CountCard(name string) int {
+	count := 0
+	for _, cards := range h.Cards {
+		for _, c := range cards {
+			if c.Name == name {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (h Hand) CountIcon(icon CardIcon) int {
+	count := 0
+	for t, cards := range h.Cards {
+		if t == CardTypeLandMark {
+			continue
+		}
+		for _, c := range cards {
+			if c.Icon == icon {
+				count++
+			}
+		}
+	}
+	return count
+}
+
 func (h Hand) Collect(money int) Hand {
 	h.Money += money
-	h.Cards = cloneHandCards(h.Cards)
 	return h
 }
 
 func (h Hand) Subtract(money int) Hand {
 	h.Money -= money
-	h.Cards = cloneHandCards(h.Cards)
 	return h
 }
 
+func (h Hand) CanPurchase(card Card) bool {
+	return h.Money >= card.GetCost(LandmarkModifier(h.LandmarksCount()))
+}
+
 func (h Hand) Purchase(card Card) Hand {
 	h.Money -= card.GetCost(LandmarkModifier(h.LandmarksCount()))
-	h.Cards = cloneHandCards(h.Cards)
-	h.Cards[card.Type] = append(h.Cards[card.Type], card)
+	h.Cards = common.CloneMap(h.Cards)
+	h.Cards[card.Type] = append(common.CloneSlice(h.Cards[card.Type]), card)
 	return h
 }
-
-func cloneHandCards(cards map[CardType][]Card) map[CardType][]Card {
-	ret := make(map[CardType][]Card)
-	for k, v := range cards {
-		for _, c := range v {
-			ret[k] = append(ret[k], c)
-		}
-	}
-	return ret
-}
@@ -6,20 +6,25 @@ import (
 	"github.com/blend/go-sdk/uuid"
 	"github.com/mat285/boardgames/games/splendor/meta"
 	"github.com/mat285/boardgames/games/splendor/pkg/game"
+	"github.com/mat285/boardgames/games/splendor/pkg/items"
 	"github.com/mat285/boardgames/games/splendor/serializer"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
 	"github.com/mat285/boardgames/pkg/game/v1alpha1"
 )
 
 const (
 	Name = "splendor"
+
+	VariantStandard = "standard"
 )
 
 var (
-	ID = uuid.V4()
+	ID = meta.ID
 )
 
 var (
-	_ v1alpha1.Game = new(Game)
+	_ v1alpha1.Game      = new(Game)
+	_ v1alpha1.LobbyGame = new(Game)
 )
 
 type Game struct {
@@ -54,12 +59,24 @@ func (g Game) Name() string {
 	return Name
 }
 
-func (g *Game) Initialize(pids []uuid.UUID) (v1alpha1.StateData, error) {
+func (g Game) Seats() (int, int) {
+	return 2, 4
+}
+
+// Variant is standard unless the game plays to a different score
+func (g Game) Variant() string {
+	if g.Config.VictoryPoints == 0 || g.Config.VictoryPoints == items.StandardVictoryPoints {
+		return VariantStandard
+	}
+	return fmt.Sprintf("%d-points", g.Config.VictoryPoints)
+}
+
+func (g *Game) Initialize(pids []uuid.UUID, r common.Random) (v1alpha1.StateData, error) {
 	players := make([]game.Player, len(pids))
 	for i := range pids {
 		players[i] = game.NewPlayer(pids[i])
 	}
-	return game.NewState(players, g.Config), nil
+	return game.NewState(r, players, g.Config), nil
 }
 
 func (g *Game) Load(state v1alpha1.StateData) error {
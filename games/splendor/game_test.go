@@ -0,0 +1,79 @@
+package splendor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/splendor"
+	"github.com/mat285/boardgames/games/splendor/pkg/game"
+	"github.com/mat285/boardgames/games/splendor/pkg/items"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+	v1alpha1 "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	"github.com/mat285/boardgames/pkg/game/v1alpha1/gametest"
+)
+
+func TestConformance(t *testing.T) {
+	g, err := splendor.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gametest.Run(t, g, gametest.Options{
+		// random play takes around a thousand turns with a few hundred
+		// moves listed on each, a sample of them is plenty. The full sweep
+		// runs with gametest.EnvFull
+		Games:        20,
+		MovesPerTurn: 20,
+	})
+}
+
+func TestGemsConserved(t *testing.T) {
+	it := assert.New(t)
+	g, err := splendor.New(nil)
+	it.Nil(err)
+	raw, err := g.Initialize([]uuid.UUID{uuid.V4(), uuid.V4(), uuid.V4()}, common.NewRandom(7))
+	it.Nil(err)
+	r := common.NewRandom(7)
+
+	phases := map[string]bool{}
+	for i := 0; i < 2000 && !raw.IsDone(); i++ {
+		state := raw.(game.State)
+		total := state.Board.Gems
+		for _, p := range state.Players {
+			total = total.Add(p.Hand.Gems)
+		}
+		it.Equal(items.Gems(), total)
+		phases[state.Phase().Name] = true
+
+		moves, err := raw.ValidMoves()
+		it.Nil(err)
+		it.NotEmpty(moves)
+		res, err := moves[r.Intn(len(moves))].Apply(raw)
+		it.Nil(err)
+		it.True(res.Valid)
+		raw = res.State
+	}
+	it.True(phases[string(game.StepReturn)])
+}
+
+func TestView(t *testing.T) {
+	it := assert.New(t)
+	g, err := splendor.New(nil)
+	it.Nil(err)
+	pid := uuid.V4()
+	raw, err := g.Initialize([]uuid.UUID{pid, uuid.V4()}, common.NewRandom(3))
+	it.Nil(err)
+	state := raw.(game.State)
+
+	view := v1alpha1.View(state, pid).(game.State)
+	it.Empty(view.Board.LevelOne.Pile)
+	it.Equal(len(state.Board.LevelOne.Pile), view.Board.LevelOne.Left)
+	it.Equal(state.Board.LevelThree.Shown, view.Board.LevelThree.Shown)
+	// the state itself keeps the pile
+	it.NotEmpty(state.Board.LevelOne.Pile)
+
+	so, err := g.SerializeState(view)
+	it.Nil(err)
+	it.False(strings.Contains(string(so.Data), "Pile"))
+}
@@ -10,7 +10,7 @@ const (
 )
 
 var (
-// ID = uuid.V4()
+	ID = uuid.MustParse("5d6c2a8e-1f4b-4a67-9a0e-3c1b7f2d9e41")
 )
 
 type Object struct {
@@ -24,7 +24,7 @@ type Meta struct {
 }
 
 func (m Meta) ID() uuid.UUID {
-	return uuid.Empty()
+	return ID
 }
 
 func (m Meta) Name() string {
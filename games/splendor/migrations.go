@@ -0,0 +1,96 @@
+package splendor
+
+import (
+	"github.com/mat285/boardgames/games/splendor/meta"
+	"github.com/mat285/boardgames/games/splendor/pkg/game"
+	"github.com/mat285/boardgames/games/splendor/pkg/items"
+	"github.com/mat285/boardgames/pkg/apiversions"
+	"github.com/mat285/boardgames/pkg/game/v1alpha1"
+	migration "github.com/mat285/boardgames/pkg/migration/v1alpha1"
+)
+
+var _ migration.Provider = new(Game)
+
+// moveV1Alpha1 collected and handed back gems over the limit in one move
+type moveV1Alpha1 struct {
+	meta.Object
+	Pass     *game.PassMove
+	Collect  *collectV1Alpha1
+	Purchase *game.CardMove
+	Reserve  *game.CardMove
+}
+
+type collectV1Alpha1 struct {
+	Take   items.GemCount
+	Return items.GemCount
+}
+
+func (g Game) Migrations() []migration.Migration {
+	return []migration.Migration{
+		{
+			From:  apiversions.V1Alpha1,
+			To:    apiversions.V1Alpha2,
+			State: migrateStateV1Alpha1,
+			Split: splitMoveV1Alpha1,
+		},
+	}
+}
+
+// splitMoveV1Alpha1 turns a collect that handed gems back into the collect
+// and the return that follows it now
+func splitMoveV1Alpha1(obj *v1alpha1.SerializedObject) ([]*v1alpha1.SerializedObject, error) {
+	codec, err := v1alpha1.CodecByName(obj.Codec)
+	if err != nil {
+		return nil, err
+	}
+	var old moveV1Alpha1
+	if err := codec.Unmarshal(obj.Data, &old); err != nil {
+		return nil, err
+	}
+	if old.Collect == nil || old.Collect.Return.Total() == 0 {
+		return []*v1alpha1.SerializedObject{obj}, nil
+	}
+	moves := []game.Move{
+		{Collect: &game.CollectMove{Take: old.Collect.Take}},
+		{Return: &game.ReturnMove{Gems: old.Collect.Return}},
+	}
+	split := make([]*v1alpha1.SerializedObject, len(moves))
+	for i := range moves {
+		data, err := codec.Marshal(moves[i])
+		if err != nil {
+			return nil, err
+		}
+		split[i] = &v1alpha1.SerializedObject{ID: obj.ID, Codec: obj.Codec, Data: data}
+	}
+	return split, nil
+}
+
+// migrateStateV1Alpha1 takes the gems the players hold out of the bank,
+// which used to never run down
+func migrateStateV1Alpha1(obj *v1alpha1.SerializedObject) (*v1alpha1.SerializedObject, error) {
+	codec, err := v1alpha1.CodecByName(obj.Codec)
+	if err != nil {
+		return nil, err
+	}
+	var state game.State
+	if err := codec.Unmarshal(obj.Data, &state); err != nil {
+		return nil, err
+	}
+	bank := state.Board.Gems
+	for _, player := range state.Players {
+		bank = bank.Sub(player.Hand.Gems)
+	}
+	counts := bank.ToMap()
+	for gem, count := range counts {
+		if count < 0 {
+			counts[gem] = 0
+		}
+	}
+	state.Board.Gems = counts.ToCount()
+	state.Step = game.StepTake
+	data, err := codec.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.SerializedObject{ID: obj.ID, Codec: obj.Codec, Data: data}, nil
+}
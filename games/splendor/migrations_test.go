@@ -0,0 +1,78 @@
+package splendor_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/splendor"
+	"github.com/mat285/boardgames/games/splendor/pkg/game"
+	"github.com/mat285/boardgames/games/splendor/pkg/items"
+	"github.com/mat285/boardgames/pkg/apiversions"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	v1alpha1 "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	migration "github.com/mat285/boardgames/pkg/migration/v1alpha1"
+)
+
+func oldCollect(it *assert.Assertions, take, ret items.GemCount) *v1alpha1.SerializedObject {
+	data, err := json.Marshal(map[string]interface{}{
+		"Collect": map[string]items.GemCount{"Take": take, "Return": ret},
+	})
+	it.Nil(err)
+	return &v1alpha1.SerializedObject{ID: splendor.ID, Version: apiversions.V1Alpha1, Codec: v1alpha1.CodecJSON, Data: data}
+}
+
+func TestMigrateCollectReturn(t *testing.T) {
+	it := assert.New(t)
+	g, err := splendor.New(nil)
+	it.Nil(err)
+	players := []uuid.UUID{uuid.V4(), uuid.V4()}
+	state, err := g.Initialize(players, common.NewRandom(1))
+	it.Nil(err)
+	initial, err := g.SerializeState(state)
+	it.Nil(err)
+	initial.Version = apiversions.V1Alpha1
+
+	record := v1alpha1.Record{
+		Game:       g.Name(),
+		APIVersion: apiversions.V1Alpha1,
+		Players:    []v1alpha1.Player{{ID: players[0]}, {ID: players[1]}},
+		Initial:    initial,
+	}
+	first := items.GemCount{Diamond: 1, Sapphire: 1, Emerald: 1}
+	second := items.GemCount{Ruby: 1, Obsidian: 1, Emerald: 1}
+	for i := 0; i < 7; i++ {
+		move := oldCollect(it, second, items.GemCount{})
+		if i%2 == 0 {
+			move = oldCollect(it, first, items.GemCount{})
+		}
+		if i == 6 {
+			// the fourth collect takes the first player to twelve gems
+			move = oldCollect(it, first, items.GemCount{Diamond: 1, Sapphire: 1})
+		}
+		record.Moves = append(record.Moves, v1alpha1.RecordedMove{Version: uint64(i + 1), Player: players[i%2], Move: move})
+	}
+
+	migrated := record
+	migrated.Moves = append([]v1alpha1.RecordedMove{}, record.Moves...)
+	it.Nil(migration.MigrateRecord(migration.Migrations(g), &migrated, engine.APIVersion))
+	it.Len(migrated.Moves, 8)
+	it.Equal(migrated.Moves[6].Version, migrated.Moves[7].Version)
+	it.Equal(apiversions.Latest, migrated.Moves[7].Move.Version)
+
+	final, err := engine.Replay(g, record)
+	it.Nil(err)
+	typed := final.(game.State)
+	it.Equal(10, typed.Players[0].Hand.Gems.Total())
+	it.Equal(9, typed.Players[1].Hand.Gems.Total())
+	it.Equal(game.StepTake, typed.Step)
+	it.Equal(items.Gems().Total()-19, typed.Board.Gems.Total())
+
+	// a move packet can't be split up
+	_, err = migration.MigrateMove(migration.Migrations(g), apiversions.V1Alpha1, apiversions.Latest, record.Moves[6].Move)
+	it.NotNil(err)
+	_, err = migration.MigrateMove(migration.Migrations(g), apiversions.V1Alpha1, apiversions.Latest, record.Moves[0].Move)
+	it.Nil(err)
+}
@@ -30,7 +30,7 @@ func (c *Client) GetState(ctx context.Context, id uuid.UUID) (*splendorgame.Stat
 		return nil, err
 	}
 	untyped, err := c.Game.DeserializeState(&game.SerializedObject{
-		ID:   id,
+		ID:   c.Game.ID(),
 		Data: packet.Payload,
 	})
 	if err != nil {
@@ -169,19 +169,61 @@ func (p *TerminalPlayer) Start(ctx context.Context) error {
 				p.Println("not waiting for move")
 				continue
 			}
-			take, ret, err := parseGems(parts[1:])
+			take, err := parseGems(parts[1:])
 			if err != nil {
 				p.Println(err)
 				continue
 			}
+			if take.Total() > 3 {
+				p.Println("Wrong number of gems")
+				continue
+			}
 			if take.Wild > 0 {
 				p.Println("Cannot take wilds")
 				continue
 			}
-			move := &splendor.Move{Collect: &splendor.CollectMove{Take: take, Return: ret}}
+			move := &splendor.Move{Collect: &splendor.CollectMove{Take: take}}
 
 			p.MoveChan <- move
 			continue
+		case "return":
+
+			if p.State.Players == nil {
+				p.Println("No active state")
+				continue
+			}
+			if !p.NeedMove {
+				p.Println("not waiting for move")
+				continue
+			}
+			gems, err := parseGems(parts[1:])
+			if err != nil {
+				p.Println(err)
+				continue
+			}
+			p.MoveChan <- &splendor.Move{Return: &splendor.ReturnMove{Gems: gems}}
+			continue
+		case "noble":
+
+			if p.State.Players == nil {
+				p.Println("No active state")
+				continue
+			}
+			if !p.NeedMove {
+				p.Println("not waiting for move")
+				continue
+			}
+			if len(parts) != 2 {
+				p.Println("Need noble id")
+				continue
+			}
+			bonus, err := parseNoble(p.State, parts[1])
+			if err != nil {
+				p.Println(err)
+				continue
+			}
+			p.MoveChan <- &splendor.Move{Noble: &splendor.NobleMove{Bonus: bonus}}
+			continue
 		case "moves":
 
 			if p.State.Players == nil {
@@ -214,7 +256,7 @@ func (p *TerminalPlayer) Start(ctx context.Context) error {
 			p.MoveChan <- move
 
 		default:
-			p.Println("Commands: board hand gems cards moves exit\n")
+			p.Println("Commands: board hand gems cards moves collect return noble exit\n")
 		}
 	}
 
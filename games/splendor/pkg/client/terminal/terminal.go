@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/blend/go-sdk/uuid"
@@ -19,9 +20,11 @@ import (
 	splendor "github.com/mat285/boardgames/games/splendor/pkg/game"
 	"github.com/mat285/boardgames/games/splendor/pkg/items"
 	httpclient "github.com/mat285/boardgames/pkg/client/http/v1alpha1"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
 	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
 	messages "github.com/mat285/boardgames/pkg/messages/v1alpha1"
 	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
 )
 
 // var _ connection.Client = new(Terminal)
@@ -154,6 +157,9 @@ type Terminal struct {
 
 	State   splendor.State
 	Packets chan wire.Packet
+
+	chatLock sync.Mutex
+	chat     []game.ChatMessage
 }
 
 func NewTerminal(ctx context.Context, username string, cli *httpclient.Client) *Terminal {
@@ -215,18 +221,17 @@ func (p *Terminal) HandleMessage(ctx context.Context, entry string) (result stri
 			result += fmt.Sprintln("please login first")
 			return
 		}
-		p.SplendorClient.Username = p.SplendorClient.Username
 		gcfg := splendor.Config{VictoryPoints: 7}
 		gid, err := p.SplendorClient.NewGame(ctx, "splendor", gcfg)
 		if err != nil {
-			result += fmt.Sprintln("Error making new game", err.Error)
+			result += fmt.Sprintln("Error making new game", err)
 			return
 		}
 		result += fmt.Sprintln("Created new game with ID:", gid)
 		p.CurrentGame = gid
 		err = p.SplendorClient.Join(ctx, gid)
 		if err != nil {
-			result += fmt.Sprintln("Error joining game", gid, err.Error)
+			result += fmt.Sprintln("Error joining game", gid, err)
 			return
 		}
 		result += fmt.Sprintln("Joined game", gid)
@@ -237,21 +242,74 @@ func (p *Terminal) HandleMessage(ctx context.Context, entry string) (result stri
 			return
 		}
 		if len(parts) < 2 {
-			result += "Need game id\n"
+			result += "Need game id or invite code\n"
 			return
 		}
+		password := ""
+		if len(parts) > 2 {
+			password = parts[2]
+		}
 		gid, err := uuid.Parse(parts[1])
 		if err != nil {
-			result += err.Error() + "\n"
+			// not an id so it has to be an invite code
+			gid, err = p.SplendorClient.JoinCode(ctx, parts[1], password)
+		} else {
+			err = p.SplendorClient.JoinWithPassword(ctx, gid, password)
+		}
+		if err != nil {
+			result += fmt.Sprintln("Error joining game", parts[1], err)
 			return
 		}
 		p.CurrentGame = gid
-		err = p.SplendorClient.Join(ctx, gid)
+		result += fmt.Sprintln("Joined game", gid)
+		return
+	case "invite":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		var invite *api.Invite
+		var err error
+		switch {
+		case len(parts) < 2:
+			invite, err = p.SplendorClient.GetInvite(ctx, p.CurrentGame)
+		case parts[1] == "rotate":
+			invite, err = p.SplendorClient.RotateInvite(ctx, p.CurrentGame)
+		case parts[1] == "off":
+			invite, err = p.SplendorClient.DisableInvite(ctx, p.CurrentGame)
+		default:
+			result += "Usage: invite [rotate|off]\n"
+			return
+		}
 		if err != nil {
-			result += fmt.Sprintln("Error joining game", gid, err.Error)
+			result += fmt.Sprintln("Error getting invite", err)
 			return
 		}
-		result += fmt.Sprintln("Joined game", gid)
+		if len(invite.Code) == 0 {
+			result += fmt.Sprintln("Invite code is off, join by id", invite.Game)
+			return
+		}
+		result += fmt.Sprintln("Invite code", invite.Code, "password:", invite.HasPassword)
+		return
+	case "password":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		password := ""
+		if len(parts) > 1 {
+			password = parts[1]
+		}
+		_, err := p.SplendorClient.SetPassword(ctx, p.CurrentGame, password)
+		if err != nil {
+			result += fmt.Sprintln("Error setting password", err)
+			return
+		}
+		if len(password) == 0 {
+			result += fmt.Sprintln("Removed the table password")
+			return
+		}
+		result += fmt.Sprintln("Set the table password")
 		return
 	case "play":
 		if p.SplendorClient.UserID.IsZero() {
@@ -291,12 +349,25 @@ func (p *Terminal) HandleMessage(ctx context.Context, entry string) (result stri
 
 		err := p.SplendorClient.Start(ctx, gid)
 		if err != nil {
-			result += fmt.Sprintln("Error starting game", gid, err.Error)
+			result += fmt.Sprintln("Error starting game", gid, err)
 			return
 		}
 
 		result += fmt.Sprintln("Started game", gid)
 		return
+	case "rematch":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		gid, err := p.SplendorClient.Rematch(ctx, p.CurrentGame)
+		if err != nil {
+			result += fmt.Sprintln("Error making rematch", err)
+			return
+		}
+		p.CurrentGame = gid
+		result += fmt.Sprintln("Switched to rematch", gid)
+		return
 	case "unread":
 		result += fmt.Sprintln("Currently have", len(p.Packets), "unread packets")
 		return
@@ -478,16 +549,20 @@ func (p *Terminal) HandleMessage(ctx context.Context, entry string) (result stri
 			result += fmt.Sprintln("No active state")
 			return
 		}
-		take, ret, err := parseGems(parts[1:])
+		take, err := parseGems(parts[1:])
 		if err != nil {
 			result += fmt.Sprintln(err)
 			return
 		}
+		if take.Total() > 3 {
+			result += fmt.Sprintln("Wrong number of gems")
+			return
+		}
 		if take.Wild > 0 {
 			result += fmt.Sprintln("Cannot take wilds")
 			return
 		}
-		move := &splendor.Move{Collect: &splendor.CollectMove{Take: take, Return: ret}}
+		move := &splendor.Move{Collect: &splendor.CollectMove{Take: take}}
 
 		err = p.sendMove(ctx, move)
 		if err != nil {
@@ -498,6 +573,187 @@ func (p *Terminal) HandleMessage(ctx context.Context, entry string) (result stri
 		result += fmt.Sprintln("Made move\n", prettyJSON(move))
 		return
 
+	case "return", "noble":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		if err := p.maybeFetchState(ctx); err != nil {
+			result += fmt.Sprintln("error fetching state", err)
+			return
+		}
+		if p.State.Players == nil {
+			result += fmt.Sprintln("No active state")
+			return
+		}
+		move := &splendor.Move{}
+		if cmd == "return" {
+			gems, err := parseGems(parts[1:])
+			if err != nil {
+				result += fmt.Sprintln(err)
+				return
+			}
+			move.Return = &splendor.ReturnMove{Gems: gems}
+		} else {
+			if len(parts) != 2 {
+				result += fmt.Sprintln("Need noble id")
+				return
+			}
+			bonus, err := parseNoble(p.State, parts[1])
+			if err != nil {
+				result += fmt.Sprintln(err)
+				return
+			}
+			move.Noble = &splendor.NobleMove{Bonus: bonus}
+		}
+
+		err := p.sendMove(ctx, move)
+		if err != nil {
+			result += fmt.Sprintln("Error sending move", err)
+			return
+		}
+
+		result += fmt.Sprintln("Made move\n", prettyJSON(move))
+		return
+
+	case "resign":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		packet, err := p.Message.MessageResign(p.SplendorClient.UserID)
+		if err != nil {
+			result += fmt.Sprintln(err)
+			return
+		}
+		_, err = p.SplendorClient.SendPacket(ctx, p.CurrentGame, p.SplendorClient.UserID, *packet)
+		if err != nil {
+			result += fmt.Sprintln("Error resigning", err)
+			return
+		}
+		result += fmt.Sprintln("Resigned, watching the rest of the game")
+		return
+
+	case "takeback":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		packet, err := p.Message.MessageTakebackRequest(p.SplendorClient.UserID)
+		if err != nil {
+			result += fmt.Sprintln(err)
+			return
+		}
+		_, err = p.SplendorClient.SendPacket(ctx, p.CurrentGame, p.SplendorClient.UserID, *packet)
+		if err != nil {
+			result += fmt.Sprintln("Error requesting takeback", err)
+			return
+		}
+		result += fmt.Sprintln("Asked the other players to take back your last move")
+		return
+
+	case "approve", "reject":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		if len(parts) < 2 {
+			result += "Need takeback id\n"
+			return
+		}
+		id, err := uuid.Parse(parts[1])
+		if err != nil {
+			result += err.Error() + "\n"
+			return
+		}
+		packet, err := p.Message.MessageTakebackAnswer(id, cmd == "approve", p.SplendorClient.UserID)
+		if err != nil {
+			result += fmt.Sprintln(err)
+			return
+		}
+		_, err = p.SplendorClient.SendPacket(ctx, p.CurrentGame, p.SplendorClient.UserID, *packet)
+		if err != nil {
+			result += fmt.Sprintln("Error answering takeback", err)
+			return
+		}
+		result += fmt.Sprintln("Answered takeback", id)
+		return
+
+	case "say":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		packet, err := p.Message.MessageSendChat(strings.Join(parts[1:], " "), p.SplendorClient.UserID)
+		if err != nil {
+			result += fmt.Sprintln(err)
+			return
+		}
+		_, err = p.SplendorClient.SendPacket(ctx, p.CurrentGame, p.SplendorClient.UserID, *packet)
+		if err != nil {
+			result += fmt.Sprintln("Error sending message", err)
+			return
+		}
+		return
+
+	case "chat":
+		p.chatLock.Lock()
+		for _, msg := range p.chat {
+			result += fmt.Sprintf("[%s] %s: %s\n", msg.Time.Local().Format("15:04"), msg.Username, msg.Text)
+		}
+		p.chatLock.Unlock()
+		return
+
+	case "mute", "unmute":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		if len(parts) < 2 {
+			result += "Need player id\n"
+			return
+		}
+		id, err := uuid.Parse(parts[1])
+		if err != nil {
+			result += err.Error() + "\n"
+			return
+		}
+		packet, err := p.Message.MessageMute(id, cmd == "mute", p.SplendorClient.UserID)
+		if err != nil {
+			result += fmt.Sprintln(err)
+			return
+		}
+		_, err = p.SplendorClient.SendPacket(ctx, p.CurrentGame, p.SplendorClient.UserID, *packet)
+		if err != nil {
+			result += fmt.Sprintln("Error muting", err)
+			return
+		}
+		result += fmt.Sprintln("Updated mute for", id)
+		return
+
+	case "watch":
+		if p.SplendorClient.UserID.IsZero() {
+			result += fmt.Sprintln("please login first")
+			return
+		}
+		if len(parts) < 2 {
+			result += "Need game id\n"
+			return
+		}
+		id, err := uuid.Parse(parts[1])
+		if err != nil {
+			result += err.Error() + "\n"
+			return
+		}
+		err = p.SplendorClient.Spectate(ctx, id)
+		if err != nil {
+			result += fmt.Sprintln("Error watching game", err)
+			return
+		}
+		p.CurrentGame = id
+		result += fmt.Sprintln("Watching game", id)
+		return
+
 	case "moves":
 		if p.SplendorClient.UserID.IsZero() {
 			result += fmt.Sprintln("please login first")
@@ -559,7 +815,7 @@ func (p *Terminal) HandleMessage(ctx context.Context, entry string) (result stri
 		return
 
 	default:
-		result += fmt.Sprintln("Commands: board hand gems cards moves exit\n")
+		result += fmt.Sprintln("Commands: board hand gems cards moves collect return noble resign takeback approve reject rematch invite password exit")
 	}
 	return
 
@@ -623,6 +879,7 @@ func (p *Terminal) retryListen(ctx context.Context) {
 		default:
 		}
 		p.SplendorClient.Connect(ctx, nil)
+		go p.requestChatHistory(ctx)
 		err := p.SplendorClient.Listen(ctx, p.Handle)
 		if err != nil {
 			// fmt.Println("Error listening for websocket", err)
@@ -633,10 +890,41 @@ func (p *Terminal) retryListen(ctx context.Context) {
 }
 
 func (p *Terminal) Handle(ctx context.Context, packet wire.Packet) error {
+	if packet.Type == messages.PacketTypeChatMessages {
+		body, err := p.Message.ExtractChatMessages(packet)
+		if err != nil {
+			return err
+		}
+		p.chatLock.Lock()
+		p.chat = append(p.chat, body.Messages...)
+		if len(p.chat) > engine.DefaultChatHistory {
+			p.chat = p.chat[len(p.chat)-engine.DefaultChatHistory:]
+		}
+		p.chatLock.Unlock()
+		return nil
+	}
 	p.Packets <- packet
 	return nil
 }
 
+// requestChatHistory catches up on the chat missed while disconnected, the
+// messages kept so far are replaced by the history
+func (p *Terminal) requestChatHistory(ctx context.Context) {
+	if p.CurrentGame.IsZero() || p.SplendorClient.UserID.IsZero() {
+		return
+	}
+	// give the websocket a moment to dial so the history isn't missed
+	time.Sleep(time.Second)
+	packet, err := p.Message.MessageChatHistory(p.SplendorClient.UserID)
+	if err != nil {
+		return
+	}
+	p.chatLock.Lock()
+	p.chat = nil
+	p.chatLock.Unlock()
+	p.SplendorClient.SendPacket(ctx, p.CurrentGame, p.SplendorClient.UserID, *packet)
+}
+
 func moveString(i int, move game.Move) string {
 	data, err := json.Marshal(move)
 	if err != nil {
@@ -654,36 +942,28 @@ func jsonMarshal(i interface{}) string {
 	return string(bytes)
 }
 
-func parseGems(parts []string) (items.GemCount, items.GemCount, error) {
-	take := items.GemCount{}
-	returning := false
-	count := 0
+func parseGems(parts []string) (items.GemCount, error) {
 	gems := items.GemCount{}
 	for _, part := range parts {
-		if part == "return" {
-			returning = true
-			if count < 2 || count > 3 {
-				return items.GemCount{}, items.GemCount{}, fmt.Errorf("Wrong number of gems")
-			}
-			take = gems
-			gems = items.GemCount{}
-			count = 0
-			continue
-		}
 		gem, err := items.ParseGem(part)
 		if err != nil {
-			return items.GemCount{}, items.GemCount{}, err
+			return items.GemCount{}, err
 		}
 		gems = gems.AddGem(gem, 1)
-		count++
 	}
-	if returning {
-		return take, gems, nil
+	if gems.Total() == 0 {
+		return items.GemCount{}, fmt.Errorf("Wrong number of gems")
 	}
+	return gems, nil
+}
 
-	if count < 2 || count > 3 {
-		return items.GemCount{}, items.GemCount{}, fmt.Errorf("Wrong number of gems")
+func parseNoble(state splendor.State, part string) (items.Bonus, error) {
+	num, err := strconv.Atoi(part)
+	if err != nil {
+		return items.Bonus{}, err
 	}
-
-	return gems, items.GemCount{}, nil
+	if num < 0 || num >= len(state.Board.Bonuses) {
+		return items.Bonus{}, fmt.Errorf("No noble %d on the board", num)
+	}
+	return state.Board.Bonuses[num], nil
 }
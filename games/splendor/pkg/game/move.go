@@ -11,17 +11,38 @@ import (
 
 var _ v1alpha1.Move = new(Move)
 
+const (
+	MoveKindPass     = "pass"
+	MoveKindCollect  = "collect"
+	MoveKindPurchase = "purchase"
+	MoveKindReserve  = "reserve"
+	MoveKindReturn   = "return"
+	MoveKindNoble    = "noble"
+)
+
 type Move struct {
 	meta.Object
 	Pass     *PassMove
 	Collect  *CollectMove
 	Purchase *CardMove
 	Reserve  *CardMove
+	Return   *ReturnMove
+	Noble    *NobleMove
 }
 
 type CollectMove struct {
-	Take   items.GemCount
-	Return items.GemCount
+	Take items.GemCount
+}
+
+// ReturnMove hands gems back to the bank when a player ends up holding
+// more than they're allowed
+type ReturnMove struct {
+	Gems items.GemCount
+}
+
+// NobleMove picks which noble visits when more than one could
+type NobleMove struct {
+	Bonus items.Bonus
 }
 
 type CardMove struct {
@@ -63,6 +84,25 @@ func (m *Move) Apply(raw v1alpha1.StateData) (*v1alpha1.MoveResult, error) {
 
 }
 
+// Kind names the field that is set, matching the moves a phase takes
+func (m *Move) Kind() string {
+	switch {
+	case m.Pass != nil:
+		return MoveKindPass
+	case m.Collect != nil:
+		return MoveKindCollect
+	case m.Purchase != nil:
+		return MoveKindPurchase
+	case m.Reserve != nil:
+		return MoveKindReserve
+	case m.Return != nil:
+		return MoveKindReturn
+	case m.Noble != nil:
+		return MoveKindNoble
+	}
+	return ""
+}
+
 func (m *Move) Validate() (bool, error) {
 	nonNil := 0
 	if m.Collect != nil {
@@ -77,6 +117,12 @@ func (m *Move) Validate() (bool, error) {
 	if m.Pass != nil {
 		nonNil++
 	}
+	if m.Return != nil {
+		nonNil++
+	}
+	if m.Noble != nil {
+		nonNil++
+	}
 	if nonNil != 1 {
 		return false, fmt.Errorf("Invalid Move")
 	}
@@ -109,6 +155,12 @@ func (c CollectMove) Validate(gems items.GemCount) error {
 		if v < 0 {
 			return fmt.Errorf("Cannot take negative gems")
 		}
+		if k == items.GemWild && v > 0 {
+			return fmt.Errorf("Cannot take wild gems")
+		}
+		if v > gems.Get(k) {
+			return fmt.Errorf("Not enough %s gems left", k)
+		}
 		if v == 2 {
 			double = true
 			if gems.Get(k) < 4 {
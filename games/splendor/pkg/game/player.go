@@ -21,6 +21,11 @@ func NewPlayer(id uuid.UUID) Player {
 	}
 }
 
+func (p Player) Clone() Player {
+	p.Hand = p.Hand.Clone()
+	return p
+}
+
 func ToCommonPlayerSlice(players ...Player) []common.Player {
 	ret := make([]common.Player, len(players))
 	for i := range players {
@@ -11,7 +11,29 @@ import (
 )
 
 var (
-	_ v1alpha1.StateData = State{}
+	_ v1alpha1.StateData  = State{}
+	_ v1alpha1.Phased     = State{}
+	_ v1alpha1.Scored     = State{}
+	_ v1alpha1.Resignable = State{}
+	_ v1alpha1.Takebacks  = State{}
+	_ v1alpha1.Concealed  = State{}
+	_ v1alpha1.Rotated    = State{}
+)
+
+const (
+	ScoreCards  = "cards"
+	ScoreNobles = "nobles"
+)
+
+type Step string
+
+const (
+	// StepTake waits on the current player to collect, purchase, reserve or pass
+	StepTake Step = "take"
+	// StepReturn waits on the current player to get back down to the gem limit
+	StepReturn Step = "return"
+	// StepNoble waits on the current player to pick one of the nobles they earned
+	StepNoble Step = "noble"
 )
 
 type State struct {
@@ -20,20 +42,38 @@ type State struct {
 	Players []Player
 
 	Turn common.TurnCounter
+	Step Step
 
 	Board items.Board
 }
 
-func NewState(players []Player, config Config) State {
+func NewState(r common.Random, players []Player, config Config) State {
 	return State{
 		Players: players,
 		Config:  config,
 		Turn:    common.NewTurnCounter(len(players), 0),
-		Board:   items.NewBoard(),
+		Step:    StepTake,
+		Board:   items.NewBoard(r),
 	}
 }
 
+func (s State) Clone() v1alpha1.StateData {
+	s.Players = common.CloneSliceFunc(s.Players, Player.Clone)
+	s.Board = s.Board.Clone()
+	return s
+}
+
+// View leaves out the order of the piles, everyone sees the same board
+func (s State) View(uuid.UUID) v1alpha1.StateData {
+	s.Players = common.CloneSliceFunc(s.Players, Player.Clone)
+	s.Board = s.Board.View()
+	return s
+}
+
 func (s State) apply(move Move) (state State, valid bool, err error) {
+	if !s.Phase().Allows(move.Kind()) {
+		return s, false, nil
+	}
 	if move.Collect != nil {
 		state, valid, err = s.applyCollect(*move.Collect)
 	} else if move.Purchase != nil {
@@ -42,15 +82,53 @@ func (s State) apply(move Move) (state State, valid bool, err error) {
 		state, valid, err = s.applyReserve(*move.Reserve)
 	} else if move.Pass != nil {
 		state, valid, err = s, true, nil
+	} else if move.Return != nil {
+		state, valid, err = s.applyReturn(*move.Return)
+	} else if move.Noble != nil {
+		state, valid, err = s.applyNoble(*move.Noble)
 	} else {
 		return s, false, fmt.Errorf("No move")
 	}
-	if valid {
-		state.Turn = state.Turn.Advance()
+	if valid && err == nil {
+		state = state.next()
 	}
 	return
 }
 
+// next works out the phase after a move, gems over the limit go back
+// first, then a noble visits if the player earned one, then the turn ends
+func (s State) next() State {
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return s
+	}
+	if player.Hand.Gems.Total() > items.MaxGems {
+		s.Step = StepReturn
+		return s
+	}
+	if s.Step != StepNoble {
+		earned := player.Hand.BonusesEarned(s.Board.Bonuses)
+		if len(earned) > 1 {
+			s.Step = StepNoble
+			return s
+		}
+		if len(earned) == 1 {
+			s = s.visit(earned[0])
+		}
+	}
+	s.Step = StepTake
+	s.Turn = s.Turn.Advance()
+	return s
+}
+
+func (s State) visit(bonus items.Bonus) State {
+	player, _ := s.GetCurrentPlayer()
+	hand := player.Hand
+	hand.Bonus = append(items.CloneBonuses(hand.Bonus), bonus)
+	s.Board = s.Board.RemoveBonuses([]items.Bonus{bonus})
+	return s.setCurrentPlayerHand(hand)
+}
+
 func (s State) applyCollect(move CollectMove) (State, bool, error) {
 	err := move.Validate(s.Board.Gems)
 	if err != nil {
@@ -61,17 +139,40 @@ func (s State) applyCollect(move CollectMove) (State, bool, error) {
 		return s, false, err
 	}
 	hand := player.Hand
-	gems := hand.Gems.Add(move.Take).ToMap()
+	hand.Gems = hand.Gems.Add(move.Take)
+	s.Board.Gems = s.Board.Gems.Sub(move.Take)
+	s = s.setCurrentPlayerHand(hand)
+	return s, true, nil
+}
 
-	if gems.Total() > 10 {
-		gems.Sub(move.Return.ToMap())
-		if !gems.NonNegative() || gems.Total() > 10 {
-			return s, false, err
-		}
+func (s State) applyReturn(move ReturnMove) (State, bool, error) {
+	if !move.Gems.ToMap().NonNegative() {
+		return s, false, fmt.Errorf("Cannot return negative gems")
+	}
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return s, false, err
+	}
+	hand := player.Hand
+	gems := hand.Gems.Sub(move.Gems)
+	if !gems.ToMap().NonNegative() || gems.Total() != items.MaxGems {
+		return s, false, nil
 	}
-	hand.Gems = gems.ToCount()
+	hand.Gems = gems
+	s.Board.Gems = s.Board.Gems.Add(move.Gems)
 	s = s.setCurrentPlayerHand(hand)
-	return s, true, err
+	return s, true, nil
+}
+
+func (s State) applyNoble(move NobleMove) (State, bool, error) {
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return s, false, err
+	}
+	if !items.ContainsBonus(player.Hand.BonusesEarned(s.Board.Bonuses), move.Bonus) {
+		return s, false, nil
+	}
+	return s.visit(move.Bonus), true, nil
 }
 
 func (s State) applyPurchase(move CardMove) (State, bool, error) {
@@ -90,10 +191,9 @@ func (s State) applyPurchase(move CardMove) (State, bool, error) {
 		return s, false, nil
 	}
 
+	paid := hand.Gems
 	hand = hand.Purchase(move.Card)
-	bonuses := hand.BonusesEarned(s.Board.Bonuses)
-	hand.Bonus = append(hand.Bonus, bonuses...)
-	s.Board = s.Board.RemoveBonuses(bonuses)
+	s.Board.Gems = s.Board.Gems.Add(paid.Sub(hand.Gems))
 	if onBoard {
 		s.Board = s.Board.RemoveCard(move.Card)
 	}
@@ -116,10 +216,31 @@ func (s State) applyReserve(move CardMove) (State, bool, error) {
 	}
 	s.Board = s.Board.RemoveCard(move.Card)
 	hand = hand.Reserve(move.Card)
+	// reserving still works once the bank is out of wilds, it just pays nothing
+	if s.Board.Gems.Wild > 0 {
+		hand.Gems = hand.Gems.AddGem(items.GemWild, 1)
+		s.Board.Gems = s.Board.Gems.AddGem(items.GemWild, -1)
+	}
 	s = s.setCurrentPlayerHand(hand)
 	return s, true, nil
 }
 
+// Phase names the step of the turn and the kinds of move it takes
+func (s State) Phase() v1alpha1.Phase {
+	pid, _ := s.CurrentPlayer()
+	phase := v1alpha1.Phase{Name: string(s.Step), Actor: pid}
+	switch s.Step {
+	case StepReturn:
+		phase.Moves = []string{MoveKindReturn}
+	case StepNoble:
+		phase.Moves = []string{MoveKindNoble}
+	default:
+		phase.Name = string(StepTake)
+		phase.Moves = []string{MoveKindCollect, MoveKindPurchase, MoveKindReserve, MoveKindPass}
+	}
+	return phase
+}
+
 func (s State) CurrentPlayer() (uuid.UUID, error) {
 	player, err := s.GetCurrentPlayer()
 	if err != nil {
@@ -139,6 +260,7 @@ func (s State) GetCurrentPlayer() (Player, error) {
 func (s State) setCurrentPlayer(p Player) State {
 	idx := s.Turn.CurrentPlayer()
 	if len(s.Players) > idx {
+		s.Players = common.CloneSlice(s.Players)
 		s.Players[idx] = p
 	}
 	return s
@@ -147,6 +269,7 @@ func (s State) setCurrentPlayer(p Player) State {
 func (s State) setCurrentPlayerHand(h items.Hand) State {
 	idx := s.Turn.CurrentPlayer()
 	if len(s.Players) > idx {
+		s.Players = common.CloneSlice(s.Players)
 		s.Players[idx].Hand = h
 	}
 	return s
@@ -156,19 +279,90 @@ func (s State) IsDone() bool {
 	return len(s.Winners()) > 0
 }
 
+// Winners are the top of the standings once someone reaches the victory
+// points, fewer cards wins a tie. The last player left wins outright
 func (s State) Winners() []uuid.UUID {
-	maxP := 0
-	var max []uuid.UUID
-	for _, p := range s.Players {
-		pts := p.Hand.Points()
-		if pts >= s.Config.VictoryPoints {
-			if maxP < pts {
-				max = []uuid.UUID{p.ID}
-				maxP = pts
-			} else if maxP == pts {
-				max = append(max, p.ID)
-			}
+	scores, _ := s.Scores()
+	in := make([]v1alpha1.Score, 0, len(scores))
+	for i := range scores {
+		if !s.Turn.IsOut(i) {
+			in = append(in, scores[i])
+		}
+	}
+	if len(scores) > 1 && len(in) == 1 {
+		return []uuid.UUID{in[0].Player}
+	}
+	var winners []uuid.UUID
+	for _, standing := range v1alpha1.Rank(in) {
+		if standing.Place > 1 || standing.Points < s.Config.VictoryPoints {
+			break
+		}
+		winners = append(winners, standing.Player)
+	}
+	return winners
+}
+
+// Scores splits points into cards and nobles, fewer cards breaks ties
+func (s State) Scores() ([]v1alpha1.Score, error) {
+	scores := make([]v1alpha1.Score, len(s.Players))
+	for i, p := range s.Players {
+		scores[i] = v1alpha1.Score{
+			Player: p.ID,
+			Points: p.Hand.Points(),
+			Breakdown: map[string]int{
+				ScoreCards:  p.Hand.CardPoints(),
+				ScoreNobles: p.Hand.BonusPoints(),
+			},
+			TieBreak: []int{-len(p.Hand.Cards)},
+		}
+	}
+	return scores, nil
+}
+
+// Resign takes the player out, their turns are skipped from now on
+// StartAt hands the first turn to the seat
+func (s State) StartAt(seat int) (v1alpha1.StateData, error) {
+	if seat < 0 || seat >= len(s.Players) {
+		return nil, fmt.Errorf("Invalid start seat %d", seat)
+	}
+	s.Turn = common.NewTurnCounter(len(s.Players), seat)
+	return s, nil
+}
+
+func (s State) Resign(player uuid.UUID) (v1alpha1.StateData, error) {
+	for i, p := range s.Players {
+		if !p.ID.Equal(player) {
+			continue
+		}
+		s.Turn = s.Turn.Eliminate(i)
+		if s.Turn.CurrentPlayer() == i {
+			s.Step = StepTake
+			s.Turn = s.Turn.Advance()
 		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("Player %s is not in the game", player)
+}
+
+// Eliminated lists the players that resigned in the order they left
+func (s State) Eliminated() []uuid.UUID {
+	out := make([]uuid.UUID, 0, len(s.Turn.Out))
+	for _, idx := range s.Turn.Out {
+		if idx < len(s.Players) {
+			out = append(out, s.Players[idx].ID)
+		}
+	}
+	return out
+}
+
+// CanTakeBack refuses to go back past a card being dealt, everyone has seen
+// what came off the pile
+func (s State) CanTakeBack(to v1alpha1.StateData) bool {
+	prev, ok := to.(State)
+	if !ok {
+		return false
 	}
-	return max
+	return len(s.Board.LevelOne.Pile) == len(prev.Board.LevelOne.Pile) &&
+		len(s.Board.LevelTwo.Pile) == len(prev.Board.LevelTwo.Pile) &&
+		len(s.Board.LevelThree.Pile) == len(prev.Board.LevelThree.Pile)
 }
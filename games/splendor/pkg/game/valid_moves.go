@@ -6,6 +6,12 @@ import (
 )
 
 func (s State) ValidMoves() ([]v1alpha1.Move, error) {
+	switch s.Step {
+	case StepReturn:
+		return MoveSliceToMoveSlice(s.ValidReturnMoves()), nil
+	case StepNoble:
+		return MoveSliceToMoveSlice(s.ValidNobleMoves()), nil
+	}
 	moves := s.ValidCollectMoves()
 	moves = append(moves, s.ValidPurchaseMoves()...)
 	moves = append(moves, s.ValidReserveMoves()...)
@@ -18,45 +24,54 @@ func (s State) ValidCollectMoves() []*Move {
 
 	gems := s.Board.Gems.ToMap()
 	delete(gems, items.GemWild)
-	sets := allSetsOf3(gems.Keys())
-
-	for _, set := range sets {
+	available := []items.Gem{}
+	for _, k := range gems.Keys() {
+		if gems[k] > 0 {
+			available = append(available, k)
+		}
+	}
+	// with fewer than 3 colors left take one of each that remains
+	n := 3
+	if len(available) < n {
+		n = len(available)
+	}
+	for _, set := range allSetsOfN(n, available) {
 		moves = append(moves, &Move{Collect: &CollectMove{Take: set}})
 	}
 
-	for k, v := range gems {
-		if v >= items.MinGemsToTakeTwo {
+	for _, k := range gems.Keys() {
+		if gems[k] >= items.MinGemsToTakeTwo {
 			count := items.GemMap{k: 2}
 			moves = append(moves, &Move{Collect: &CollectMove{Take: count.ToCount()}})
 		}
 	}
-	takeret := make([]*Move, 0, len(moves))
+	return moves
+}
+
+func (s State) ValidReturnMoves() []*Move {
+	moves := []*Move{}
 	player, err := s.GetCurrentPlayer()
 	if err != nil {
-		// todo  handle error
 		return moves
 	}
 	hand := player.Hand
-	total := hand.Gems.Total()
-	gemSlice := hand.Gems.ToSlice()
-	for i, move := range moves {
-		count := move.Collect.Take.Total()
-		if count+total > items.MaxGems {
-			n := count + total - items.MaxGems
-			sets := allSetsOfN(n, gemSlice)
-			for _, set := range sets {
-				takeret = append(takeret, &Move{
-					Collect: &CollectMove{
-						Take:   move.Collect.Take,
-						Return: set,
-					},
-				})
-			}
-		} else {
-			takeret = append(takeret, moves[i])
-		}
+	n := hand.Gems.Total() - items.MaxGems
+	for _, set := range allSetsOfN(n, hand.Gems.ToSlice()) {
+		moves = append(moves, &Move{Return: &ReturnMove{Gems: set}})
 	}
-	return takeret
+	return moves
+}
+
+func (s State) ValidNobleMoves() []*Move {
+	moves := []*Move{}
+	player, err := s.GetCurrentPlayer()
+	if err != nil {
+		return moves
+	}
+	for _, bonus := range player.Hand.BonusesEarned(s.Board.Bonuses) {
+		moves = append(moves, &Move{Noble: &NobleMove{Bonus: bonus}})
+	}
+	return moves
 }
 
 func (s State) ValidPurchaseMoves() []*Move {
@@ -86,7 +101,7 @@ func (s State) ValidReserveMoves() []*Move {
 		return moves
 	}
 	hand := player.Hand
-	if !hand.CanReserve() || s.Board.Gems.Wild <= 0 {
+	if !hand.CanReserve() {
 		return moves
 	}
 	for _, card := range s.Board.AvailableCards() {
@@ -98,26 +113,38 @@ func (s State) ValidReserveMoves() []*Move {
 func allSetsOfN(n int, gems []items.Gem) []items.GemCount {
 	switch n {
 	case 1:
-		return allSetsOf1(gems)
+		return uniqueSets(allSetsOf1(gems))
 	case 2:
-		return allSetsOf2(gems)
+		return uniqueSets(allSetsOf2(gems))
 	case 3:
-		return allSetsOf3(gems)
+		return uniqueSets(allSetsOf3(gems))
 	}
 	return nil
 }
 
+func uniqueSets(sets []items.GemCount) []items.GemCount {
+	seen := make(map[items.GemCount]bool, len(sets))
+	ret := make([]items.GemCount, 0, len(sets))
+	for _, set := range sets {
+		if seen[set] {
+			continue
+		}
+		seen[set] = true
+		ret = append(ret, set)
+	}
+	return ret
+}
+
 func allSetsOf3(gems []items.Gem) []items.GemCount {
 	sets := make([]items.GemCount, 0)
 	for i := 0; i < len(gems); i++ {
 		for j := i + 1; j < len(gems); j++ {
 			for k := j + 1; k < len(gems); k++ {
-				count := items.GemMap{
-					gems[i]: 1,
-					gems[j]: 1,
-					gems[k]: 1,
-				}
-				sets = append(sets, count.ToCount())
+				count := items.GemCount{}
+				count = count.AddGem(gems[i], 1)
+				count = count.AddGem(gems[j], 1)
+				count = count.AddGem(gems[k], 1)
+				sets = append(sets, count)
 			}
 		}
 	}
@@ -128,11 +155,10 @@ func allSetsOf2(gems []items.Gem) []items.GemCount {
 	sets := make([]items.GemCount, 0)
 	for i := 0; i < len(gems); i++ {
 		for j := i + 1; j < len(gems); j++ {
-			count := items.GemMap{
-				gems[i]: 1,
-				gems[j]: 1,
-			}
-			sets = append(sets, count.ToCount())
+			count := items.GemCount{}
+			count = count.AddGem(gems[i], 1)
+			count = count.AddGem(gems[j], 1)
+			sets = append(sets, count)
 		}
 	}
 	return sets
@@ -140,17 +166,10 @@ func allSetsOf2(gems []items.Gem) []items.GemCount {
 
 func allSetsOf1(gems []items.Gem) []items.GemCount {
 
-	m := make(map[items.Gem]items.GemCount)
+	sets := make([]items.GemCount, 0, len(gems))
 	for _, gem := range gems {
 		c := items.GemCount{}
-		c.AddGem(gem, 1)
-		m[gem] = c
-	}
-	sets := make([]items.GemCount, 0, len(m))
-
-	for _, gc := range m {
-		sets = append(sets, gc)
+		sets = append(sets, c.AddGem(gem, 1))
 	}
 	return sets
-
 }
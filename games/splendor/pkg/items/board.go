@@ -1,5 +1,7 @@
 package items
 
+import common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+
 type Board struct {
 	Gems       GemCount
 	LevelOne   Deck
@@ -8,15 +10,34 @@ type Board struct {
 	Bonuses    []Bonus
 }
 
-func NewBoard() Board {
+func NewBoard(r common.Random) Board {
 	return Board{
 		Gems:       Gems(),
-		LevelOne:   NewDeck(LevelOneCards()).Deal(4),
-		LevelTwo:   NewDeck(LevelTwoCards()).Deal(4),
-		LevelThree: NewDeck(LevelThreeCards()).Deal(4),
-		Bonuses:    RandomBonuses(3),
+		LevelOne:   NewDeck(r, LevelOneCards()).Deal(4),
+		LevelTwo:   NewDeck(r, LevelTwoCards()).Deal(4),
+		LevelThree: NewDeck(r, LevelThreeCards()).Deal(4),
+		Bonuses:    RandomBonuses(r, 3),
+	}
+
+}
+
+func (b Board) Clone() Board {
+	return Board{
+		Gems:       b.Gems,
+		LevelOne:   b.LevelOne.Clone(),
+		LevelTwo:   b.LevelTwo.Clone(),
+		LevelThree: b.LevelThree.Clone(),
+		Bonuses:    CloneBonuses(b.Bonuses),
 	}
+}
 
+// View is the board as the players see it, without the piles
+func (b Board) View() Board {
+	b = b.Clone()
+	b.LevelOne = b.LevelOne.View()
+	b.LevelTwo = b.LevelTwo.View()
+	b.LevelThree = b.LevelThree.View()
+	return b
 }
 
 func (b Board) IsCardOnBoard(card Card) bool {
@@ -1,6 +1,6 @@
 package items
 
-import "math/rand"
+import common "github.com/mat285/boardgames/pkg/common/v1alpha1"
 
 type Bonus struct {
 	Value int
@@ -17,6 +17,9 @@ func ContainsBonus(bs []Bonus, bonus Bonus) bool {
 }
 
 func CloneBonuses(bs []Bonus) []Bonus {
+	if bs == nil {
+		return nil
+	}
 	ret := make([]Bonus, len(bs))
 	for i := range bs {
 		ret[i] = bs[i]
@@ -24,31 +27,16 @@ func CloneBonuses(bs []Bonus) []Bonus {
 	return ret
 }
 
-func RandomBonuses(n int) []Bonus {
+func RandomBonuses(r common.Random, n int) []Bonus {
 	all := Bonuses()
 	if n <= 0 {
 		return []Bonus{}
 	}
+	r.Shuffle(len(all), func(i, j int) { all[i], all[j] = all[j], all[i] })
 	if n >= len(all) {
 		return all
 	}
-	ret := make([]Bonus, n)
-	for i := 0; i < n; i++ {
-		ret[i], all = RandomBonus(all)
-	}
-	return ret
-}
-
-func RandomBonus(bs []Bonus) (Bonus, []Bonus) {
-	if len(bs) == 0 {
-		return Bonus{}, []Bonus{}
-	}
-	i := rand.Intn(len(bs))
-	bonus := bs[i]
-	last := len(bs) - 1
-
-	bs[i] = bs[last]
-	return bonus, bs[:last]
+	return all[:n]
 }
 
 func Bonuses() []Bonus {
@@ -29,6 +29,9 @@ func ContainsCard(cards []Card, card Card) bool {
 }
 
 func CloneCards(cards []Card) []Card {
+	if cards == nil {
+		return nil
+	}
 	ret := make([]Card, len(cards))
 	for i := range cards {
 		ret[i] = cards[i]
@@ -1,31 +1,51 @@
 package items
 
-import "math/rand"
+import common "github.com/mat285/boardgames/pkg/common/v1alpha1"
 
 type Deck struct {
 	Shown []Card
-	Pile  []Card
+	Pile  []Card `json:",omitempty"`
+	// Left counts the pile in a player's view, where its cards are left out
+	Left int `json:",omitempty"`
 }
 
-func NewDeck(cards []Card) Deck {
+// NewDeck shuffles the cards into the pile, everything dealt after comes
+// off the top so the deal is fixed by the random source
+func NewDeck(r common.Random, cards []Card) Deck {
+	pile := CloneCards(cards)
+	r.Shuffle(len(pile), func(i, j int) { pile[i], pile[j] = pile[j], pile[i] })
 	return Deck{
 		Shown: make([]Card, 0),
-		Pile:  cards,
+		Pile:  pile,
 	}
 }
 
-func (d Deck) Deal(num int) Deck {
-	pile := d.Pile
-	shown := CloneCards(d.Shown)
+func (d Deck) Clone() Deck {
+	return Deck{
+		Shown: CloneCards(d.Shown),
+		Pile:  CloneCards(d.Pile),
+		Left:  d.Left,
+	}
+}
 
-	for i := 0; i < num; i++ {
-		var card Card
-		card, pile = PickRandomCard(pile)
-		shown = append(shown, card)
+// View hides the pile, only how many cards are left in it shows
+func (d Deck) View() Deck {
+	return Deck{
+		Shown: CloneCards(d.Shown),
+		Left:  len(d.Pile),
 	}
+}
+
+func (d Deck) Deal(num int) Deck {
+	if num > len(d.Pile) {
+		num = len(d.Pile)
+	}
+	shown := make([]Card, 0, len(d.Shown)+num)
+	shown = append(shown, d.Shown...)
+	shown = append(shown, d.Pile[:num]...)
 
 	d.Shown = shown
-	d.Pile = pile
+	d.Pile = CloneCards(d.Pile[num:])
 	return d
 }
 
@@ -41,15 +61,3 @@ func (d Deck) RemoveAndReplace(card Card) Deck {
 	d.Shown = shown
 	return d.Deal(1)
 }
-
-func PickRandomCard(cards []Card) (Card, []Card) {
-	if len(cards) == 0 {
-		return Card{}, []Card{}
-	}
-	i := rand.Intn(len(cards))
-	card := cards[i]
-	last := len(cards) - 1
-
-	cards[i] = cards[last]
-	return card, cards[:last]
-}
@@ -0,0 +1,25 @@
+package items_test
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/mat285/boardgames/games/splendor/pkg/items"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+)
+
+func TestDealLeavesPile(t *testing.T) {
+	it := assert.New(t)
+
+	d := items.NewDeck(common.NewRandom(1), items.LevelOneCards())
+	pile := items.CloneCards(d.Pile)
+
+	dealt := d.Deal(4)
+	it.Len(dealt.Shown, 4)
+	it.Len(dealt.Pile, len(pile)-4)
+	it.Equal(pile, d.Pile)
+
+	dealt.RemoveAndReplace(dealt.Shown[0])
+	it.Len(dealt.Shown, 4)
+	it.Len(dealt.Pile, len(pile)-4)
+}
@@ -1,5 +1,7 @@
 package items
 
+import "sort"
+
 type GemMap map[Gem]int
 
 type GemCount struct {
@@ -44,6 +46,7 @@ func (gc GemCount) ToSlice() []Gem {
 			ret = append(ret, k)
 		}
 	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
 	return ret
 }
 
@@ -127,6 +130,7 @@ func (gm GemMap) Keys() []Gem {
 	for k := range gm {
 		keys = append(keys, k)
 	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
 	return keys
 }
 
@@ -17,16 +17,32 @@ func NewHand() Hand {
 	}
 }
 
+func (h Hand) Clone() Hand {
+	return Hand{
+		Gems:     h.Gems,
+		Cards:    CloneCards(h.Cards),
+		Bonus:    CloneBonuses(h.Bonus),
+		Reserved: CloneCards(h.Reserved),
+	}
+}
+
 func (h Hand) Points() int {
-	points := 0
+	return h.CardPoints() + h.BonusPoints()
+}
 
+func (h Hand) CardPoints() int {
+	points := 0
 	for _, card := range h.Cards {
 		points += card.Value
 	}
+	return points
+}
+
+func (h Hand) BonusPoints() int {
+	points := 0
 	for _, bonus := range h.Bonus {
 		points += bonus.Value
 	}
-
 	return points
 }
 
@@ -59,8 +75,7 @@ func (h Hand) CanReserve() bool {
 }
 
 func (h Hand) Reserve(card Card) Hand {
-	h.Gems = h.Gems.AddGem(GemWild, 1)
-	h.Reserved = append(h.Reserved, card)
+	h.Reserved = append(CloneCards(h.Reserved), card)
 	return h
 }
 
@@ -112,7 +127,7 @@ func (h Hand) Purchase(card Card) Hand {
 	gems := h.Gems.ToMap()
 
 	for g, c := range price {
-		if c == 0 {
+		if c <= 0 {
 			continue
 		}
 		if gems[g] >= c {
@@ -124,7 +139,7 @@ func (h Hand) Purchase(card Card) Hand {
 		}
 	}
 
-	h.Cards = append(h.Cards, card)
+	h.Cards = append(CloneCards(h.Cards), card)
 
 	return Hand{
 		Gems:     GemCountFromMap(gems),
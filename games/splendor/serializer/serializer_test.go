@@ -0,0 +1,57 @@
+package serializer_test
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/splendor/pkg/game"
+	"github.com/mat285/boardgames/games/splendor/serializer"
+	"github.com/mat285/boardgames/pkg/apiversions"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+	"github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+func TestRoundTrip(t *testing.T) {
+	it := assert.New(t)
+
+	state := game.NewState(common.NewRandom(1), []game.Player{game.NewPlayer(uuid.V4()), game.NewPlayer(uuid.V4())}, game.StandardConfig())
+	moves, err := state.ValidMoves()
+	it.Nil(err)
+	it.NotEmpty(moves)
+
+	for _, s := range []serializer.Serializer{{}, {Codec: v1alpha1.GobCodec{}}} {
+		so, err := s.SerializeState(state)
+		it.Nil(err)
+		it.Equal(apiversions.Latest, so.Version)
+		it.Equal(s.Codec == nil, so.Codec == v1alpha1.CodecJSON)
+
+		out, err := s.DeserializeState(so)
+		it.Nil(err)
+		typed, ok := out.(game.State)
+		it.True(ok)
+		it.Equal(state.Board.Gems, typed.Board.Gems)
+		it.Equal(state.Players[1].ID, typed.Players[1].ID)
+
+		mo, err := s.SerializeMove(moves[0])
+		it.Nil(err)
+		move, err := s.DeserializeMove(mo)
+		it.Nil(err)
+		_, ok = move.(*game.Move)
+		it.True(ok)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	it := assert.New(t)
+
+	s := serializer.Serializer{}
+	_, err := s.DeserializeState(&v1alpha1.SerializedObject{ID: uuid.V4(), Data: []byte("{}")})
+	it.NotNil(err)
+
+	so, err := s.SerializeState(game.NewState(common.NewRandom(1), nil, game.StandardConfig()))
+	it.Nil(err)
+	so.Version = "v0beta1"
+	_, err = s.DeserializeState(so)
+	it.NotNil(err)
+}
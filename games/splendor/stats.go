@@ -0,0 +1,109 @@
+package splendor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/splendor/pkg/game"
+	"github.com/mat285/boardgames/games/splendor/pkg/items"
+	"github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+const (
+	// StatAverageTurnsToWin is how many turns the player takes in the games
+	// they win
+	StatAverageTurnsToWin = "average-turns-to-win"
+	// StatPurchased counts the cards the player bought by gem
+	StatPurchased = "purchased"
+	// StatMostPurchasedGem is the gem of the cards the player buys most
+	StatMostPurchasedGem = "most-purchased-gem"
+)
+
+var (
+	_ v1alpha1.StatsGame = new(Game)
+)
+
+// Stats works out the player's splendor stats from the moves and the final
+// state of their games
+func (g Game) Stats(player uuid.UUID, records []v1alpha1.Record) (v1alpha1.Stats, error) {
+	wins, winningTurns := 0, 0
+	purchased := make(map[items.Gem]int)
+	for _, record := range records {
+		if record.State == nil {
+			continue
+		}
+		untyped, err := g.DeserializeState(record.State)
+		if err != nil {
+			return nil, err
+		}
+		state, ok := untyped.(game.State)
+		if !ok {
+			return nil, fmt.Errorf("Invalid State for Game")
+		}
+		for _, p := range state.Players {
+			if !p.ID.Equal(player) {
+				continue
+			}
+			for _, card := range p.Hand.Cards {
+				purchased[card.Type]++
+			}
+		}
+
+		won := false
+		for _, standing := range record.Standings {
+			won = won || (standing.Player.Equal(player) && standing.Place == 1)
+		}
+		if !won {
+			continue
+		}
+		turns, err := g.turns(player, record)
+		if err != nil {
+			return nil, err
+		}
+		wins++
+		winningTurns += turns
+	}
+
+	stats := v1alpha1.Stats{StatPurchased: purchased}
+	if wins > 0 {
+		stats[StatAverageTurnsToWin] = float64(winningTurns) / float64(wins)
+	}
+	gems := make([]items.Gem, 0, len(purchased))
+	for gem := range purchased {
+		gems = append(gems, gem)
+	}
+	sort.Slice(gems, func(i, j int) bool {
+		if purchased[gems[i]] != purchased[gems[j]] {
+			return purchased[gems[i]] > purchased[gems[j]]
+		}
+		return gems[i] < gems[j]
+	})
+	if len(gems) > 0 {
+		stats[StatMostPurchasedGem] = gems[0]
+	}
+	return stats, nil
+}
+
+// turns counts the player's turns in the game, returning gems and picking
+// a noble are part of the turn they follow
+func (g Game) turns(player uuid.UUID, record v1alpha1.Record) (int, error) {
+	turns := 0
+	for _, recorded := range record.Played() {
+		if !recorded.Player.Equal(player) {
+			continue
+		}
+		untyped, err := g.DeserializeMove(recorded.Move)
+		if err != nil {
+			return 0, err
+		}
+		move, ok := untyped.(*game.Move)
+		if !ok {
+			return 0, fmt.Errorf("Invalid Move for Game")
+		}
+		if move.Return == nil && move.Noble == nil {
+			turns++
+		}
+	}
+	return turns, nil
+}
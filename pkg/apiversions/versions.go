@@ -2,4 +2,33 @@ package apiversions
 
 const (
 	V1Alpha1 = "v1alpha1"
+	// V1Alpha2 split turns into phases, splendor gems returned over the
+	// limit became a move of their own
+	V1Alpha2 = "v1alpha2"
+
+	// Latest is the version new packets and persisted objects are written with
+	Latest = V1Alpha2
 )
+
+// Versions lists every version oldest first
+var Versions = []string{V1Alpha1, V1Alpha2}
+
+// OrDefault treats objects written before versions were recorded as v1alpha1
+func OrDefault(version string) string {
+	if len(version) == 0 {
+		return V1Alpha1
+	}
+	return version
+}
+
+// Next returns the version after the given one, false for the latest or
+// one that isn't known
+func Next(version string) (string, bool) {
+	version = OrDefault(version)
+	for i := range Versions[:len(Versions)-1] {
+		if Versions[i] == version {
+			return Versions[i+1], true
+		}
+	}
+	return "", false
+}
@@ -22,6 +22,9 @@ type Node struct {
 	Children []*Node
 
 	Tree *Tree
+
+	// Weight is how likely the node is when its parent is a chance node
+	Weight int
 }
 
 func (n *Node) AddChild(children ...*Node) {
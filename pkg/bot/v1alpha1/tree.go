@@ -50,6 +50,9 @@ func (t *Tree) ExpandLeaves() error {
 }
 
 func (t *Tree) ExpandNode(node *Node) error {
+	if chance, ok := v1alpha1.IsChance(node.State); ok {
+		return t.expandChance(node, chance)
+	}
 	possible, err := node.State.ValidMoves()
 	if err != nil {
 		return err
@@ -81,6 +84,39 @@ func (t *Tree) ExpandNode(node *Node) error {
 	return nil
 }
 
+// expandChance adds every outcome of a chance node, they are never filtered
+// since the score needs the whole distribution
+func (t *Tree) expandChance(node *Node, chance v1alpha1.Chance) error {
+	outcomes, err := chance.ChanceOutcomes()
+	if err != nil {
+		return err
+	}
+	children := make([]*Node, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		res, err := outcome.Move.Apply(node.State)
+		if err != nil {
+			return err
+		}
+		if !res.Valid {
+			continue
+		}
+		child := &Node{
+			State:  res.State,
+			Move:   outcome.Move,
+			Weight: outcome.Weight,
+			Score:  t.Heuristic(res.State),
+			Parent: node,
+			Tree:   node.Tree,
+		}
+		children = append(children, child)
+		node.Tree.Leaves[child] = true
+		t.Size++
+	}
+	node.AddChild(children...)
+	t.Depth++
+	return nil
+}
+
 func (t *Tree) BackpropogateScores(id uuid.UUID) error {
 	candidates := make(map[*Node]bool)
 	next := make(map[*Node]bool)
@@ -109,7 +145,9 @@ func (t *Tree) BackpropogateScores(id uuid.UUID) error {
 			if err != nil {
 				return err
 			}
-			if us {
+			if _, chance := v1alpha1.IsChance(node.State); chance {
+				node.Score = weightedNodeScore(node.Children)
+			} else if us {
 				node.Score = maxNode(node.Children).Score
 			} else {
 				node.Score = averageNodeScore(node.Children)
@@ -136,6 +174,20 @@ func averageNodeScore(nodes []*Node) int {
 	return sum / len(nodes)
 }
 
+// weightedNodeScore is the expected score of the outcomes of a chance node
+func weightedNodeScore(nodes []*Node) int {
+	total := 0
+	sum := 0
+	for _, node := range nodes {
+		total += node.Weight
+		sum += node.Score * node.Weight
+	}
+	if total == 0 {
+		return averageNodeScore(nodes)
+	}
+	return sum / total
+}
+
 func maxNode(nodes []*Node) *Node {
 	if len(nodes) == 0 {
 		return nil
@@ -2,15 +2,20 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
+	"time"
 
 	"github.com/blend/go-sdk/uuid"
 	connection "github.com/mat285/boardgames/pkg/connection/v1alpha1"
 	"github.com/mat285/boardgames/pkg/game/v1alpha1"
 	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	notify "github.com/mat285/boardgames/pkg/notify/v1alpha1"
 	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
 	server "github.com/mat285/boardgames/server/http/v1alpha1"
 )
 
@@ -56,7 +61,7 @@ func (c *Client) Send(ctx context.Context, packet wire.Packet) error {
 }
 
 func (c *Client) Login(ctx context.Context) error {
-	body := game.Player{
+	login := game.Player{
 		Username: c.Username,
 	}
 	req, err := c.NewJSONRequest(
@@ -64,11 +69,12 @@ func (c *Client) Login(ctx context.Context) error {
 		http.MethodPost,
 		"/api/v1alpha1/user/login",
 		nil,
-		body,
+		login,
 	)
 	if err != nil {
 		return err
 	}
+	var body game.Player
 	err = c.JSON(ctx, req, &body)
 	if err != nil {
 		return err
@@ -93,6 +99,19 @@ func (c *Client) GetUserGames(ctx context.Context) ([]server.UserGame, error) {
 }
 
 func (c *Client) NewGame(ctx context.Context, name string, config interface{}) (uuid.UUID, error) {
+	return c.newGame(ctx, name, config)
+}
+
+// NewSeededGame creates a casual game that deals the same way every time
+// for the same seed and seating, it is never rated
+func (c *Client) NewSeededGame(ctx context.Context, name string, config interface{}, seed int64) (uuid.UUID, error) {
+	return c.newGame(ctx, name, config,
+		OptRequestQuery(server.QueryKeySeed, strconv.FormatInt(seed, 10)),
+		OptRequestQuery(server.QueryKeyCasual, "true"),
+	)
+}
+
+func (c *Client) newGame(ctx context.Context, name string, config interface{}, opts ...RequestOption) (uuid.UUID, error) {
 	req, err := c.NewJSONRequest(
 		ctx,
 		http.MethodPost,
@@ -101,6 +120,7 @@ func (c *Client) NewGame(ctx context.Context, name string, config interface{}) (
 			":name": name,
 		},
 		config,
+		opts...,
 	)
 	if err != nil {
 		return nil, err
@@ -126,6 +146,25 @@ func (c *Client) Join(ctx context.Context, id uuid.UUID) error {
 	return c.Do(ctx, req)
 }
 
+// SetTeam puts the user on a team in the lobby of a game
+func (c *Client) SetTeam(ctx context.Context, id uuid.UUID, team string) error {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/game/:id/team",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+		OptRequestQuery(server.QueryKeyTeam, team),
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.Do(ctx, req)
+}
+
 func (c *Client) Start(ctx context.Context, id uuid.UUID) error {
 	req, err := c.NewJSONRequest(
 		ctx,
@@ -165,6 +204,24 @@ func (c *Client) GetState(ctx context.Context, id uuid.UUID) (*wire.Packet, erro
 	return &res, c.JSON(ctx, req, &res)
 }
 
+// GetRecord fetches the record of a finished game to verify it
+func (c *Client) GetRecord(ctx context.Context, id uuid.UUID) (*game.Record, error) {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/game/:id/record",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res game.Record
+	return &res, c.JSON(ctx, req, &res)
+}
+
 func (c *Client) SendPacket(ctx context.Context, id, player uuid.UUID, move wire.Packet) (*wire.Packet, error) {
 	req, err := c.NewJSONRequest(
 		ctx,
@@ -186,3 +243,593 @@ func (c *Client) webSocketsAddress(user string) string {
 	url := fmt.Sprintf("ws://%s", path.Join(c.Config.HostPort(), "/api/v1alpha1/websockets/"+user))
 	return url
 }
+
+// Rematch sets up a new game with the same players once the game is over,
+// asking again returns the same rematch
+func (c *Client) Rematch(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/game/:id/rematch",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res uuid.UUID
+	return res, c.JSON(ctx, req, &res)
+}
+
+// NewSeries makes the game the first of a series, e.g. best-of 3 or
+// first-to 50
+func (c *Client) NewSeries(ctx context.Context, id uuid.UUID, format game.SeriesFormat, target int) (uuid.UUID, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/game/:id/series",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+		OptRequestQuery(server.QueryKeyFormat, string(format)),
+		OptRequestQuery(server.QueryKeyTarget, strconv.Itoa(target)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res uuid.UUID
+	return res, c.JSON(ctx, req, &res)
+}
+
+func (c *Client) GetSeries(ctx context.Context, id uuid.UUID) (*server.SeriesStatus, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/series/:id",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res server.SeriesStatus
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// NewPrivateGame creates a game that stays out of the lobby, others join it
+// by its id
+func (c *Client) NewPrivateGame(ctx context.Context, name string, config interface{}) (uuid.UUID, error) {
+	return c.newGame(ctx, name, config, OptRequestQuery(server.QueryKeyPrivate, "true"))
+}
+
+// GetLobby lists a page of the public games that match the query
+func (c *Client) GetLobby(ctx context.Context, query api.LobbyQuery) (*api.LobbyResponse, error) {
+	var opts []RequestOption
+	for key, value := range map[string]string{
+		server.QueryKeyGame:    query.Game,
+		server.QueryKeyVariant: query.Variant,
+		server.QueryKeyStatus:  string(query.Status),
+		server.QueryKeyHost:    query.Host,
+	} {
+		if len(value) > 0 {
+			opts = append(opts, OptRequestQuery(key, value))
+		}
+	}
+	for key, value := range map[string]int{
+		server.QueryKeySeats:  query.Seats,
+		server.QueryKeyOffset: query.Offset,
+		server.QueryKeyLimit:  query.Limit,
+	} {
+		if value > 0 {
+			opts = append(opts, OptRequestQuery(key, strconv.Itoa(value)))
+		}
+	}
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/lobby",
+		nil,
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.LobbyResponse
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// SubscribeLobby starts or stops lobby events coming in over the websocket
+func (c *Client) SubscribeLobby(ctx context.Context, subscribe bool) error {
+	t := api.PacketTypeLobbySubscribe
+	if !subscribe {
+		t = api.PacketTypeLobbyUnsubscribe
+	}
+	return c.Send(ctx, wire.NewPacket(wire.OptPacketType(t), wire.OptPacketOrigin(c.UserID)))
+}
+
+// JoinWithPassword joins a table the host locked with a password
+func (c *Client) JoinWithPassword(ctx context.Context, id uuid.UUID, password string) error {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/game/:id/join",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		api.JoinRequest{Password: password},
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.Do(ctx, req)
+}
+
+// JoinCode joins the game the invite code is for and returns its id, the
+// password is only needed for tables that have one
+func (c *Client) JoinCode(ctx context.Context, code, password string) (uuid.UUID, error) {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/join/:code",
+		map[string]string{
+			":code": code,
+		},
+		api.JoinRequest{Password: password},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var id uuid.UUID
+	return id, c.JSON(ctx, req, &id)
+}
+
+func (c *Client) GetInvite(ctx context.Context, id uuid.UUID) (*api.Invite, error) {
+	return c.invite(ctx, http.MethodGet, id)
+}
+
+// RotateInvite gives the game a new invite code, only the host can
+func (c *Client) RotateInvite(ctx context.Context, id uuid.UUID) (*api.Invite, error) {
+	return c.invite(ctx, http.MethodPost, id)
+}
+
+// DisableInvite turns the invite code off, only the host can
+func (c *Client) DisableInvite(ctx context.Context, id uuid.UUID) (*api.Invite, error) {
+	return c.invite(ctx, http.MethodDelete, id)
+}
+
+func (c *Client) invite(ctx context.Context, method string, id uuid.UUID) (*api.Invite, error) {
+	req, err := c.NewRequest(
+		ctx,
+		method,
+		"/api/v1alpha1/game/:id/invite",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Invite
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// SetPassword locks the table behind a password, empty takes it off. Only
+// the host can
+func (c *Client) SetPassword(ctx context.Context, id uuid.UUID, password string) (*api.Invite, error) {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/game/:id/password",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		api.JoinRequest{Password: password},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Invite
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// Queue enters matchmaking for the game, a match found is pushed over the
+// websocket and has to be confirmed
+func (c *Client) Queue(ctx context.Context, name string, queue api.QueueRequest) (*api.Ticket, error) {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/matchmaking/:name",
+		map[string]string{
+			":name": name,
+		},
+		queue,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Ticket
+	return &res, c.JSON(ctx, req, &res)
+}
+
+func (c *Client) GetTicket(ctx context.Context) (*api.Ticket, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/matchmaking",
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Ticket
+	return &res, c.JSON(ctx, req, &res)
+}
+
+func (c *Client) LeaveQueue(ctx context.Context) error {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodDelete,
+		"/api/v1alpha1/matchmaking",
+		nil,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	return c.Do(ctx, req)
+}
+
+// ConfirmMatch accepts a match the queue found, Engine is set on the match
+// once everyone has
+func (c *Client) ConfirmMatch(ctx context.Context, id uuid.UUID) (*api.Match, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/match/:id/confirm",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Match
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// NewTournament sets up a tournament of the game organized by the client
+func (c *Client) NewTournament(ctx context.Context, name string, tournament api.NewTournamentRequest) (*api.TournamentStatus, error) {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/tournaments/:name/new",
+		map[string]string{
+			":name": name,
+		},
+		tournament,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.TournamentStatus
+	return &res, c.JSON(ctx, req, &res)
+}
+
+func (c *Client) GetTournament(ctx context.Context, id uuid.UUID) (*api.TournamentStatus, error) {
+	return c.tournament(ctx, http.MethodGet, "/api/v1alpha1/tournament/:id", id)
+}
+
+// StartTournament pairs the first round and starts its games
+func (c *Client) StartTournament(ctx context.Context, id uuid.UUID) (*api.TournamentStatus, error) {
+	return c.tournament(ctx, http.MethodPost, "/api/v1alpha1/tournament/:id/start", id)
+}
+
+func (c *Client) tournament(ctx context.Context, method, route string, id uuid.UUID) (*api.TournamentStatus, error) {
+	req, err := c.NewRequest(
+		ctx,
+		method,
+		route,
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.TournamentStatus
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// SubscribeTournament starts or stops the tournament's updates coming in
+// over the websocket
+func (c *Client) SubscribeTournament(ctx context.Context, id uuid.UUID, subscribe bool) error {
+	t := api.PacketTypeTournamentSubscribe
+	if !subscribe {
+		t = api.PacketTypeTournamentUnsubscribe
+	}
+	body, err := json.Marshal(api.TournamentSubscription{Tournament: id})
+	if err != nil {
+		return err
+	}
+	return c.Send(ctx, wire.NewPacket(wire.OptPacketType(t), wire.OptPacketOrigin(c.UserID), wire.OptPacketPayload(body)))
+}
+
+// NewCasualGame creates a game the server may leave out of the ratings
+func (c *Client) NewCasualGame(ctx context.Context, name string, config interface{}) (uuid.UUID, error) {
+	return c.newGame(ctx, name, config, OptRequestQuery(server.QueryKeyCasual, "true"))
+}
+
+// NewCorrespondenceGame creates a game where each move can take up to the
+// deadline, the server keeps it off to the side between moves
+func (c *Client) NewCorrespondenceGame(ctx context.Context, name string, config interface{}, deadline time.Duration) (uuid.UUID, error) {
+	return c.newGame(ctx, name, config, OptRequestQuery(server.QueryKeyDeadline, deadline.String()))
+}
+
+// Leaderboard returns a page of the game's players, highest rated first
+func (c *Client) Leaderboard(ctx context.Context, name string, offset, limit int, provisional bool) (*api.Leaderboard, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/leaderboard/:game",
+		map[string]string{
+			":game": name,
+		},
+		nil,
+		OptRequestQuery(server.QueryKeyOffset, strconv.Itoa(offset)),
+		OptRequestQuery(server.QueryKeyLimit, strconv.Itoa(limit)),
+		OptRequestQuery(server.QueryKeyProvisional, strconv.FormatBool(provisional)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Leaderboard
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// GetRatings returns the player's ratings with their history
+func (c *Client) GetRatings(ctx context.Context, username string) ([]api.PlayerRating, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/ratings/:username",
+		map[string]string{
+			":username": username,
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res []api.PlayerRating
+	return res, c.JSON(ctx, req, &res)
+}
+
+// GetProfile returns the player's results across the games they finished
+func (c *Client) GetProfile(ctx context.Context, username string) (*api.Profile, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/users/:name/profile",
+		map[string]string{
+			":name": username,
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Profile
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// Spectate watches a public game without taking a seat
+func (c *Client) Spectate(ctx context.Context, id uuid.UUID) error {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/game/:id/spectate",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	return c.Do(ctx, req)
+}
+
+// GetNotifications returns the user's inbox, only what hasn't been read
+// when unread is set
+func (c *Client) GetNotifications(ctx context.Context, unread bool) (*api.Inbox, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/notifications",
+		nil,
+		nil,
+		OptRequestQuery(server.QueryKeyUnread, strconv.FormatBool(unread)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Inbox
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// ReadNotifications marks the notifications read, all of them without ids
+func (c *Client) ReadNotifications(ctx context.Context, ids ...uuid.UUID) error {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/notifications/read",
+		nil,
+		api.ReadNotificationsRequest{IDs: ids},
+	)
+	if err != nil {
+		return err
+	}
+	return c.Do(ctx, req)
+}
+
+func (c *Client) GetNotificationPreferences(ctx context.Context) (*notify.Preferences, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/notifications/preferences",
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res notify.Preferences
+	return &res, c.JSON(ctx, req, &res)
+}
+
+func (c *Client) SetNotificationPreferences(ctx context.Context, prefs notify.Preferences) (*notify.Preferences, error) {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPut,
+		"/api/v1alpha1/notifications/preferences",
+		nil,
+		prefs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res notify.Preferences
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// ConfirmEmail confirms the email in the preferences with the token that was
+// mailed to it, nothing is mailed there before
+func (c *Client) ConfirmEmail(ctx context.Context, token string) (*notify.Preferences, error) {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/notifications/preferences/confirm",
+		nil,
+		api.ConfirmEmailRequest{Token: token},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res notify.Preferences
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// GetFriends lists the user's friends with who is online, and the friend
+// requests either way
+func (c *Client) GetFriends(ctx context.Context) (*api.Friends, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/friends",
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Friends
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// AddFriend sends the user a friend request, or accepts theirs
+func (c *Client) AddFriend(ctx context.Context, username string) error {
+	return c.friend(ctx, http.MethodPost, username)
+}
+
+// RemoveFriend unfriends the user
+func (c *Client) RemoveFriend(ctx context.Context, username string) error {
+	return c.friend(ctx, http.MethodDelete, username)
+}
+
+func (c *Client) friend(ctx context.Context, method, username string) error {
+	req, err := c.NewRequest(
+		ctx,
+		method,
+		"/api/v1alpha1/friends/:username",
+		map[string]string{
+			":username": username,
+		},
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	return c.Do(ctx, req)
+}
+
+// InviteFriend invites the friend to take a seat at the game
+func (c *Client) InviteFriend(ctx context.Context, id uuid.UUID, username string) (*api.Invitation, error) {
+	req, err := c.NewJSONRequest(
+		ctx,
+		http.MethodPost,
+		"/api/v1alpha1/game/:id/invitations",
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		api.InviteFriendRequest{Username: username},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Invitation
+	return &res, c.JSON(ctx, req, &res)
+}
+
+// GetInvitations lists the invitations the user hasn't answered
+func (c *Client) GetInvitations(ctx context.Context) ([]api.Invitation, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodGet,
+		"/api/v1alpha1/invitations",
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res []api.Invitation
+	return res, c.JSON(ctx, req, &res)
+}
+
+// AcceptInvitation joins the game the user was invited to
+func (c *Client) AcceptInvitation(ctx context.Context, id uuid.UUID) (*api.Invitation, error) {
+	return c.answerInvitation(ctx, "/api/v1alpha1/invitations/:id/accept", id)
+}
+
+// DeclineInvitation turns the invitation down
+func (c *Client) DeclineInvitation(ctx context.Context, id uuid.UUID) (*api.Invitation, error) {
+	return c.answerInvitation(ctx, "/api/v1alpha1/invitations/:id/decline", id)
+}
+
+func (c *Client) answerInvitation(ctx context.Context, route string, id uuid.UUID) (*api.Invitation, error) {
+	req, err := c.NewRequest(
+		ctx,
+		http.MethodPost,
+		route,
+		map[string]string{
+			":id": id.ToFullString(),
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var res api.Invitation
+	return &res, c.JSON(ctx, req, &res)
+}
@@ -9,6 +9,15 @@ type Option func(*Client)
 
 type RequestOption func(req *http.Request)
 
+// OptRequestQuery sets a query value on the request
+func OptRequestQuery(key, value string) RequestOption {
+	return func(req *http.Request) {
+		query := req.URL.Query()
+		query.Set(key, value)
+		req.URL.RawQuery = query.Encode()
+	}
+}
+
 func OptUsername(name string) Option {
 	return func(c *Client) {
 		c.Username = name
@@ -0,0 +1,48 @@
+package v1alpha1
+
+// CloneSlice returns a shallow copy of s that shares no backing array with
+// it, nil stays nil
+func CloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	ret := make([]T, len(s))
+	copy(ret, s)
+	return ret
+}
+
+// CloneSliceFunc copies s using clone for every element
+func CloneSliceFunc[T any](s []T, clone func(T) T) []T {
+	if s == nil {
+		return nil
+	}
+	ret := make([]T, len(s))
+	for i := range s {
+		ret[i] = clone(s[i])
+	}
+	return ret
+}
+
+// CloneMap returns a shallow copy of m, nil stays nil
+func CloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[K]V, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
+
+// CloneMapFunc copies m using clone for every value
+func CloneMapFunc[K comparable, V any](m map[K]V, clone func(V) V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[K]V, len(m))
+	for k, v := range m {
+		ret[k] = clone(v)
+	}
+	return ret
+}
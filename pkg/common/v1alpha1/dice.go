@@ -1,20 +1,18 @@
 package v1alpha1
 
-import "math/rand"
-
 type Die interface {
 	Faces() int
 	Value(int) int
 }
 
-func Roll(d Die) int {
-	return d.Value(rand.Intn(d.Faces()))
+func Roll(r Random, d Die) int {
+	return d.Value(r.Intn(d.Faces()))
 }
 
-func Sum(ds ...Die) int {
+func Sum(r Random, ds ...Die) int {
 	s := 0
 	for _, d := range ds {
-		s += Roll(d)
+		s += Roll(r, d)
 	}
 	return s
 }
@@ -0,0 +1,38 @@
+package v1alpha1
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Random is where games draw chance from, *rand.Rand satisfies it. Games
+// never reach for the global source so a seeded session replays exactly
+type Random interface {
+	Intn(n int) int
+	Shuffle(n int, swap func(i, j int))
+}
+
+func NewRandom(seed int64) Random {
+	return rand.New(rand.NewSource(seed))
+}
+
+// NewSeed picks a seed for sessions that weren't given one
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// GlobalRandom draws from the global math/rand source, it is safe to share
+// between goroutines but can't be reproduced
+func GlobalRandom() Random {
+	return globalRandom{}
+}
+
+type globalRandom struct{}
+
+func (globalRandom) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+func (globalRandom) Shuffle(n int, swap func(i, j int)) {
+	rand.Shuffle(n, swap)
+}
@@ -3,6 +3,9 @@ package v1alpha1
 type TurnCounter struct {
 	Players      int
 	CurrentIndex int
+	// Out lists the seats that left the game in the order they went out,
+	// their turns are skipped
+	Out []int `json:",omitempty"`
 }
 
 func NewTurnCounter(players int, start int) TurnCounter {
@@ -12,8 +15,15 @@ func NewTurnCounter(players int, start int) TurnCounter {
 	}
 }
 
+// Next is the next seat still in the game, the current one if nobody else is
 func (tc TurnCounter) Next() int {
-	return (tc.CurrentIndex + 1) % tc.Players
+	for i := 1; i <= tc.Players; i++ {
+		next := (tc.CurrentIndex + i) % tc.Players
+		if !tc.IsOut(next) {
+			return next
+		}
+	}
+	return tc.CurrentIndex
 }
 
 func (tc TurnCounter) CurrentPlayer() int {
@@ -24,5 +34,30 @@ func (tc TurnCounter) Advance() TurnCounter {
 	return TurnCounter{
 		Players:      tc.Players,
 		CurrentIndex: tc.Next(),
+		Out:          tc.Out,
+	}
+}
+
+// Eliminate takes the seat out of the game, the turn stays where it is so
+// the caller advances it when the seat was the one playing
+func (tc TurnCounter) Eliminate(idx int) TurnCounter {
+	if tc.IsOut(idx) {
+		return tc
 	}
+	tc.Out = append(CloneSlice(tc.Out), idx)
+	return tc
+}
+
+func (tc TurnCounter) IsOut(idx int) bool {
+	for _, out := range tc.Out {
+		if out == idx {
+			return true
+		}
+	}
+	return false
+}
+
+// Remaining counts the seats still in the game
+func (tc TurnCounter) Remaining() int {
+	return tc.Players - len(tc.Out)
 }
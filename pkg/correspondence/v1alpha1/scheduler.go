@@ -0,0 +1,291 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+)
+
+// APIVersion is the version the schedule is persisted at
+const APIVersion = "v1alpha1"
+
+// ScheduleID is where the schedule is kept in the store
+var ScheduleID = uuid.MustParse("5c4e0b7a-3f0d-4f6e-9d2a-8e1c6b0a7d31")
+
+// Entry is a correspondence game waiting on its players, it holds enough to
+// list the game and load it back without it being in memory
+type Entry struct {
+	Game     uuid.UUID
+	Name     string
+	Players  []uuid.UUID
+	Waiting  []uuid.UUID
+	Deadline time.Time
+}
+
+// IsWaitingOn returns if the game waits on the player's move
+func (e Entry) IsWaitingOn(player uuid.UUID) bool {
+	for _, id := range e.Waiting {
+		if id.Equal(player) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPlayer returns if the player is in the game
+func (e Entry) HasPlayer(player uuid.UUID) bool {
+	for _, id := range e.Players {
+		if id.Equal(player) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRetry is how long a game whose deadline couldn't be enforced
+// waits before it is tried again
+const DefaultRetry = time.Minute
+
+// Expired is called with each game whose deadline passed, the game stays
+// on the schedule when it returns an error
+type Expired func(context.Context, Entry) error
+
+// Scheduler keeps the deadline of every waiting game and calls Expire once
+// one passes. Every change is written to the store so a restarted server
+// loads the schedule back and enforces what passed while it was down
+type Scheduler struct {
+	sync.Mutex
+	Store  persist.Interface
+	Expire Expired
+	Retry  time.Duration
+
+	entries map[string]Entry
+	retries map[string]time.Time
+	version uint64
+	wake    chan struct{}
+}
+
+func NewScheduler(store persist.Interface, expire Expired) *Scheduler {
+	return &Scheduler{
+		Store:   store,
+		Expire:  expire,
+		Retry:   DefaultRetry,
+		entries: make(map[string]Entry),
+		retries: make(map[string]time.Time),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Load replaces the schedule with the one in the store, a store without
+// one leaves it empty
+func (s *Scheduler) Load(ctx context.Context) error {
+	obj, err := s.Store.Load(ctx, ScheduleID)
+	if persist.IsError(err, persist.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// stores that don't keep the go type hand back the raw json
+	data, ok := obj.Data.([]byte)
+	if !ok {
+		data, err = json.Marshal(obj.Data)
+		if err != nil {
+			return err
+		}
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	s.Lock()
+	s.entries = make(map[string]Entry)
+	s.retries = make(map[string]time.Time)
+	for _, entry := range entries {
+		s.entries[entry.Game.ToFullString()] = entry
+	}
+	s.version = obj.ObjectVersion
+	s.Unlock()
+	s.poke()
+	return nil
+}
+
+// Set schedules the game, replacing its last deadline
+func (s *Scheduler) Set(ctx context.Context, entry Entry) error {
+	s.Lock()
+	s.entries[entry.Game.ToFullString()] = entry
+	delete(s.retries, entry.Game.ToFullString())
+	err := s.save(ctx)
+	s.Unlock()
+	s.poke()
+	return err
+}
+
+// Remove drops the game from the schedule, it returns if it was there
+func (s *Scheduler) Remove(ctx context.Context, game uuid.UUID) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	if _, has := s.entries[game.ToFullString()]; !has {
+		return false, nil
+	}
+	delete(s.entries, game.ToFullString())
+	delete(s.retries, game.ToFullString())
+	return true, s.save(ctx)
+}
+
+// Get returns the game's entry if it is scheduled
+func (s *Scheduler) Get(game uuid.UUID) (Entry, bool) {
+	s.Lock()
+	defer s.Unlock()
+	entry, has := s.entries[game.ToFullString()]
+	return entry, has
+}
+
+// Entries lists every scheduled game, soonest deadline first
+func (s *Scheduler) Entries() []Entry {
+	s.Lock()
+	defer s.Unlock()
+	return s.sorted()
+}
+
+// Run calls Expire for the games whose deadline passes until the context
+// is done
+func (s *Scheduler) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wake:
+		case <-timer.C:
+		}
+		s.ExpireBy(ctx, time.Now().UTC())
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if next, ok := s.next(); ok {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// ExpireBy calls Expire with every game due by the time and takes it off
+// the schedule once that succeeds, a game that moved on to a new turn
+// meanwhile keeps its new deadline. A game that fails is tried again after
+// the retry delay
+func (s *Scheduler) ExpireBy(ctx context.Context, now time.Time) []Entry {
+	s.Lock()
+	var due []Entry
+	for _, entry := range s.sorted() {
+		if entry.Deadline.After(now) {
+			break
+		}
+		if s.retries[entry.Game.ToFullString()].After(now) {
+			continue
+		}
+		due = append(due, entry)
+	}
+	s.Unlock()
+
+	var expired []Entry
+	for _, entry := range due {
+		if s.Expire != nil {
+			if err := s.Expire(ctx, entry); err != nil {
+				s.Lock()
+				s.retries[entry.Game.ToFullString()] = now.Add(s.Retry)
+				s.Unlock()
+				continue
+			}
+		}
+		if err := s.expired(ctx, entry, now); err != nil {
+			continue
+		}
+		expired = append(expired, entry)
+	}
+	return expired
+}
+
+// expired drops the entry unless the game was scheduled again since
+func (s *Scheduler) expired(ctx context.Context, entry Entry, now time.Time) error {
+	s.Lock()
+	defer s.Unlock()
+	key := entry.Game.ToFullString()
+	delete(s.retries, key)
+	current, has := s.entries[key]
+	if !has || !current.Deadline.Equal(entry.Deadline) {
+		return nil
+	}
+	delete(s.entries, key)
+	if err := s.save(ctx); err != nil {
+		// keep it so it is tried again
+		s.entries[key] = entry
+		s.retries[key] = now.Add(s.Retry)
+		return err
+	}
+	return nil
+}
+
+// next returns when the soonest entry is due, counting the wait of one
+// being retried
+func (s *Scheduler) next() (time.Time, bool) {
+	s.Lock()
+	defer s.Unlock()
+	var next time.Time
+	for _, entry := range s.entries {
+		due := entry.Deadline
+		if retry := s.retries[entry.Game.ToFullString()]; retry.After(due) {
+			due = retry
+		}
+		if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// poke wakes Run to look at the schedule again
+func (s *Scheduler) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sorted lists the entries soonest first, the lock has to be held
+func (s *Scheduler) sorted() []Entry {
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Deadline.Before(entries[j].Deadline)
+	})
+	return entries
+}
+
+// save writes the schedule to the store, the lock has to be held
+func (s *Scheduler) save(ctx context.Context) error {
+	if s.Store == nil {
+		return nil
+	}
+	s.version++
+	_, err := s.Store.CheckAndSet(ctx, persist.Object{
+		Meta: persist.Meta{
+			ID:            ScheduleID,
+			APIVersion:    APIVersion,
+			ObjectVersion: s.version,
+		},
+		Data: s.sorted(),
+	})
+	return err
+}
@@ -0,0 +1,126 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	correspondence "github.com/mat285/boardgames/pkg/correspondence/v1alpha1"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+)
+
+func TestScheduler(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	store := persist.NewMemory()
+	now := time.Now().UTC()
+	player := uuid.V4()
+	late := correspondence.Entry{Game: uuid.V4(), Name: "splendor", Players: []uuid.UUID{player}, Waiting: []uuid.UUID{player}, Deadline: now.Add(-time.Hour)}
+	soon := correspondence.Entry{Game: uuid.V4(), Name: "splendor", Deadline: now.Add(time.Hour)}
+	later := correspondence.Entry{Game: uuid.V4(), Name: "splendor", Deadline: now.Add(48 * time.Hour)}
+
+	s := correspondence.NewScheduler(store, nil)
+	it.Nil(s.Set(ctx, later))
+	it.Nil(s.Set(ctx, late))
+	it.Nil(s.Set(ctx, soon))
+	removed, err := s.Remove(ctx, soon.Game)
+	it.Nil(err)
+	it.True(removed)
+
+	// a restarted server picks the schedule back up from the store
+	var expired []correspondence.Entry
+	restarted := correspondence.NewScheduler(store, func(_ context.Context, entry correspondence.Entry) error {
+		expired = append(expired, entry)
+		return nil
+	})
+	it.Nil(restarted.Load(ctx))
+	entries := restarted.Entries()
+	it.Len(entries, 2)
+	it.Equal(late.Game, entries[0].Game)
+	it.True(entries[0].IsWaitingOn(player))
+	it.True(entries[0].HasPlayer(player))
+
+	it.Len(restarted.ExpireBy(ctx, now), 1)
+	it.Len(expired, 1)
+	it.Equal(late.Game, expired[0].Game)
+	_, has := restarted.Get(late.Game)
+	it.False(has)
+	it.Len(restarted.ExpireBy(ctx, now.Add(72*time.Hour)), 1)
+	it.Empty(restarted.Entries())
+}
+
+func TestSchedulerExpireFails(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	store := persist.NewMemory()
+	now := time.Now().UTC()
+	late := correspondence.Entry{Game: uuid.V4(), Name: "splendor", Deadline: now.Add(-time.Hour)}
+	moved := correspondence.Entry{Game: uuid.V4(), Name: "splendor", Deadline: now.Add(-time.Hour)}
+
+	var s *correspondence.Scheduler
+	fail := true
+	calls := 0
+	s = correspondence.NewScheduler(store, func(ctx context.Context, entry correspondence.Entry) error {
+		calls++
+		if entry.Game.Equal(moved.Game) {
+			// the game went on to a new turn
+			entry.Deadline = now.Add(time.Hour)
+			return s.Set(ctx, entry)
+		}
+		if fail {
+			return fmt.Errorf("Store unavailable")
+		}
+		return nil
+	})
+	it.Nil(s.Set(ctx, late))
+	it.Nil(s.Set(ctx, moved))
+
+	expired := s.ExpireBy(ctx, now)
+	it.Len(expired, 1)
+	it.Equal(moved.Game, expired[0].Game)
+	entry, has := s.Get(moved.Game)
+	it.True(has)
+	it.Equal(now.Add(time.Hour), entry.Deadline)
+
+	// the failed game stays scheduled, in the store too, and waits to retry
+	_, has = s.Get(late.Game)
+	it.True(has)
+	restarted := correspondence.NewScheduler(store, nil)
+	it.Nil(restarted.Load(ctx))
+	_, has = restarted.Get(late.Game)
+	it.True(has)
+	calls = 0
+	it.Empty(s.ExpireBy(ctx, now.Add(time.Second)))
+	it.Zero(calls)
+
+	fail = false
+	expired = s.ExpireBy(ctx, now.Add(correspondence.DefaultRetry))
+	it.Len(expired, 1)
+	it.Equal(late.Game, expired[0].Game)
+	_, has = s.Get(late.Game)
+	it.False(has)
+	it.Len(s.Entries(), 1)
+}
+
+func TestSchedulerRun(t *testing.T) {
+	it := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fired := make(chan uuid.UUID, 1)
+	s := correspondence.NewScheduler(persist.NewMemory(), func(_ context.Context, entry correspondence.Entry) error {
+		fired <- entry.Game
+		return nil
+	})
+	go s.Run(ctx)
+	game := uuid.V4()
+	it.Nil(s.Set(ctx, correspondence.Entry{Game: game, Deadline: time.Now().UTC().Add(10 * time.Millisecond)}))
+	select {
+	case id := <-fired:
+		it.Equal(game, id)
+	case <-time.After(5 * time.Second):
+		it.Fail("deadline never expired")
+	}
+}
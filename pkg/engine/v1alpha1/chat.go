@@ -0,0 +1,223 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	connection "github.com/mat285/boardgames/pkg/connection/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	messages "github.com/mat285/boardgames/pkg/messages/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+)
+
+const (
+	// DefaultChatHistory is how many messages a game keeps when it isn't set
+	DefaultChatHistory = 100
+	// MaxChatLength is the longest message anyone can say
+	MaxChatLength = 500
+)
+
+// ChatFilter checks a message before anyone sees it, it can rewrite the
+// text or refuse it with an error
+type ChatFilter func(player uuid.UUID, text string) (string, error)
+
+// Spectate lets the client watch the game without a seat, spectators only
+// take part in the chat when SpectatorChat is set
+func (e *Engine) Spectate(ctx context.Context, client connection.ClientInfo) error {
+	e.Lock()
+	if e.GetPlayer(client.GetID()) != nil {
+		e.Unlock()
+		return fmt.Errorf("Already seated")
+	}
+	if e.spectators == nil {
+		e.spectators = make(map[string]*Player)
+	}
+	e.spectators[client.GetID().ToFullString()] = NewPlayer(client.GetID(), client.GetUsername(), client)
+	e.Unlock()
+	return e.sendChatHistory(ctx, client.GetID())
+}
+
+// handleChat acts on the chat packets, they don't wait on the game loop so
+// the table can talk before the start and after the end
+func (e *Engine) handleChat(ctx context.Context, packet wire.Packet) error {
+	switch packet.Type {
+	case messages.PacketTypeSendChat:
+		return e.sendChat(ctx, packet)
+	case messages.PacketTypeMute:
+		return e.mute(packet)
+	case messages.PacketTypeChatHistory:
+		return e.sendChatHistory(ctx, packet.Origin)
+	}
+	return nil
+}
+
+func (e *Engine) sendChat(ctx context.Context, packet wire.Packet) error {
+	body, err := e.MessageProvider.ExtractSendChat(packet)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(body.Text)
+	if len(text) == 0 {
+		return fmt.Errorf("Empty message")
+	}
+	if len(text) > MaxChatLength {
+		return fmt.Errorf("Message is longer than %d characters", MaxChatLength)
+	}
+	e.Lock()
+	sender := e.chatter(packet.Origin)
+	filter := e.ChatFilter
+	e.Unlock()
+	if sender == nil {
+		return fmt.Errorf("Not at the table")
+	}
+	if filter != nil {
+		text, err = filter(sender.ID, text)
+		if err != nil {
+			return err
+		}
+	}
+	msg := game.ChatMessage{
+		ID:       uuid.V4(),
+		Player:   sender.ID,
+		Username: sender.Username,
+		Text:     text,
+		Time:     time.Now().UTC(),
+	}
+
+	e.Lock()
+	e.chat = append(e.chat, msg)
+	if max := e.chatHistory(); len(e.chat) > max {
+		e.chat = append([]game.ChatMessage{}, e.chat[len(e.chat)-max:]...)
+	}
+	var listeners []*Player
+	for _, listener := range e.chatters() {
+		if !e.muted[listener.ID.ToFullString()][sender.ID.ToFullString()] {
+			listeners = append(listeners, listener)
+		}
+	}
+	e.Unlock()
+
+	out, err := e.MessageProvider.MessageChatMessages([]game.ChatMessage{msg})
+	if err != nil {
+		return err
+	}
+	log := logger.GetLogger(ctx)
+	for _, listener := range listeners {
+		logger.MaybeError(log, listener.Send(ctx, *out))
+	}
+	return nil
+}
+
+// mute hides or shows the messages of a player to the sender
+func (e *Engine) mute(packet wire.Packet) error {
+	body, err := e.MessageProvider.ExtractMute(packet)
+	if err != nil {
+		return err
+	}
+	e.Lock()
+	defer e.Unlock()
+	if e.chatter(packet.Origin) == nil {
+		return fmt.Errorf("Not at the table")
+	}
+	key := packet.Origin.ToFullString()
+	if !body.Mute {
+		delete(e.muted[key], body.Player.ToFullString())
+		return nil
+	}
+	e.addMute(packet.Origin, body.Player)
+	return nil
+}
+
+// addMute hides the messages of muted from the player, the lock has to be
+// held
+func (e *Engine) addMute(player, muted uuid.UUID) {
+	key := player.ToFullString()
+	if e.muted == nil {
+		e.muted = make(map[string]map[string]bool)
+	}
+	if e.muted[key] == nil {
+		e.muted[key] = make(map[string]bool)
+	}
+	e.muted[key][muted.ToFullString()] = true
+}
+
+// mutes lists who muted whom, sorted so saves compare equal, the lock has
+// to be held
+func (e *Engine) mutes() []game.Mute {
+	var mutes []game.Mute
+	for player, muted := range e.muted {
+		for other, on := range muted {
+			if on {
+				mutes = append(mutes, game.Mute{Player: uuid.MustParse(player), Muted: uuid.MustParse(other)})
+			}
+		}
+	}
+	sort.Slice(mutes, func(i, j int) bool {
+		a, b := mutes[i].Player.ToFullString(), mutes[j].Player.ToFullString()
+		if a != b {
+			return a < b
+		}
+		return mutes[i].Muted.ToFullString() < mutes[j].Muted.ToFullString()
+	})
+	return mutes
+}
+
+// sendChatHistory sends the kept messages to the player, leaving out the
+// ones from players they muted
+func (e *Engine) sendChatHistory(ctx context.Context, pid uuid.UUID) error {
+	e.Lock()
+	listener := e.chatter(pid)
+	var history []game.ChatMessage
+	for _, msg := range e.chat {
+		if !e.muted[pid.ToFullString()][msg.Player.ToFullString()] {
+			history = append(history, msg)
+		}
+	}
+	e.Unlock()
+	if listener == nil || len(history) == 0 {
+		return nil
+	}
+	out, err := e.MessageProvider.MessageChatMessages(history)
+	if err != nil {
+		return err
+	}
+	return listener.Send(ctx, *out)
+}
+
+// chatter returns who is talking if they can take part in the chat, the
+// lock has to be held
+func (e *Engine) chatter(pid uuid.UUID) *Player {
+	if player := e.GetPlayer(pid); player != nil {
+		return player
+	}
+	if e.SpectatorChat {
+		return e.spectators[pid.ToFullString()]
+	}
+	return nil
+}
+
+// chatters are everyone who sees the chat, the lock has to be held
+func (e *Engine) chatters() []*Player {
+	var players []*Player
+	for _, id := range e.seats {
+		players = append(players, e.Players[id.ToFullString()])
+	}
+	if e.SpectatorChat {
+		for _, spectator := range e.spectators {
+			players = append(players, spectator)
+		}
+	}
+	return players
+}
+
+func (e *Engine) chatHistory() int {
+	if e.ChatHistory > 0 {
+		return e.ChatHistory
+	}
+	return DefaultChatHistory
+}
@@ -0,0 +1,101 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/splendor"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	messages "github.com/mat285/boardgames/pkg/messages/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+)
+
+// chatClient keeps the chat messages it is sent
+type chatClient struct {
+	sync.Mutex
+	id       uuid.UUID
+	username string
+	heard    []game.ChatMessage
+}
+
+func (c *chatClient) GetID() uuid.UUID    { return c.id }
+func (c *chatClient) GetUsername() string { return c.username }
+func (c *chatClient) Messages() []string {
+	c.Lock()
+	defer c.Unlock()
+	var texts []string
+	for _, msg := range c.heard {
+		texts = append(texts, msg.Text)
+	}
+	return texts
+}
+
+func (c *chatClient) Send(ctx context.Context, packet wire.Packet) error {
+	if packet.Type != messages.PacketTypeChatMessages {
+		return nil
+	}
+	body, err := messages.Provider{}.ExtractChatMessages(packet)
+	if err != nil {
+		return err
+	}
+	c.Lock()
+	c.heard = append(c.heard, body.Messages...)
+	c.Unlock()
+	return nil
+}
+
+func TestChat(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	g, err := splendor.New(nil)
+	it.Nil(err)
+	e := engine.NewEngine(g, nil)
+	e.ChatHistory = 2
+	e.ChatFilter = func(_ uuid.UUID, text string) (string, error) {
+		if strings.Contains(text, "spam") {
+			return "", fmt.Errorf("No spam")
+		}
+		return text, nil
+	}
+	a := &chatClient{id: uuid.V4(), username: "a"}
+	b := &chatClient{id: uuid.V4(), username: "b"}
+	watcher := &chatClient{id: uuid.V4(), username: "watcher"}
+	it.Nil(e.Join(ctx, a))
+	it.Nil(e.Join(ctx, b))
+	it.Nil(e.Spectate(ctx, watcher))
+
+	say := func(from uuid.UUID, text string) error {
+		packet, err := e.MessageProvider.MessageSendChat(text, from)
+		it.Nil(err)
+		packet.Origin = from
+		return e.Receive(ctx, *packet)
+	}
+	it.Nil(say(a.id, "hello"))
+	it.NotNil(say(b.id, "spam"))
+	// spectators can't talk unless the game lets them
+	it.NotNil(say(watcher.id, "hi"))
+	it.Equal([]string{"hello"}, b.Messages())
+	it.Empty(watcher.Messages())
+
+	mute, err := e.MessageProvider.MessageMute(a.id, true, b.id)
+	it.Nil(err)
+	mute.Origin = b.id
+	it.Nil(e.Receive(ctx, *mute))
+	it.Nil(say(a.id, "again"))
+	it.Nil(say(b.id, "bye"))
+	it.Equal([]string{"hello", "bye"}, b.Messages())
+	it.Equal([]string{"hello", "again", "bye"}, a.Messages())
+
+	// rejoining sends the kept history
+	it.Nil(e.Join(ctx, a))
+	it.Equal([]string{"hello", "again", "bye", "again", "bye"}, a.Messages())
+	record, err := e.Record()
+	it.Nil(err)
+	it.Len(record.Chat, 2)
+}
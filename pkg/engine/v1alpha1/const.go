@@ -3,5 +3,5 @@ package v1alpha1
 import "github.com/mat285/boardgames/pkg/apiversions"
 
 const (
-	APIVersion = apiversions.V1Alpha1
+	APIVersion = apiversions.Latest
 )
@@ -0,0 +1,86 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	connection "github.com/mat285/boardgames/pkg/connection/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+)
+
+// IsCorrespondence returns if each move has a deadline instead of the game
+// being played in real time
+func (e *Engine) IsCorrespondence() bool {
+	return e.MoveDeadline > 0
+}
+
+// Deadline is when the acting players have to have moved by, it is zero
+// for real time games, the lock has to be held
+func (e *Engine) Deadline() time.Time {
+	return e.deadline
+}
+
+// Step plays the packet on a correspondence game and moves the game on
+// until it waits on a player again, without a packet it only moves the
+// game on. Nothing runs between steps so the engine can be saved and
+// dropped until the next packet
+func (e *Engine) Step(ctx context.Context, packet *wire.Packet) error {
+	e.stepLock.Lock()
+	defer e.stepLock.Unlock()
+	e.Lock()
+	started := e.started
+	e.Unlock()
+	if !started {
+		return fmt.Errorf("Game not started")
+	}
+	if packet != nil {
+		err := e.handlePacket(ctx, *packet)
+		if err != nil {
+			return err
+		}
+	}
+	return e.advance(ctx)
+}
+
+// advance makes the chance and queued moves and asks the acting players
+// for theirs, the way the game loop does between packets
+func (e *Engine) advance(ctx context.Context) error {
+	for {
+		e.Lock()
+		done := e.State.Data.IsDone()
+		e.Unlock()
+		if done {
+			return e.gameOver(ctx)
+		}
+		chance, err := e.gameTurnChance(ctx)
+		if err != nil {
+			return err
+		}
+		if chance {
+			continue
+		}
+		queued, err := e.gameTurnQueued(ctx)
+		if err != nil {
+			logger.MaybeError(logger.GetLogger(ctx), err)
+		}
+		if queued {
+			continue
+		}
+		return e.gameTurnPreMove(ctx)
+	}
+}
+
+// Reconnect sends a seated player's packets to the client without telling
+// anyone, it is for engines loaded back while their players are connected
+func (e *Engine) Reconnect(client connection.ClientInfo) bool {
+	e.Lock()
+	defer e.Unlock()
+	player := e.GetPlayer(client.GetID())
+	if player == nil {
+		return false
+	}
+	player.Sender = client
+	return true
+}
@@ -0,0 +1,74 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/machikoro"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+)
+
+func TestCorrespondence(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	store := persist.NewMemory()
+	g, err := machikoro.New(nil)
+	it.Nil(err)
+	e := engine.NewEngine(g, nil)
+	e.MoveDeadline = 72 * time.Hour
+	e.Persist = store
+	a := &chatClient{id: uuid.V4(), username: "a"}
+	b := &chatClient{id: uuid.V4(), username: "b"}
+	it.Nil(e.Join(ctx, a))
+	it.Nil(e.Join(ctx, b))
+
+	var turns [][]uuid.UUID
+	observe := func(_ context.Context, _ *engine.Engine, event engine.Event) {
+		if event.Type == engine.EventTypeTurn {
+			turns = append(turns, event.Body.([]uuid.UUID))
+		}
+	}
+	e.Observe(observe)
+	// there's no game loop so starting returns once a player has to move
+	it.Nil(e.Start(ctx))
+	it.Len(turns, 1)
+	e.Lock()
+	deadline := e.Deadline()
+	it.Nil(e.Save(ctx))
+	e.Unlock()
+	it.True(deadline.After(time.Now().Add(71 * time.Hour)))
+
+	// the engine is dropped between moves and loaded back for the next one
+	g, err = machikoro.New(nil)
+	it.Nil(err)
+	loaded, err := engine.Load(ctx, g, store, e.ID)
+	it.Nil(err)
+	it.Equal(72*time.Hour, loaded.MoveDeadline)
+	loaded.Observe(observe)
+	it.True(loaded.Reconnect(a))
+	it.False(loaded.Reconnect(&chatClient{id: uuid.V4()}))
+	loaded.Lock()
+	it.True(loaded.Deadline().Equal(deadline))
+	version := loaded.State.Version
+	valid, err := loaded.State.Data.ValidMoves()
+	loaded.Unlock()
+	it.Nil(err)
+
+	// resuming asks for the same turn without telling observers again
+	it.Nil(loaded.Resume(ctx))
+	it.Len(turns, 1)
+
+	mover := turns[0][0]
+	packet, err := loaded.MessageProvider.MessagePlayerMove(valid[len(valid)-1], mover)
+	it.Nil(err)
+	packet.Origin = mover
+	it.Nil(loaded.Receive(ctx, *packet))
+	it.Len(turns, 2)
+	loaded.Lock()
+	it.True(loaded.State.Version > version)
+	loaded.Unlock()
+}
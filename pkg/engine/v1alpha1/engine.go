@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/blend/go-sdk/logger"
 	"github.com/blend/go-sdk/uuid"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
 	connection "github.com/mat285/boardgames/pkg/connection/v1alpha1"
 	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
 	messages "github.com/mat285/boardgames/pkg/messages/v1alpha1"
+	model "github.com/mat285/boardgames/pkg/model/v1alpha1"
 	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
 	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
 )
@@ -18,14 +21,48 @@ import (
 // 	_ connection.ServerInfo = new(Engine)
 // )
 
+type sealedMove struct {
+	*game.SealedMove
+	move game.Move
+}
+
 type Engine struct {
 	sync.Mutex
 	ID uuid.UUID
-
-	started bool
+	// Seed drives every random draw of the game, set it before starting to
+	// get the same deal again
+	Seed int64
+	// StartSeat takes the first turn, a rematch moves it on a seat
+	StartSeat int
+
+	// Created is when the game was set up, the lobby lists newest first
+	Created time.Time
+	// Private games are only joined by id and never listed in the lobby
+	Private bool
+	// Casual games are left out of the ratings when the server is set to,
+	// Seeded ones were dealt from a seed a player picked and never are
+	Casual bool
+	Seeded bool
+	// MoveDeadline makes a correspondence game, each move can take up to it
+	// and packets are played as they come in without a game loop
+	MoveDeadline time.Duration
+	deadline     time.Time
+	// stepLock plays the packets of a correspondence game one at a time
+	stepLock sync.Mutex
+
+	started  bool
+	finished bool
+
+	// Previous and Next link a game to the one it is a rematch of and the
+	// rematch that followed it
+	Previous uuid.UUID
+	Next     uuid.UUID
 
 	Host    *Player
 	Players map[string]*Player
+	seats   []uuid.UUID
+	// teams are picked in the lobby, empty for free for all
+	teams []game.Team
 
 	// request connection.Requester
 	inbound chan wire.Packet
@@ -35,6 +72,38 @@ type Engine struct {
 	State *game.State
 	Game  game.Game
 
+	initial *game.SerializedObject
+	Moves   []game.RecordedMove
+
+	// Commitment hashes the seed and the initial deal, what it commits to
+	// is only sent out once the game is over
+	Commitment string
+	reveal     *game.Reveal
+
+	// sealed holds moves from the acting players until all of them are in
+	sealed []sealedMove
+
+	// states follows Moves, the state from before the move at i is at i
+	states []game.StateData
+	// takeback waits on the votes of the other players
+	takeback *takeback
+
+	observers []Observer
+	// turn is one past the version observers last heard was a new turn
+	turn uint64
+
+	// chat keeps the last messages said at the table and muted who each
+	// player doesn't want to hear
+	chat  []game.ChatMessage
+	muted map[string]map[string]bool
+	// spectators watch without a seat
+	spectators map[string]*Player
+	// ChatHistory is how many messages are kept and ChatFilter checks each
+	// one before it is sent, SpectatorChat lets spectators read and talk
+	ChatHistory   int
+	ChatFilter    ChatFilter
+	SpectatorChat bool
+
 	Persist persist.Interface
 
 	stop chan struct{}
@@ -43,6 +112,8 @@ type Engine struct {
 func NewEngine(g game.Game, host *Player) *Engine {
 	e := &Engine{
 		ID:              uuid.V4(),
+		Seed:            common.NewSeed(),
+		Created:         time.Now().UTC(),
 		Players:         make(map[string]*Player),
 		MessageProvider: messages.NewProvider(g),
 		Game:            g,
@@ -51,7 +122,7 @@ func NewEngine(g game.Game, host *Player) *Engine {
 	}
 	// e.request = connection.NewRequestManager(e.receive)
 	if host != nil {
-		e.Players[host.ID.ToFullString()] = host
+		e.seat(host)
 	}
 	e.State = game.NewState(e.GamePlayers())
 	return e
@@ -65,6 +136,18 @@ func (e *Engine) SetID(id uuid.UUID) {
 	e.ID = id
 }
 
+// Status is where the game is at for the lobby, the lock has to be held
+func (e *Engine) Status() model.GameStatus {
+	switch {
+	case !e.started:
+		return model.GameStatusOpen
+	case e.State.Data == nil || !e.State.Data.IsDone():
+		return model.GameStatusPlaying
+	default:
+		return model.GameStatusOver
+	}
+}
+
 func (e *Engine) GetStateData() (game.StateData, error) {
 	if e.State == nil {
 		return nil, fmt.Errorf("no state")
@@ -76,17 +159,101 @@ func (e *Engine) GetStateData() (game.StateData, error) {
 }
 
 func (e *Engine) Join(ctx context.Context, client connection.ClientInfo) error {
+	e.Lock()
+	seated, err := e.join(client)
+	e.Unlock()
+	if err != nil {
+		return err
+	}
+	if seated {
+		e.notify(ctx, Event{Type: EventTypeJoined, Body: client.GetID()})
+	}
+	// players rejoining pick the chat up where they left it
+	logger.MaybeError(logger.GetLogger(ctx), e.sendChatHistory(ctx, client.GetID()))
+	return nil
+}
+
+// join seats the client, it reports false for a player taking back their
+// seat. The lock has to be held
+func (e *Engine) join(client connection.ClientInfo) (bool, error) {
+	if client.GetID().Equal(game.SystemActor) {
+		return false, fmt.Errorf("Cannot join as the system actor")
+	}
+	if existing := e.GetPlayer(client.GetID()); existing != nil {
+		// rejoining a seat we already have, usually after a load
+		existing.Sender = client
+		return false, nil
+	}
 	if e.started {
-		return fmt.Errorf("Game Already Started")
+		return false, fmt.Errorf("Game Already Started")
 	}
+	if _, max := game.GameSeats(e.Game); max > 0 && len(e.seats) >= max {
+		return false, fmt.Errorf("Game is full")
+	}
+	e.seat(NewPlayer(client.GetID(), client.GetUsername(), client))
+	return true, nil
+}
+
+// seat adds the player in join order, which is the order the game deals in
+func (e *Engine) seat(player *Player) {
+	e.Players[player.ID.ToFullString()] = player
+	e.seats = append(e.seats, player.ID)
+}
+
+// AssignTeam moves a seated player onto the named team before the game
+// starts, teams are kept in the order they were first named
+func (e *Engine) AssignTeam(player uuid.UUID, team string) error {
 	e.Lock()
 	defer e.Unlock()
-	player := NewPlayer(client.GetID(), client.GetUsername(), client)
-	e.Players[player.ID.ToFullString()] = player
+	if e.started {
+		return fmt.Errorf("Game Already Started")
+	}
+	if _, ok := e.Game.(game.TeamGame); !ok {
+		return fmt.Errorf("Game %s is not played in teams", e.Game.Name())
+	}
+	if e.GetPlayer(player) == nil {
+		return fmt.Errorf("No player for id %s", player)
+	}
+	if len(team) == 0 {
+		return fmt.Errorf("Missing team name")
+	}
+	teams := make([]game.Team, 0, len(e.teams)+1)
+	found := false
+	for _, t := range e.teams {
+		players := make([]uuid.UUID, 0, len(t.Players)+1)
+		for _, p := range t.Players {
+			if !p.Equal(player) {
+				players = append(players, p)
+			}
+		}
+		if t.Name == team {
+			players = append(players, player)
+			found = true
+		}
+		if len(players) > 0 {
+			teams = append(teams, game.Team{Name: t.Name, Players: players})
+		}
+	}
+	if !found {
+		teams = append(teams, game.Team{Name: team, Players: []uuid.UUID{player}})
+	}
+	e.teams = teams
 	return nil
 }
 
+// Teams returns the teams of the game, the ones the state reports once it
+// has started and the lobby's before that
+func (e *Engine) Teams() ([]game.Team, error) {
+	if e.State == nil || e.State.Data == nil {
+		return common.CloneSlice(e.teams), nil
+	}
+	return game.StateTeams(e.State.Data, e.teams)
+}
+
 func (e *Engine) Receive(ctx context.Context, packet wire.Packet) error {
+	if e.IsCorrespondence() {
+		return e.RecieveSync(ctx, packet)
+	}
 	return e.receive(ctx, packet, func(ctx context.Context, packet wire.Packet) error {
 		return wire.PushPacket(ctx, e.inbound, packet)
 	})
@@ -95,17 +262,51 @@ func (e *Engine) Receive(ctx context.Context, packet wire.Packet) error {
 
 func (e *Engine) RecieveSync(ctx context.Context, packet wire.Packet) error {
 	return e.receive(ctx, packet, func(ctx context.Context, packet wire.Packet) error {
-		e.Lock()
-		_, _, err := e.gameTurnApplyPacket(ctx, packet)
-		e.Unlock()
-		return err
+		if e.IsCorrespondence() {
+			return e.Step(ctx, &packet)
+		}
+		err := e.handlePacket(ctx, packet)
+		if err != nil {
+			return err
+		}
+		return e.gameOver(ctx)
 	})
 }
 
+// handlePacket acts on a packet from a player, a move or a request about
+// the game itself
+func (e *Engine) handlePacket(ctx context.Context, packet wire.Packet) error {
+	switch packet.Type {
+	case messages.PacketTypeResign:
+		return e.gameTurnResign(ctx, packet.Origin)
+	case messages.PacketTypeTakebackRequest:
+		return e.requestTakeback(ctx, packet.Origin)
+	case messages.PacketTypeTakebackAnswer:
+		return e.answerTakeback(ctx, packet)
+	case messages.PacketTypeQueueMoves:
+		return e.queueMoves(ctx, packet)
+	}
+	e.Lock()
+	player, move, err := e.gameTurnApplyPacket(ctx, packet)
+	e.Unlock()
+	if err != nil {
+		return err
+	}
+	if move == nil {
+		// sealed until every acting player has moved
+		return e.gameTurnOpen(ctx, player.ID)
+	}
+	return e.broadcastPlayerMove(ctx, player.ID, move)
+}
+
 func (e *Engine) receive(ctx context.Context, packet wire.Packet, fn func(context.Context, wire.Packet) error) error {
 	switch packet.Type {
-	case messages.PacketTypePlayerMove:
+	case messages.PacketTypePlayerMove, messages.PacketTypeResign,
+		messages.PacketTypeTakebackRequest, messages.PacketTypeTakebackAnswer,
+		messages.PacketTypeQueueMoves:
 		return fn(ctx, packet)
+	case messages.PacketTypeSendChat, messages.PacketTypeMute, messages.PacketTypeChatHistory:
+		return e.handleChat(ctx, packet)
 	default:
 		// drop packet
 	}
@@ -118,15 +319,62 @@ func (e *Engine) Start(ctx context.Context) error {
 		e.Unlock()
 		return fmt.Errorf("Game already started")
 	}
-	data, err := e.Game.Initialize(e.PlayerIDs())
+	data, err := Deal(e.Game, e.PlayerIDs(), e.teams, e.Seed, e.StartSeat)
+	if err != nil {
+		e.Unlock()
+		return err
+	}
+	initial, err := e.MessageProvider.SerializeState(data)
+	if err != nil {
+		e.Unlock()
+		return err
+	}
+	reveal, err := game.NewReveal(e.Seed, initial)
+	if err != nil {
+		e.Unlock()
+		return err
+	}
+	commitment, err := reveal.Commitment()
 	if err != nil {
 		e.Unlock()
 		return err
 	}
 	e.State.Data = data
+	e.states = []game.StateData{data}
+	e.initial = initial
+	e.reveal = reveal
+	e.Commitment = commitment
 	e.started = true
 	e.stop = make(chan struct{})
 	e.Unlock()
+	e.notify(ctx, Event{Type: EventTypeStarted})
+
+	msg, err := e.MessageProvider.MessageGameStarted(e.PlayerIDs(), e.teams, commitment)
+	if err != nil {
+		return err
+	}
+	err = e.Broadcast(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if e.IsCorrespondence() {
+		return e.Step(ctx, nil)
+	}
+	return e.gameLoop(ctx)
+}
+
+// Resume runs the game loop for an engine that was loaded already started
+func (e *Engine) Resume(ctx context.Context) error {
+	e.Lock()
+	if !e.started {
+		e.Unlock()
+		return fmt.Errorf("Game not started")
+	}
+	e.stop = make(chan struct{})
+	e.Unlock()
+	if e.IsCorrespondence() {
+		return e.Step(ctx, nil)
+	}
 	return e.gameLoop(ctx)
 }
 
@@ -142,7 +390,31 @@ func (e *Engine) gameLoop(ctx context.Context) error {
 			// fall through
 		}
 
-		err := e.gameTurnPreMove(ctx)
+		e.Lock()
+		done := e.State.Data.IsDone()
+		e.Unlock()
+		if done {
+			return e.gameOver(ctx)
+		}
+
+		chance, err := e.gameTurnChance(ctx)
+		if err != nil {
+			// chance can't move the game on so nobody ever will
+			return err
+		}
+		if chance {
+			continue
+		}
+
+		queued, err := e.gameTurnQueued(ctx)
+		if err != nil {
+			logger.MaybeError(log, err)
+		}
+		if queued {
+			continue
+		}
+
+		err = e.gameTurnPreMove(ctx)
 		if err != nil {
 			logger.MaybeError(log, err)
 			continue
@@ -156,52 +428,212 @@ func (e *Engine) gameLoop(ctx context.Context) error {
 			if !ok {
 				return nil
 			}
-			e.Lock()
-			player, move, err := e.gameTurnApplyPacket(ctx, packet)
-			e.Unlock()
+			err = e.handlePacket(ctx, packet)
 			if err != nil {
 				logger.MaybeError(log, err)
-				continue
-			}
-			err = e.broadcastPlayerMove(ctx, player.ID, move)
-			if err != nil {
-				logger.MaybeError(log, err)
-				continue
 			}
 			continue
 		}
 	}
 }
 
-func (e *Engine) gameTurnPreMove(ctx context.Context) error {
-	log := logger.GetLogger(ctx)
-	if e.State.Data.IsDone() {
-		msg, err := e.MessageProvider.MessageGameOver(e.State.Data.Winners())
-		if err != nil {
-			return err
-		}
-		return e.Broadcast(ctx, msg)
+// gameTurnChance makes the move for the system actor when chance decides
+// the next step, it reports whether there was one to make
+func (e *Engine) gameTurnChance(ctx context.Context) (bool, error) {
+	e.Lock()
+	state, ok := game.IsChance(e.State.Data)
+	if !ok || state.IsDone() {
+		e.Unlock()
+		return false, nil
+	}
+	move, err := ChanceMove(state, e.Seed, e.State.Version+1)
+	if err == nil {
+		err = e.applyMove(game.SystemActor, move)
+	}
+	e.Unlock()
+	if err != nil {
+		return true, err
 	}
+	return true, e.broadcastPlayerMove(ctx, game.SystemActor, move)
+}
 
-	pid, err := e.State.Data.CurrentPlayer()
+// gameTurnResign takes the player out of the game, they keep getting
+// packets as a spectator
+func (e *Engine) gameTurnResign(ctx context.Context, pid uuid.UUID) error {
+	e.Lock()
+	err := e.resign(pid)
+	var opened []*game.SealedMove
+	var openErr error
+	if err == nil && len(e.sealed) > 0 {
+		// everyone left acting may have moved already
+		opened, openErr = e.openSealed()
+	}
+	var cleared []*Player
+	if err == nil {
+		cleared = e.clearQueues(pid)
+	}
+	e.Unlock()
+	if err != nil {
+		return err
+	}
+	err = e.sendCleared(ctx, cleared)
+	if err != nil {
+		return err
+	}
+	msg, err := e.MessageProvider.MessagePlayerResigned(pid)
+	if err != nil {
+		return err
+	}
+	err = e.Broadcast(ctx, msg)
 	if err != nil {
-		logger.MaybeError(log, err)
 		return err
 	}
+	err = e.broadcastOpened(ctx, opened)
+	if err != nil {
+		return err
+	}
+	return openErr
+}
 
-	player := e.GetPlayer(pid)
-	if player == nil {
+// resign logs the resignation like a move, the lock has to be held
+func (e *Engine) resign(pid uuid.UUID) error {
+	if !e.started {
+		return fmt.Errorf("Game not started")
+	}
+	if e.GetPlayer(pid) == nil {
 		return fmt.Errorf("No player for id %s", pid)
 	}
+	state, err := game.Resign(e.State.Data, pid)
+	if err != nil {
+		return err
+	}
+	var sealed []sealedMove
+	for _, s := range e.sealed {
+		if !s.Player.Equal(pid) {
+			sealed = append(sealed, s)
+		}
+	}
+	e.sealed = sealed
+
+	e.State.Data = state
+	e.State.Version++
+	e.Moves = append(e.Moves, game.RecordedMove{
+		Version:  e.State.Version,
+		Player:   pid,
+		Resigned: true,
+	})
+	e.remember(state)
+	return nil
+}
 
-	msg, err := e.MessageProvider.MessageRequestMove(e.State.Data)
+// gameOver tells everyone where they finished, hands out the reveal and
+// saves the finished game. It only does so once and not before the game is
+// over
+func (e *Engine) gameOver(ctx context.Context) error {
+	e.Lock()
+	if e.finished || e.State.Data == nil || !e.State.Data.IsDone() {
+		e.Unlock()
+		return nil
+	}
+	e.finished = true
+	winners := e.State.Data.Winners()
+	standings, err := e.Standings()
+	var teams []game.TeamStanding
+	if err == nil {
+		teams, err = e.TeamStandings()
+	}
+	if err == nil {
+		err = e.Save(ctx)
+	}
+	e.Unlock()
+	if err != nil {
+		return err
+	}
+	e.notify(ctx, Event{Type: EventTypeOver})
+
+	msg, err := e.MessageProvider.MessageGameOver(winners, standings, teams)
+	if err != nil {
+		return err
+	}
+	err = e.Broadcast(ctx, msg)
+	if err != nil || e.reveal == nil {
+		return err
+	}
+	msg, err = e.MessageProvider.MessageGameReveal(*e.reveal)
 	if err != nil {
 		return err
 	}
-	msg.Destination = pid
-	msg.Origin = e.ID
-	msg.ID = pid
-	return player.Send(ctx, *msg)
+	return e.Broadcast(ctx, msg)
+}
+
+// Standings ranks the players once the game is over, it's nil until then
+func (e *Engine) Standings() ([]game.Standing, error) {
+	if e.State == nil || e.State.Data == nil || !e.State.Data.IsDone() {
+		return nil, nil
+	}
+	return game.Standings(e.State.Data, e.PlayerIDs())
+}
+
+// TeamStandings ranks the teams once the game is over, it's nil until then
+// and for free for all games
+func (e *Engine) TeamStandings() ([]game.TeamStanding, error) {
+	standings, err := e.Standings()
+	if err != nil || standings == nil {
+		return nil, err
+	}
+	teams, err := e.Teams()
+	if err != nil || len(teams) == 0 {
+		return nil, err
+	}
+	return game.TeamStandings(e.State.Data, teams, standings), nil
+}
+
+func (e *Engine) gameTurnPreMove(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+	acting, err := game.ActingPlayers(e.State.Data)
+	if err != nil {
+		logger.MaybeError(log, err)
+		return err
+	}
+
+	var waiting []uuid.UUID
+	for _, pid := range acting {
+		if e.sealedMove(pid) != nil {
+			continue
+		}
+		waiting = append(waiting, pid)
+		player := e.GetPlayer(pid)
+		if player == nil {
+			return fmt.Errorf("No player for id %s", pid)
+		}
+		// each player is only sent what they are allowed to see
+		msg, err := e.MessageProvider.MessageRequestMove(game.View(e.State.Data, pid))
+		if err != nil {
+			return err
+		}
+		request := *msg
+		request.Destination = pid
+		request.Origin = e.ID
+		request.ID = pid
+		err = player.Send(ctx, request)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the loop asks again after every packet, observers only hear about
+	// each turn once
+	e.Lock()
+	fresh := e.turn != e.State.Version+1
+	e.turn = e.State.Version + 1
+	if fresh && e.IsCorrespondence() {
+		e.deadline = time.Now().UTC().Add(e.MoveDeadline)
+	}
+	e.Unlock()
+	if fresh {
+		e.notify(ctx, Event{Type: EventTypeTurn, Body: waiting})
+	}
+	return nil
 }
 
 func (e *Engine) gameTurnApplyPacket(ctx context.Context, packet wire.Packet) (*Player, game.Move, error) {
@@ -210,39 +642,184 @@ func (e *Engine) gameTurnApplyPacket(ctx context.Context, packet wire.Packet) (*
 		return nil, nil, fmt.Errorf("game is already over ignoring move")
 	}
 
-	pid, err := e.State.Data.CurrentPlayer()
+	acting, err := game.ActingPlayers(e.State.Data)
 	if err != nil {
 		logger.MaybeError(log, err)
 		return nil, nil, err
 	}
 
+	pid := packet.Origin
+	if !excludeUUID(pid, acting...) {
+		return nil, nil, fmt.Errorf("Player %s cannot act now ignoring move", pid)
+	}
+
 	player := e.GetPlayer(pid)
 	if player == nil {
 		return nil, nil, fmt.Errorf("No player for id %s", pid)
 	}
 
-	if !packet.Origin.Equal(pid) {
-		return nil, nil, fmt.Errorf("Not player %s turn ignoring move", pid)
-	}
-
 	move, err := e.MessageProvider.ExtractMove(packet)
 	if err != nil {
 		return player, nil, err
 	}
+	if len(acting) > 1 {
+		return player, nil, e.seal(pid, move)
+	}
+	return player, move, e.applyMove(pid, move)
+}
+
+// seal holds the move back until every acting player has moved, it has to
+// be valid on its own against the current state. The lock has to be held
+func (e *Engine) seal(pid uuid.UUID, move game.Move) error {
+	if e.sealedMove(pid) != nil {
+		return fmt.Errorf("Player %s already moved", pid)
+	}
+	response, err := move.Apply(e.State.Data)
+	if err != nil {
+		return err
+	}
+	if !response.Valid {
+		return fmt.Errorf("Invalid Move")
+	}
+	so, err := e.MessageProvider.SerializeMove(move)
+	if err != nil {
+		return err
+	}
+	sealed, err := game.NewSealedMove(pid, so)
+	if err != nil {
+		return err
+	}
+	e.sealed = append(e.sealed, sealedMove{SealedMove: sealed, move: move})
+	return nil
+}
+
+func (e *Engine) sealedMove(pid uuid.UUID) *sealedMove {
+	for i := range e.sealed {
+		if e.sealed[i].Player.Equal(pid) {
+			return &e.sealed[i]
+		}
+	}
+	return nil
+}
+
+// gameTurnOpen tells everyone the player has moved, once every acting
+// player has it opens the sealed moves and applies them in seat order
+func (e *Engine) gameTurnOpen(ctx context.Context, pid uuid.UUID) error {
+	e.Lock()
+	sealed := e.sealedMove(pid)
+	if sealed == nil {
+		e.Unlock()
+		return nil
+	}
+	commitment, err := sealed.Commitment()
+	if err != nil {
+		e.Unlock()
+		return err
+	}
+	opened, openErr := e.openSealed()
+	e.Unlock()
+
+	msg, err := e.MessageProvider.MessageMoveSealed(pid, commitment)
+	if err != nil {
+		return err
+	}
+	err = e.Broadcast(ctx, msg)
+	if err != nil {
+		return err
+	}
+	err = e.broadcastOpened(ctx, opened)
+	if err != nil {
+		return err
+	}
+	return openErr
+}
 
+func (e *Engine) broadcastOpened(ctx context.Context, opened []*game.SealedMove) error {
+	for _, move := range opened {
+		msg, err := e.MessageProvider.MessageMoveOpened(*move)
+		if err != nil {
+			return err
+		}
+		err = e.Broadcast(ctx, msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openSealed applies the sealed moves once every acting player has one,
+// a move that no longer applies is dropped and its player is asked again.
+// The lock has to be held
+func (e *Engine) openSealed() ([]*game.SealedMove, error) {
+	acting, err := game.ActingPlayers(e.State.Data)
+	if err != nil {
+		return nil, err
+	}
+	for _, pid := range acting {
+		if e.sealedMove(pid) == nil {
+			return nil, nil
+		}
+	}
+	sealed := e.sealed
+	e.sealed = nil
+
+	var opened []*game.SealedMove
+	var firstErr error
+	for _, pid := range e.seats {
+		for _, s := range sealed {
+			if !s.Player.Equal(pid) {
+				continue
+			}
+			err = e.applyMove(pid, s.move)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			opened = append(opened, s.SealedMove)
+		}
+	}
+	return opened, firstErr
+}
+
+// applyMove moves the game on and logs the move, the lock has to be held
+func (e *Engine) applyMove(pid uuid.UUID, move game.Move) error {
 	response, err := move.Apply(e.State.Data)
 	if err != nil {
 		// player.Send(ctx, wire.ErrorPacket(err))
-		return player, nil, err
+		return err
 	}
 
 	if !response.Valid {
 		// player.Send(ctx, wire.ErrorPacket(fmt.Errorf("Invalid Move")))
-		return player, move, fmt.Errorf("Invalid Move")
+		return fmt.Errorf("Invalid Move")
+	}
+
+	so, err := e.MessageProvider.SerializeMove(move)
+	if err != nil {
+		return err
 	}
 
 	e.State.Data = response.State
-	return player, move, nil
+	e.State.Version++
+	e.Moves = append(e.Moves, game.RecordedMove{
+		Version: e.State.Version,
+		Player:  pid,
+		Move:    so,
+	})
+	e.remember(response.State)
+	return nil
+}
+
+// remember keeps the state the last move led to for takebacks, a move
+// also drops a takeback still waiting on votes. The lock has to be held
+func (e *Engine) remember(state game.StateData) {
+	e.takeback = nil
+	if len(e.states) == len(e.Moves) {
+		e.states = append(e.states, state)
+	}
 }
 
 func (e *Engine) broadcastPlayerMove(ctx context.Context, player uuid.UUID, move game.Move) error {
@@ -290,15 +867,19 @@ func (e *Engine) Save(ctx context.Context) error {
 	if e.Persist == nil {
 		return nil
 	}
+	record, err := e.Record()
+	if err != nil {
+		return err
+	}
 	obj := persist.Object{
 		Meta: persist.Meta{
 			ID:            e.ID,
 			APIVersion:    APIVersion,
 			ObjectVersion: e.State.Version,
 		},
-		Data: e.State,
+		Data: *record,
 	}
-	_, err := e.Persist.CheckAndSet(ctx, obj)
+	_, err = e.Persist.CheckAndSet(ctx, obj)
 	return err
 }
 
@@ -329,22 +910,68 @@ func (e *Engine) GetPlayer(id uuid.UUID) *Player {
 	return nil
 }
 
+// PlayerIDs lists the players in seat order
 func (e *Engine) PlayerIDs() []uuid.UUID {
-	ids := make([]uuid.UUID, 0, len(e.State.Players))
-	for i := range e.Players {
-		id, err := uuid.Parse(i)
-		if err != nil {
-			continue
-		}
-		ids = append(ids, id)
-	}
-	return ids
+	return append([]uuid.UUID{}, e.seats...)
 }
 
 func (e *Engine) GamePlayers() []game.Player {
-	players := make([]game.Player, 0, len(e.Players))
-	for i := range e.Players {
-		players = append(players, e.Players[i].Player)
+	players := make([]game.Player, 0, len(e.seats))
+	for _, id := range e.seats {
+		if player := e.GetPlayer(id); player != nil {
+			players = append(players, player.Player)
+		}
 	}
 	return players
 }
+
+// Deal sets up a new game from the seed, in teams when there are any, with
+// the first turn handed to the start seat. The same seed, seating, teams and
+// start seat always deal the same game
+func Deal(g game.Game, players []uuid.UUID, teams []game.Team, seed int64, start int) (game.StateData, error) {
+	data, err := deal(g, players, teams, seed)
+	if err != nil {
+		return nil, err
+	}
+	return game.StartAt(data, start)
+}
+
+func deal(g game.Game, players []uuid.UUID, teams []game.Team, seed int64) (game.StateData, error) {
+	if len(teams) == 0 {
+		return g.Initialize(players, common.NewRandom(seed))
+	}
+	tg, ok := g.(game.TeamGame)
+	if !ok {
+		return nil, fmt.Errorf("Game %s is not played in teams", g.Name())
+	}
+	for _, p := range players {
+		onTeam := false
+		for _, t := range teams {
+			onTeam = onTeam || t.Has(p)
+		}
+		if !onTeam {
+			return nil, fmt.Errorf("Player %s is not on a team", p)
+		}
+	}
+	return tg.InitializeTeams(common.CloneSliceFunc(teams, cloneTeam), common.NewRandom(seed))
+}
+
+func cloneTeam(t game.Team) game.Team {
+	t.Players = common.CloneSlice(t.Players)
+	return t
+}
+
+// ChanceMove is the outcome the engine picks for a chance step, the source
+// only depends on the seed and the version the move makes so replays and
+// loads roll the same dice
+func ChanceMove(state game.Chance, seed int64, version uint64) (game.Move, error) {
+	outcomes, err := state.ChanceOutcomes()
+	if err != nil {
+		return nil, err
+	}
+	i, err := game.PickOutcome(outcomes, common.NewRandom(seed+int64(version)))
+	if err != nil {
+		return nil, err
+	}
+	return outcomes[i].Move, nil
+}
@@ -0,0 +1,209 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	messages "github.com/mat285/boardgames/pkg/messages/v1alpha1"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+)
+
+func TestSealedMoves(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	store := persist.NewMemory()
+	a, b := newRecorder("a"), newRecorder("b")
+	e := tallyTable(it, &tallyGame{Simultaneous: true}, store, a, b)
+	it.Len(a.received(messages.PacketTypeRequestMove), 1)
+	it.Len(b.received(messages.PacketTypeRequestMove), 1)
+
+	// the first pick is held back, the others only see its commitment
+	it.Nil(add(it, e, a.id, 3))
+	it.Zero(e.State.Version)
+	it.Equal([]int{0, 0}, tally(e).Picked)
+	it.NotNil(add(it, e, a.id, 1))
+	sealed := b.received(messages.PacketTypeMoveSealed)
+	it.Len(sealed, 1)
+	body, err := messages.Provider{}.ExtractMoveSealed(sealed[0])
+	it.Nil(err)
+	it.Equal(a.id, body.Player)
+	it.Empty(b.received(messages.PacketTypeMoveOpened))
+	// only b is still asked to move
+	it.Len(a.received(messages.PacketTypeRequestMove), 1)
+	it.Len(b.received(messages.PacketTypeRequestMove), 2)
+
+	// the sealed move outlives the engine being dropped
+	e.Lock()
+	it.Nil(e.Save(ctx))
+	e.Unlock()
+	loaded, err := engine.Load(ctx, &tallyGame{Simultaneous: true}, store, e.ID)
+	it.Nil(err)
+	it.True(loaded.Reconnect(a))
+	it.True(loaded.Reconnect(b))
+	it.Nil(loaded.Resume(ctx))
+	it.Len(a.received(messages.PacketTypeRequestMove), 1)
+	it.Len(b.received(messages.PacketTypeRequestMove), 3)
+	it.NotNil(add(it, loaded, a.id, 2))
+
+	it.Nil(add(it, loaded, b.id, 1))
+	state := tally(loaded)
+	it.Equal([]int{3, 1}, state.Points)
+	it.Equal(1, state.Round)
+	it.Equal(uint64(2), loaded.State.Version)
+	opened := b.received(messages.PacketTypeMoveOpened)
+	it.Len(opened, 2)
+	first, err := messages.Provider{}.ExtractMoveOpened(opened[0])
+	it.Nil(err)
+	it.Equal(a.id, first.Player)
+	commitment, err := first.Commitment()
+	it.Nil(err)
+	it.Equal(body.Commitment, commitment)
+	// the next round asks both again
+	it.Len(a.received(messages.PacketTypeRequestMove), 2)
+	it.Len(b.received(messages.PacketTypeRequestMove), 4)
+}
+
+func TestTeams(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	players := []*recorder{newRecorder("a"), newRecorder("b"), newRecorder("c"), newRecorder("d")}
+
+	solo := engine.NewEngine(&tallyGame{}, nil)
+	it.Nil(solo.Join(ctx, players[0]))
+	it.NotNil(solo.AssignTeam(players[0].id, "red"))
+
+	e := engine.NewEngine(&teamTallyGame{tallyGame{Rounds: 1}}, nil)
+	e.MoveDeadline = time.Hour
+	for _, p := range players {
+		it.Nil(e.Join(ctx, p))
+	}
+	it.NotNil(e.AssignTeam(players[0].id, ""))
+	for i, p := range players {
+		it.Nil(e.AssignTeam(p.id, []string{"red", "blue"}[i%2]))
+	}
+	// moving a player takes them off their old team
+	it.Nil(e.AssignTeam(players[0].id, "blue"))
+	it.Nil(e.AssignTeam(players[0].id, "red"))
+	it.Nil(e.Start(ctx))
+	it.NotNil(e.AssignTeam(players[0].id, "blue"))
+
+	// the game is dealt in teams, red sits first and a came back to it last
+	state := tally(e)
+	it.Len(state.Lineup, 2)
+	it.Equal("red", state.Lineup[0].Name)
+	it.Equal([]uuid.UUID{players[2].id, players[0].id}, state.Lineup[0].Players)
+	it.Equal([]uuid.UUID{players[2].id, players[0].id, players[1].id, players[3].id}, state.Players)
+
+	for i, points := range []int{1, 1, 3, 2} {
+		it.Nil(add(it, e, state.Players[i], points))
+	}
+	e.Lock()
+	standings, err := e.TeamStandings()
+	e.Unlock()
+	it.Nil(err)
+	it.Len(standings, 2)
+	it.Equal("blue", standings[0].Name)
+	it.True(standings[0].Won)
+	it.False(standings[1].Won)
+	it.Equal([]uuid.UUID{players[1].id, players[3].id}, tally(e).Winners())
+}
+
+func TestHibernate(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	store := persist.NewMemory()
+	a, b, c := newRecorder("a"), newRecorder("b"), newRecorder("c")
+	e := tallyTable(it, &tallyGame{Rounds: 1}, store, a, b)
+	queue(it, e, b.id, 2)
+	packet, err := e.MessageProvider.MessageMute(b.id, true, uuid.V4())
+	it.Nil(err)
+	send(it, e, a.id, packet)
+	it.Nil(e.Spectate(ctx, c))
+
+	// everything the table set up outlives the engine being dropped
+	e.Lock()
+	it.Nil(e.Save(ctx))
+	saved, err := e.Record()
+	e.Unlock()
+	it.Nil(err)
+	it.Len(saved.Queued, 1)
+	it.Len(saved.Muted, 1)
+	it.Len(saved.Spectators, 1)
+	woken, err := engine.Load(ctx, &tallyGame{Rounds: 1}, store, e.ID)
+	it.Nil(err)
+	woken.Lock()
+	record, err := woken.Record()
+	woken.Unlock()
+	it.Nil(err)
+	it.Equal(saved, record)
+
+	it.True(woken.Reconnect(a))
+	it.True(woken.Reconnect(b))
+	it.Nil(woken.Resume(ctx))
+	packet, err = woken.MessageProvider.MessageSendChat("gg", uuid.V4())
+	it.Nil(err)
+	send(it, woken, b.id, packet)
+	it.Empty(a.received(messages.PacketTypeChatMessages))
+	it.Len(b.received(messages.PacketTypeChatMessages), 1)
+
+	// b's queued move is played once a moves and that ends the game
+	it.Nil(add(it, woken, a.id, 1))
+	it.Equal([]int{1, 2}, tally(woken).Points)
+	it.True(tally(woken).IsDone())
+
+	// a finished game woken after its rematch was made can't make another
+	next, err := woken.Rematch(ctx)
+	it.Nil(err)
+	it.Equal(woken.ID, next.Previous)
+	_, err = woken.Rematch(ctx)
+	it.NotNil(err)
+	woken, err = engine.Load(ctx, &tallyGame{Rounds: 1}, store, e.ID)
+	it.Nil(err)
+	it.Equal(next.ID, woken.Next)
+	_, err = woken.Rematch(ctx)
+	it.NotNil(err)
+}
+
+func TestRematch(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	a, b, c := newRecorder("a"), newRecorder("b"), newRecorder("c")
+	e := tallyTable(it, &tallyGame{Rounds: 1}, nil, a, b, c)
+	for _, p := range []*recorder{a, b, c} {
+		it.Nil(add(it, e, p.id, 1))
+	}
+
+	// everyone keeps their seat and the next seat starts
+	next, err := e.Rematch(ctx)
+	it.Nil(err)
+	it.Equal(e.PlayerIDs(), next.PlayerIDs())
+	it.Nil(next.Start(ctx))
+	state := tally(next)
+	it.Equal([]uuid.UUID{a.id, b.id, c.id}, state.Players)
+	it.Equal(b.id, state.Players[state.Next])
+	it.NotNil(add(it, next, a.id, 1))
+	for _, p := range []*recorder{b, c, a} {
+		it.Nil(add(it, next, p.id, 2))
+	}
+	it.True(tally(next).IsDone())
+
+	// the start seat is part of the deal the commitment covers
+	next.Lock()
+	record, err := next.Record()
+	next.Unlock()
+	it.Nil(err)
+	it.Equal(1, record.StartSeat)
+	it.Nil(engine.Verify(&tallyGame{Rounds: 1}, *record))
+	record.StartSeat = 0
+	it.NotNil(engine.Verify(&tallyGame{Rounds: 1}, *record))
+
+	third, err := next.Rematch(ctx)
+	it.Nil(err)
+	it.Nil(third.Start(ctx))
+	state = tally(third)
+	it.Equal(c.id, state.Players[state.Next])
+}
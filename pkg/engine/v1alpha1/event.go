@@ -1,5 +1,7 @@
 package v1alpha1
 
+import "context"
+
 type Event struct {
 	Type EventType
 	Body interface{}
@@ -11,4 +13,35 @@ const (
 	EventTypeUnknown EventType = 0
 	EventTypeStop    EventType = 1
 	EventTypeSave    EventType = 2
+
+	// EventTypeJoined is sent to observers when a player takes a seat, the
+	// body is their id
+	EventTypeJoined EventType = 3
+	// EventTypeStarted is sent to observers once the game is dealt
+	EventTypeStarted EventType = 4
+	// EventTypeOver is sent to observers once the game is over and saved
+	EventTypeOver EventType = 5
+	// EventTypeTurn is sent to observers once for each turn, the body is
+	// the ids of the acting players still to move
+	EventTypeTurn EventType = 6
 )
+
+// Observer hears about the engine moving through its lobby and game, it is
+// called without the engine lock held
+type Observer func(context.Context, *Engine, Event)
+
+// Observe adds an observer to the engine
+func (e *Engine) Observe(observer Observer) {
+	e.Lock()
+	defer e.Unlock()
+	e.observers = append(e.observers, observer)
+}
+
+func (e *Engine) notify(ctx context.Context, event Event) {
+	e.Lock()
+	observers := append([]Observer{}, e.observers...)
+	e.Unlock()
+	for _, observer := range observers {
+		observer(ctx, e, event)
+	}
+}
@@ -1,14 +1,21 @@
 package v1alpha1
 
 import (
+	"context"
+
 	"github.com/blend/go-sdk/uuid"
 	connection "github.com/mat285/boardgames/pkg/connection/v1alpha1"
 	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
 )
 
 type Player struct {
 	game.Player
 	connection.Sender
+
+	// queued are moves the player made ahead of their turn, only they see
+	// them
+	queued []game.Move
 }
 
 func NewPlayer(id uuid.UUID, username string, conn connection.Sender) *Player {
@@ -17,3 +24,12 @@ func NewPlayer(id uuid.UUID, username string, conn connection.Sender) *Player {
 		Sender: conn,
 	}
 }
+
+// Send drops packets for seats without a connection, e.g. loaded games
+// that the player hasn't rejoined yet
+func (p *Player) Send(ctx context.Context, packet wire.Packet) error {
+	if p.Sender == nil {
+		return nil
+	}
+	return p.Sender.Send(ctx, packet)
+}
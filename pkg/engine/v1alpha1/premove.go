@@ -0,0 +1,129 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+)
+
+// queueMoves replaces the player's queue with the moves in the packet, they
+// aren't checked until it is the player's turn
+func (e *Engine) queueMoves(ctx context.Context, packet wire.Packet) error {
+	moves, err := e.MessageProvider.ExtractQueuedMoves(packet)
+	if err != nil {
+		return err
+	}
+	e.Lock()
+	player := e.GetPlayer(packet.Origin)
+	if player == nil {
+		e.Unlock()
+		return fmt.Errorf("No player for id %s", packet.Origin)
+	}
+	if e.State.Data != nil && e.State.Data.IsDone() {
+		e.Unlock()
+		return fmt.Errorf("Game is already over")
+	}
+	player.queued = moves
+	e.Unlock()
+	return e.sendQueued(ctx, player, moves)
+}
+
+// gameTurnQueued plays the first queued move of every acting player that
+// has one. A queued move that isn't legal any more clears the queue and the
+// player is asked for their move as usual. It reports whether a move was
+// played
+func (e *Engine) gameTurnQueued(ctx context.Context) (bool, error) {
+	e.Lock()
+	acting, err := game.ActingPlayers(e.State.Data)
+	if err != nil {
+		e.Unlock()
+		return false, err
+	}
+	type used struct {
+		player *Player
+		move   game.Move
+		left   []game.Move
+		err    error
+	}
+	var queued []used
+	for _, pid := range acting {
+		player := e.GetPlayer(pid)
+		if player == nil || len(player.queued) == 0 || e.sealedMove(pid) != nil {
+			continue
+		}
+		u := used{player: player, move: player.queued[0]}
+		if len(acting) > 1 {
+			u.err = e.seal(pid, u.move)
+		} else {
+			u.err = e.applyMove(pid, u.move)
+		}
+		if u.err == nil {
+			player.queued = player.queued[1:]
+		} else {
+			player.queued = nil
+		}
+		u.left = player.queued
+		queued = append(queued, u)
+		if len(acting) == 1 {
+			break
+		}
+	}
+	e.Unlock()
+
+	played := false
+	var firstErr error
+	for _, u := range queued {
+		err = e.sendQueued(ctx, u.player, u.left)
+		if err == nil && u.err == nil {
+			played = true
+			if len(acting) > 1 {
+				err = e.gameTurnOpen(ctx, u.player.ID)
+			} else {
+				err = e.broadcastPlayerMove(ctx, u.player.ID, u.move)
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return played, firstErr
+}
+
+func (e *Engine) sendQueued(ctx context.Context, player *Player, moves []game.Move) error {
+	msg, err := e.MessageProvider.MessageQueuedMoves(moves)
+	if err != nil {
+		return err
+	}
+	msg.Destination = player.ID
+	msg.Origin = e.ID
+	return player.Send(ctx, *msg)
+}
+
+// clearQueues drops the queued moves of the players, they were made for a
+// game that went another way. It returns the players that had any, the
+// lock has to be held
+func (e *Engine) clearQueues(pids ...uuid.UUID) []*Player {
+	var cleared []*Player
+	for _, pid := range pids {
+		player := e.GetPlayer(pid)
+		if player != nil && len(player.queued) > 0 {
+			player.queued = nil
+			cleared = append(cleared, player)
+		}
+	}
+	return cleared
+}
+
+// sendCleared tells the players their queues are empty
+func (e *Engine) sendCleared(ctx context.Context, players []*Player) error {
+	for _, player := range players {
+		err := e.sendQueued(ctx, player, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	messages "github.com/mat285/boardgames/pkg/messages/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+)
+
+// queue replaces the player's queued moves
+func queue(it *assert.Assertions, e *engine.Engine, player uuid.UUID, points ...int) {
+	var moves []game.Move
+	for _, p := range points {
+		moves = append(moves, &tallyMove{Player: player, Add: p})
+	}
+	packet, err := e.MessageProvider.MessageQueueMoves(moves, player)
+	it.Nil(err)
+	packet.Origin = player
+	it.Nil(e.Receive(context.Background(), *packet))
+}
+
+// queued returns the points of the queue the client was last sent
+func queued(it *assert.Assertions, e *engine.Engine, client *recorder) []int {
+	packets := client.received(messages.PacketTypeQueuedMoves)
+	it.NotEmpty(packets)
+	moves, err := e.MessageProvider.ExtractQueuedMoves(packets[len(packets)-1])
+	it.Nil(err)
+	points := []int{}
+	for _, move := range moves {
+		points = append(points, move.(*tallyMove).Add)
+	}
+	return points
+}
+
+// send plays the packet from the player
+func send(it *assert.Assertions, e *engine.Engine, player uuid.UUID, packet *wire.Packet) {
+	packet.Origin = player
+	it.Nil(e.Receive(context.Background(), *packet))
+}
+
+func TestQueuedMoves(t *testing.T) {
+	it := assert.New(t)
+	a, b, c := newRecorder("a"), newRecorder("b"), newRecorder("c")
+	e := tallyTable(it, &tallyGame{}, nil, a, b, c)
+
+	// the queue is only shown to its owner
+	queue(it, e, b.id, 2, 3)
+	queue(it, e, c.id, 5)
+	it.Equal([]int{2, 3}, queued(it, e, b))
+	it.Equal([]int{5}, queued(it, e, c))
+	it.Empty(a.received(messages.PacketTypeQueuedMoves))
+
+	// b's first queued move is played as soon as it is their turn, c's
+	// isn't legal so their queue is dropped and they are asked instead
+	it.Nil(add(it, e, a.id, 1))
+	it.Equal([]int{1, 2, 0}, tally(e).Points)
+	it.Equal([]int{3}, queued(it, e, b))
+	it.Empty(b.received(messages.PacketTypeRequestMove))
+	it.Equal([]int{}, queued(it, e, c))
+	it.Len(c.received(messages.PacketTypeRequestMove), 1)
+	it.Empty(a.received(messages.PacketTypeQueuedMoves))
+
+	// a takeback drops every queue, they were made for the other game
+	it.Nil(add(it, e, c.id, 1))
+	it.Equal([]int{1, 2, 1}, tally(e).Points)
+	packet, err := e.MessageProvider.MessageTakebackRequest(c.id)
+	it.Nil(err)
+	send(it, e, c.id, packet)
+	votes := a.received(messages.PacketTypeTakebackVote)
+	it.Len(votes, 1)
+	vote, err := e.MessageProvider.ExtractTakebackVote(votes[0])
+	it.Nil(err)
+	for _, voter := range []*recorder{a, b} {
+		packet, err := e.MessageProvider.MessageTakebackAnswer(vote.ID, true, voter.id)
+		it.Nil(err)
+		send(it, e, voter.id, packet)
+	}
+	it.Equal([]int{1, 2, 0}, tally(e).Points)
+	it.Equal([]int{}, queued(it, e, b))
+	it.Nil(add(it, e, c.id, 2))
+	it.Nil(add(it, e, a.id, 1))
+	it.Equal([]int{2, 2, 2}, tally(e).Points)
+	it.Len(b.received(messages.PacketTypeRequestMove), 1)
+
+	// resigning drops the queue too
+	it.Nil(add(it, e, b.id, 3))
+	queue(it, e, b.id, 1)
+	packet, err = e.MessageProvider.MessageResign(b.id)
+	it.Nil(err)
+	send(it, e, b.id, packet)
+	it.Equal([]int{}, queued(it, e, b))
+	it.Nil(add(it, e, c.id, 1))
+	it.Nil(add(it, e, a.id, 1))
+	it.Equal([]int{3, 5, 3}, tally(e).Points)
+	it.Equal(c.id, tally(e).Players[tally(e).Next])
+}
@@ -0,0 +1,304 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/blend/go-sdk/uuid"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	migration "github.com/mat285/boardgames/pkg/migration/v1alpha1"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+)
+
+// Record captures the engine in its persisted form
+func (e *Engine) Record() (*game.Record, error) {
+	record := &game.Record{
+		ID:         e.ID,
+		Game:       e.Game.Name(),
+		Variant:    game.GameVariant(e.Game),
+		Created:    e.Created,
+		APIVersion: APIVersion,
+		Version:    e.State.Version,
+		Seed:       e.Seed,
+		StartSeat:  e.StartSeat,
+		Players:    e.GamePlayers(),
+		Teams:      common.CloneSliceFunc(e.teams, cloneTeam),
+		Private:    e.Private,
+		Casual:     e.Casual,
+		Seeded:     e.Seeded,
+		Previous:   e.Previous,
+		Next:       e.Next,
+		Initial:    e.initial,
+		Moves:      append([]game.RecordedMove{}, e.Moves...),
+		Commitment: e.Commitment,
+		Reveal:     e.reveal,
+		Chat:       append([]game.ChatMessage{}, e.chat...),
+
+		MoveDeadline: e.MoveDeadline,
+		Deadline:     e.deadline,
+	}
+	for _, s := range e.sealed {
+		record.Sealed = append(record.Sealed, *s.SealedMove)
+	}
+	for _, id := range e.seats {
+		player := e.GetPlayer(id)
+		if player == nil || len(player.queued) == 0 {
+			continue
+		}
+		queued := game.QueuedMoves{Player: player.ID}
+		for _, move := range player.queued {
+			so, err := e.Game.SerializeMove(move)
+			if err != nil {
+				return nil, err
+			}
+			queued.Moves = append(queued.Moves, so)
+		}
+		record.Queued = append(record.Queued, queued)
+	}
+	record.Muted = e.mutes()
+	for _, spectator := range e.spectators {
+		record.Spectators = append(record.Spectators, spectator.Player)
+	}
+	sort.Slice(record.Spectators, func(i, j int) bool {
+		return record.Spectators[i].ID.ToFullString() < record.Spectators[j].ID.ToFullString()
+	})
+	if e.State.Data != nil {
+		so, err := e.MessageProvider.SerializeState(e.State.Data)
+		if err != nil {
+			return nil, err
+		}
+		record.State = so
+	}
+	standings, err := e.Standings()
+	if err != nil {
+		return nil, err
+	}
+	record.Standings = standings
+	record.TeamStandings, err = e.TeamStandings()
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Load restores a persisted engine for the game, migrating it from whatever
+// api version it was written with. Players need to rejoin to get packets.
+func Load(ctx context.Context, g game.Game, store persist.Interface, id uuid.UUID) (*Engine, error) {
+	if store == nil {
+		return nil, fmt.Errorf("No persistence")
+	}
+	obj, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	record, err := RecordFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	if record.Game != g.Name() {
+		return nil, fmt.Errorf("Record is for game %s not %s", record.Game, g.Name())
+	}
+	err = migration.MigrateRecord(migration.Migrations(g), record, APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	e := NewEngine(g, nil)
+	e.ID = record.ID
+	e.Seed = record.Seed
+	e.StartSeat = record.StartSeat
+	e.Created = record.Created
+	e.Private = record.Private
+	e.Casual = record.Casual
+	e.Seeded = record.Seeded
+	e.Previous = record.Previous
+	e.Next = record.Next
+	e.MoveDeadline = record.MoveDeadline
+	e.deadline = record.Deadline
+	e.Persist = store
+	for _, p := range record.Players {
+		e.seat(NewPlayer(p.ID, p.Username, nil))
+	}
+	e.State = game.NewState(record.Players)
+	e.State.Version = record.Version
+	e.Moves = record.Moves
+	e.initial = record.Initial
+	e.Commitment = record.Commitment
+	e.teams = record.Teams
+	e.reveal = record.Reveal
+	e.chat = record.Chat
+	for _, mute := range record.Muted {
+		e.addMute(mute.Player, mute.Muted)
+	}
+	for _, spectator := range record.Spectators {
+		if e.spectators == nil {
+			e.spectators = make(map[string]*Player)
+		}
+		e.spectators[spectator.ID.ToFullString()] = NewPlayer(spectator.ID, spectator.Username, nil)
+	}
+
+	if record.State != nil {
+		e.State.Data, err = g.DeserializeState(record.State)
+		if err != nil {
+			return nil, err
+		}
+		err = g.Load(e.State.Data)
+		if err != nil {
+			return nil, err
+		}
+		e.started = true
+		for i := range record.Sealed {
+			move, err := g.DeserializeMove(record.Sealed[i].Move)
+			if err != nil {
+				return nil, err
+			}
+			e.sealed = append(e.sealed, sealedMove{SealedMove: &record.Sealed[i], move: move})
+		}
+		for _, queued := range record.Queued {
+			player := e.GetPlayer(queued.Player)
+			if player == nil {
+				return nil, fmt.Errorf("Queued moves for %s who isn't seated", queued.Player)
+			}
+			for _, so := range queued.Moves {
+				move, err := g.DeserializeMove(so)
+				if err != nil {
+					return nil, err
+				}
+				player.queued = append(player.queued, move)
+			}
+		}
+		if !e.deadline.IsZero() {
+			// players were told about the turn before it was saved
+			e.turn = e.State.Version + 1
+		}
+	}
+	return e, nil
+}
+
+// Replay applies every recorded move to the initial state of the record,
+// records from older api versions are migrated first
+func Replay(g game.Game, record game.Record) (game.StateData, error) {
+	record.Moves = append([]game.RecordedMove{}, record.Moves...)
+	err := migration.MigrateRecord(migration.Migrations(g), &record, APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	if record.Initial == nil {
+		return nil, fmt.Errorf("Record has no initial state")
+	}
+	return replay(g, record.Initial, record.Seed, record.Moves)
+}
+
+func replay(g game.Game, initial *game.SerializedObject, seed int64, moves []game.RecordedMove) (game.StateData, error) {
+	states, err := replayStates(g, initial, seed, moves)
+	if err != nil {
+		return nil, err
+	}
+	return states[len(states)-1], nil
+}
+
+// replayStates applies the moves one at a time, the state from before the
+// move at i is at i and the final state is last
+func replayStates(g game.Game, initial *game.SerializedObject, seed int64, moves []game.RecordedMove) ([]game.StateData, error) {
+	state, err := g.DeserializeState(initial)
+	if err != nil {
+		return nil, err
+	}
+	states := []game.StateData{state}
+	for i, recorded := range moves {
+		switch {
+		case recorded.Resigned:
+			state, err = game.Resign(state, recorded.Player)
+			if err != nil {
+				return nil, err
+			}
+		case recorded.TakeBack > 0:
+			idx, err := takebackIndex(moves[:i], recorded.Player, recorded.TakeBack)
+			if err != nil {
+				return nil, err
+			}
+			if !game.CanTakeBack(state, states[idx]) {
+				return nil, fmt.Errorf("Takeback at version %d is not allowed", recorded.Version)
+			}
+			state = states[idx]
+		default:
+			move, err := g.DeserializeMove(recorded.Move)
+			if err != nil {
+				return nil, err
+			}
+			if chance, ok := game.IsChance(state); ok {
+				err = checkChance(g, chance, seed, recorded)
+				if err != nil {
+					return nil, err
+				}
+			}
+			res, err := move.Apply(state)
+			if err != nil {
+				return nil, err
+			}
+			if !res.Valid {
+				return nil, fmt.Errorf("Invalid move at version %d", recorded.Version)
+			}
+			state = res.State
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// checkChance makes sure a logged chance move is the one the seed picks
+func checkChance(g game.Game, state game.Chance, seed int64, recorded game.RecordedMove) error {
+	if !recorded.Player.Equal(game.SystemActor) {
+		return fmt.Errorf("Move at version %d should be made by chance", recorded.Version)
+	}
+	expected, err := ChanceMove(state, seed, recorded.Version)
+	if err != nil {
+		return err
+	}
+	so, err := g.SerializeMove(expected)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(so.Data, recorded.Move.Data) {
+		return fmt.Errorf("Chance move at version %d is not the one the seed rolls", recorded.Version)
+	}
+	return nil
+}
+
+// RecordFromObject reads a record out of a persisted object, stores that
+// don't keep the go type hand back the raw json
+func RecordFromObject(obj *persist.Object) (*game.Record, error) {
+	if obj == nil {
+		return nil, fmt.Errorf("No object")
+	}
+	var record game.Record
+	switch typed := obj.Data.(type) {
+	case game.Record:
+		record = typed
+	case *game.Record:
+		if typed == nil {
+			return nil, fmt.Errorf("No record")
+		}
+		record = *typed
+	case []byte:
+		if err := json.Unmarshal(typed, &record); err != nil {
+			return nil, err
+		}
+	default:
+		data, err := json.Marshal(typed)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+	}
+	if len(record.APIVersion) == 0 {
+		record.APIVersion = obj.APIVersion
+	}
+	return &record, nil
+}
@@ -0,0 +1,93 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/splendor"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	migration "github.com/mat285/boardgames/pkg/migration/v1alpha1"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+)
+
+const legacyVersion = "v0alpha1"
+
+// legacyGame wrapped its moves in an envelope before the current version
+type legacyGame struct {
+	game.Game
+	migrated int
+}
+
+type legacyMove struct {
+	Move json.RawMessage
+}
+
+func (g *legacyGame) Migrations() []migration.Migration {
+	return []migration.Migration{{
+		From: legacyVersion,
+		To:   engine.APIVersion,
+		Move: func(obj *game.SerializedObject) (*game.SerializedObject, error) {
+			var old legacyMove
+			if err := json.Unmarshal(obj.Data, &old); err != nil {
+				return nil, err
+			}
+			g.migrated++
+			return &game.SerializedObject{ID: obj.ID, Data: old.Move}, nil
+		},
+	}}
+}
+
+// legacyRecord writes the record back the way the old version did
+func legacyRecord(it *assert.Assertions, record game.Record) game.Record {
+	record.APIVersion = legacyVersion
+	moves := make([]game.RecordedMove, len(record.Moves))
+	for i, recorded := range record.Moves {
+		data, err := json.Marshal(legacyMove{Move: recorded.Move.Data})
+		it.Nil(err)
+		recorded.Move = &game.SerializedObject{ID: recorded.Move.ID, Data: data}
+		moves[i] = recorded
+	}
+	record.Moves = moves
+	return record
+}
+
+func TestLoadMigrates(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	record := playRecord(it, 7, 6)
+	old := legacyRecord(it, record)
+
+	g, err := splendor.New(nil)
+	it.Nil(err)
+	legacy := &legacyGame{Game: g}
+	state, err := engine.Replay(legacy, old)
+	it.Nil(err)
+	it.Equal(6, legacy.migrated)
+	expected, err := engine.Replay(g, record)
+	it.Nil(err)
+	it.Equal(expected, state)
+
+	// a replay without the migration can't read the old moves
+	_, err = engine.Replay(g, old)
+	it.NotNil(err)
+
+	store := persist.NewMemory()
+	old.ID = uuid.V4()
+	_, err = store.CheckAndSet(ctx, persist.Object{
+		Meta: persist.Meta{ID: old.ID, APIVersion: legacyVersion, ObjectVersion: old.Version},
+		Data: old,
+	})
+	it.Nil(err)
+	legacy.migrated = 0
+	e, err := engine.Load(ctx, legacy, store, old.ID)
+	it.Nil(err)
+	it.Equal(6, legacy.migrated)
+	saved, err := e.Record()
+	it.Nil(err)
+	it.Equal(engine.APIVersion, saved.APIVersion)
+	it.Equal(record.Moves[0].Move.Data, saved.Moves[0].Move.Data)
+}
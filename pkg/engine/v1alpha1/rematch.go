@@ -0,0 +1,54 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+// Rematch sets up a new game once this one is over, with the same game and
+// config, the same players in the same seats and teams and the start player
+// moved one seat on.
+// The new game keeps the observers and still has to be started, the link
+// to it is saved so a reloaded game isn't rematched twice
+func (e *Engine) Rematch(ctx context.Context) (*Engine, error) {
+	e.Lock()
+	defer e.Unlock()
+	if !e.started || !e.State.Data.IsDone() {
+		return nil, fmt.Errorf("Game is not over")
+	}
+	if e.Next != nil {
+		return nil, fmt.Errorf("Rematch already created as %s", e.Next)
+	}
+	next := NewEngine(e.Game, nil)
+	for _, id := range e.seats {
+		player := e.GetPlayer(id)
+		if player == nil {
+			continue
+		}
+		next.seat(NewPlayer(player.ID, player.Username, player.Sender))
+	}
+	if len(e.seats) > 0 {
+		next.StartSeat = (e.StartSeat + 1) % len(e.seats)
+	}
+	next.State = game.NewState(next.GamePlayers())
+	for _, team := range e.teams {
+		next.teams = append(next.teams, cloneTeam(team))
+	}
+	next.Private = e.Private
+	next.Casual = e.Casual
+	next.MoveDeadline = e.MoveDeadline
+	next.Persist = e.Persist
+	next.ChatHistory = e.ChatHistory
+	next.ChatFilter = e.ChatFilter
+	next.SpectatorChat = e.SpectatorChat
+	next.observers = append([]Observer{}, e.observers...)
+	next.Previous = e.ID
+	e.Next = next.ID
+	if err := e.Save(ctx); err != nil {
+		e.Next = nil
+		return nil, err
+	}
+	return next, nil
+}
@@ -0,0 +1,216 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	messages "github.com/mat285/boardgames/pkg/messages/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+)
+
+// takeback is waiting on the other players to agree to undo a move
+type takeback struct {
+	messages.MessageBodyTakeback
+	voters []uuid.UUID
+	votes  []uuid.UUID
+}
+
+// lastMove finds the player's latest move that can still be taken back,
+// the move and everything after it are undone
+func lastMove(moves []game.RecordedMove, player uuid.UUID) (uint64, error) {
+	for i := len(moves) - 1; i >= 0; i-- {
+		recorded := moves[i]
+		if recorded.Player.Equal(player) && recorded.Move != nil {
+			return recorded.Version, nil
+		}
+		if recorded.Move == nil || recorded.Player.Equal(game.SystemActor) {
+			break
+		}
+	}
+	return 0, fmt.Errorf("No move to take back for %s", player)
+}
+
+// takebackIndex finds the move the player wants to take back. Nothing
+// after it can be a roll, a resignation or another takeback, those can't
+// be undone
+func takebackIndex(moves []game.RecordedMove, player uuid.UUID, version uint64) (int, error) {
+	last, err := lastMove(moves, player)
+	if err != nil {
+		return 0, err
+	}
+	if last != version {
+		return 0, fmt.Errorf("Version %d is not the last move of %s", version, player)
+	}
+	// a move split up by a migration is taken back whole
+	for i := len(moves) - 1; i >= 0; i-- {
+		if moves[i].Version == version && (i == 0 || moves[i-1].Version != version) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("No move at version %d", version)
+}
+
+// history returns every state of the game so far, the one from before the
+// move at i is at i. Loaded games rebuild it from the record
+func (e *Engine) history() ([]game.StateData, error) {
+	if len(e.states) == len(e.Moves)+1 {
+		return e.states, nil
+	}
+	if e.initial == nil {
+		return nil, fmt.Errorf("No initial state")
+	}
+	states, err := replayStates(e.Game, e.initial, e.Seed, e.Moves)
+	if err != nil {
+		return nil, err
+	}
+	e.states = states
+	return states, nil
+}
+
+// requestTakeback asks everyone else still playing to let the player take
+// back their last move, with nobody to ask it happens straight away
+func (e *Engine) requestTakeback(ctx context.Context, pid uuid.UUID) error {
+	e.Lock()
+	if !e.started || e.State.Data.IsDone() {
+		e.Unlock()
+		return fmt.Errorf("Game is not being played")
+	}
+	if e.takeback != nil {
+		e.Unlock()
+		return fmt.Errorf("Takeback already requested")
+	}
+	version, err := lastMove(e.Moves, pid)
+	if err == nil {
+		err = e.checkTakeback(pid, version)
+	}
+	if err != nil {
+		e.Unlock()
+		return err
+	}
+	tb := &takeback{
+		MessageBodyTakeback: messages.MessageBodyTakeback{
+			ID:      uuid.V4(),
+			Player:  pid,
+			Version: version,
+		},
+	}
+	for _, id := range e.seats {
+		if !id.Equal(pid) && !game.IsEliminated(e.State.Data, id) {
+			tb.voters = append(tb.voters, id)
+		}
+	}
+	if len(tb.voters) == 0 {
+		cleared, err := e.rewind(pid, version)
+		e.Unlock()
+		if err != nil {
+			return err
+		}
+		return e.takenBack(ctx, tb.ID, cleared)
+	}
+	e.takeback = tb
+	e.Unlock()
+
+	msg, err := e.MessageProvider.MessageTakebackVote(tb.MessageBodyTakeback)
+	if err != nil {
+		return err
+	}
+	return e.Broadcast(ctx, msg, pid)
+}
+
+// answerTakeback counts a vote, one refusal is enough to turn it down and
+// the game rewinds once everyone asked has agreed
+func (e *Engine) answerTakeback(ctx context.Context, packet wire.Packet) error {
+	answer, err := e.MessageProvider.ExtractTakebackAnswer(packet)
+	if err != nil {
+		return err
+	}
+	pid := packet.Origin
+
+	e.Lock()
+	tb := e.takeback
+	if tb == nil || !tb.ID.Equal(answer.ID) {
+		e.Unlock()
+		return fmt.Errorf("No takeback %s to answer", answer.ID)
+	}
+	if !excludeUUID(pid, tb.voters...) || excludeUUID(pid, tb.votes...) {
+		e.Unlock()
+		return fmt.Errorf("Player %s cannot vote on this takeback", pid)
+	}
+	if !answer.Approve {
+		e.takeback = nil
+		e.Unlock()
+		return e.broadcastTakebackResult(ctx, tb.ID, false)
+	}
+	tb.votes = append(tb.votes, pid)
+	if len(tb.votes) < len(tb.voters) {
+		e.Unlock()
+		return nil
+	}
+	e.takeback = nil
+	cleared, err := e.rewind(tb.Player, tb.Version)
+	e.Unlock()
+	if err != nil {
+		return err
+	}
+	return e.takenBack(ctx, tb.ID, cleared)
+}
+
+// takenBack tells everyone the takeback went through and the players who
+// lost their queued moves to it
+func (e *Engine) takenBack(ctx context.Context, id uuid.UUID, cleared []*Player) error {
+	err := e.broadcastTakebackResult(ctx, id, true)
+	if err != nil {
+		return err
+	}
+	return e.sendCleared(ctx, cleared)
+}
+
+// checkTakeback makes sure the move can still be undone, the lock has to be
+// held
+func (e *Engine) checkTakeback(pid uuid.UUID, version uint64) error {
+	idx, err := takebackIndex(e.Moves, pid, version)
+	if err != nil {
+		return err
+	}
+	states, err := e.history()
+	if err != nil {
+		return err
+	}
+	if !game.CanTakeBack(e.State.Data, states[idx]) {
+		return fmt.Errorf("Game does not allow taking that move back")
+	}
+	return nil
+}
+
+// rewind takes the game back to before the move and logs the takeback,
+// every queued move is dropped and the players that had one are returned.
+// The lock has to be held
+func (e *Engine) rewind(pid uuid.UUID, version uint64) ([]*Player, error) {
+	err := e.checkTakeback(pid, version)
+	if err != nil {
+		return nil, err
+	}
+	idx, _ := takebackIndex(e.Moves, pid, version)
+	states, _ := e.history()
+
+	e.sealed = nil
+	e.State.Data = states[idx]
+	e.State.Version++
+	e.Moves = append(e.Moves, game.RecordedMove{
+		Version:  e.State.Version,
+		Player:   pid,
+		TakeBack: version,
+	})
+	e.states = append(states, e.State.Data)
+	return e.clearQueues(e.seats...), nil
+}
+
+func (e *Engine) broadcastTakebackResult(ctx context.Context, id uuid.UUID, approved bool) error {
+	msg, err := e.MessageProvider.MessageTakebackResult(id, approved)
+	if err != nil {
+		return err
+	}
+	return e.Broadcast(ctx, msg)
+}
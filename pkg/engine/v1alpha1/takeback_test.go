@@ -0,0 +1,106 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	messages "github.com/mat285/boardgames/pkg/messages/v1alpha1"
+)
+
+// requestTakeback asks for the player's last move to be taken back
+func requestTakeback(it *assert.Assertions, e *engine.Engine, player uuid.UUID) error {
+	packet, err := e.MessageProvider.MessageTakebackRequest(player)
+	it.Nil(err)
+	packet.Origin = player
+	return e.Receive(context.Background(), *packet)
+}
+
+// vote answers the takeback for the player
+func vote(it *assert.Assertions, e *engine.Engine, player, id uuid.UUID, approve bool) error {
+	packet, err := e.MessageProvider.MessageTakebackAnswer(id, approve, player)
+	it.Nil(err)
+	packet.Origin = player
+	return e.Receive(context.Background(), *packet)
+}
+
+// takebackResults returns the results the client was sent, approved or not
+func takebackResults(it *assert.Assertions, client *recorder) []bool {
+	var results []bool
+	for _, packet := range client.received(messages.PacketTypeTakebackResult) {
+		res, err := messages.Provider{}.ExtractTakebackResult(packet)
+		it.Nil(err)
+		results = append(results, res.Approved)
+	}
+	return results
+}
+
+func TestTakeback(t *testing.T) {
+	it := assert.New(t)
+	a, b, c := newRecorder("a"), newRecorder("b"), newRecorder("c")
+	e := tallyTable(it, &tallyGame{}, nil, a, b, c)
+	it.Nil(add(it, e, a.id, 1))
+	it.Nil(add(it, e, b.id, 2))
+	it.NotNil(requestTakeback(it, e, c.id))
+
+	// everyone else is asked, the player asking isn't
+	it.Nil(requestTakeback(it, e, b.id))
+	it.Empty(b.received(messages.PacketTypeTakebackVote))
+	votes := c.received(messages.PacketTypeTakebackVote)
+	it.Len(votes, 1)
+	first, err := e.MessageProvider.ExtractTakebackVote(votes[0])
+	it.Nil(err)
+	it.Equal(b.id, first.Player)
+	it.Equal(uint64(2), first.Version)
+
+	// only one takeback is asked for at a time and only its voters answer
+	it.NotNil(requestTakeback(it, e, b.id))
+	it.NotNil(requestTakeback(it, e, a.id))
+	it.NotNil(vote(it, e, b.id, first.ID, true))
+	it.NotNil(vote(it, e, a.id, uuid.V4(), true))
+
+	// one refusal turns it down and the game goes on as it was
+	it.Nil(vote(it, e, a.id, first.ID, true))
+	it.NotNil(vote(it, e, a.id, first.ID, true))
+	it.Nil(vote(it, e, c.id, first.ID, false))
+	it.Equal([]bool{false}, takebackResults(it, b))
+	it.Equal([]int{1, 2, 0}, tally(e).Points)
+	it.Equal(uint64(2), e.State.Version)
+	it.NotNil(vote(it, e, a.id, first.ID, true))
+
+	// once everyone agrees the move is undone and logged
+	it.Nil(requestTakeback(it, e, b.id))
+	votes = a.received(messages.PacketTypeTakebackVote)
+	it.Len(votes, 2)
+	second, err := e.MessageProvider.ExtractTakebackVote(votes[1])
+	it.Nil(err)
+	it.Nil(vote(it, e, c.id, second.ID, true))
+	it.Equal([]int{1, 2, 0}, tally(e).Points)
+	it.Nil(vote(it, e, a.id, second.ID, true))
+	it.Equal([]bool{false, true}, takebackResults(it, c))
+	state := tally(e)
+	it.Equal([]int{1, 0, 0}, state.Points)
+	it.Equal(b.id, state.Players[state.Next])
+	it.Equal(uint64(3), e.State.Version)
+	last := e.Moves[len(e.Moves)-1]
+	it.Equal(b.id, last.Player)
+	it.Equal(uint64(2), last.TakeBack)
+
+	// the history replays to the same game
+	e.Lock()
+	record, err := e.Record()
+	e.Unlock()
+	it.Nil(err)
+	it.Len(record.Played(), 1)
+	replayed, err := engine.Replay(&tallyGame{}, *record)
+	it.Nil(err)
+	it.Equal(state, replayed)
+
+	// a three can't be unseen so it can't be taken back
+	it.Nil(add(it, e, b.id, 3))
+	it.NotNil(requestTakeback(it, e, b.id))
+	it.Len(c.received(messages.PacketTypeTakebackVote), 2)
+	it.Equal([]int{1, 3, 0}, tally(e).Points)
+}
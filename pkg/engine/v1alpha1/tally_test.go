@@ -0,0 +1,382 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+)
+
+var (
+	_ game.Game         = new(tallyGame)
+	_ game.TeamGame     = new(teamTallyGame)
+	_ game.Resignable   = tallyState{}
+	_ game.Teamed       = tallyState{}
+	_ game.Simultaneous = tallyState{}
+	_ game.Takebacks    = tallyState{}
+	_ game.Rotated      = tallyState{}
+)
+
+var tallyID = uuid.MustParse("0b0c4d9e-7a61-4c1f-8d2e-5f3a9b6c1e07")
+
+// tallyGame is a small game for the engine tests, each round every player
+// adds one to three points and the most points after the rounds wins. It
+// is played in turns or, when simultaneous, everyone picks at once
+type tallyGame struct {
+	tallyMeta
+	tallySerializer
+
+	Rounds       int
+	Simultaneous bool
+}
+
+// teamTallyGame is dealt with the teams picked in the lobby, the team with
+// the most points between them wins
+type teamTallyGame struct {
+	tallyGame
+}
+
+type tallySerializer = game.TypedSerializer[tallyState, tallyMove, *tallyMove]
+
+type tallyMeta struct{}
+
+func (tallyMeta) Meta() game.Meta { return tallyMeta{} }
+func (tallyMeta) ID() uuid.UUID   { return tallyID }
+func (tallyMeta) Name() string    { return "tally" }
+
+func (g *tallyGame) Initialize(players []uuid.UUID, _ common.Random) (game.StateData, error) {
+	rounds := g.Rounds
+	if rounds == 0 {
+		rounds = 3
+	}
+	return tallyState{
+		Players:      common.CloneSlice(players),
+		Simultaneous: g.Simultaneous,
+		Rounds:       rounds,
+		Points:       make([]int, len(players)),
+		Picked:       make([]int, len(players)),
+	}, nil
+}
+
+func (g *tallyGame) Load(game.StateData) error { return nil }
+
+func (g *teamTallyGame) InitializeTeams(teams []game.Team, r common.Random) (game.StateData, error) {
+	var players []uuid.UUID
+	for _, team := range teams {
+		players = append(players, team.Players...)
+	}
+	raw, err := g.Initialize(players, r)
+	if err != nil {
+		return nil, err
+	}
+	state := raw.(tallyState)
+	state.Lineup = teams
+	return state, nil
+}
+
+type tallyState struct {
+	tallyMeta
+	Players      []uuid.UUID
+	Simultaneous bool
+	Rounds       int
+	Round        int
+	// Next is the seat to move when played in turns, rounds start at Start
+	Next  int
+	Start int
+	// Picked holds the sealed picks of the round, zero for no pick yet
+	Picked []int
+	Points []int
+	Out    []uuid.UUID
+	Lineup []game.Team
+	// Revealed counts the threes played, each one stands in for turning
+	// over a card nobody had seen
+	Revealed int
+}
+
+func (s tallyState) Clone() game.StateData {
+	s.Players = common.CloneSlice(s.Players)
+	s.Picked = common.CloneSlice(s.Picked)
+	s.Points = common.CloneSlice(s.Points)
+	s.Out = common.CloneSlice(s.Out)
+	s.Lineup = common.CloneSlice(s.Lineup)
+	return s
+}
+
+func (s tallyState) seat(player uuid.UUID) int {
+	for i, p := range s.Players {
+		if p.Equal(player) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s tallyState) isOut(player uuid.UUID) bool {
+	for _, out := range s.Out {
+		if out.Equal(player) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s tallyState) ActingPlayers() ([]uuid.UUID, error) {
+	if s.IsDone() {
+		return nil, nil
+	}
+	if !s.Simultaneous {
+		return []uuid.UUID{s.Players[s.Next]}, nil
+	}
+	var acting []uuid.UUID
+	for i, p := range s.Players {
+		if s.Picked[i] == 0 && !s.isOut(p) {
+			acting = append(acting, p)
+		}
+	}
+	return acting, nil
+}
+
+func (s tallyState) CurrentPlayer() (uuid.UUID, error) {
+	acting, err := s.ActingPlayers()
+	if err != nil {
+		return nil, err
+	}
+	if len(acting) == 0 {
+		return s.Players[s.Next], nil
+	}
+	return acting[0], nil
+}
+
+func (s tallyState) IsDone() bool {
+	return s.Round >= s.Rounds || len(s.Out) >= len(s.Players)-1
+}
+
+func (s tallyState) Winners() []uuid.UUID {
+	if !s.IsDone() {
+		return nil
+	}
+	if len(s.Lineup) > 0 {
+		best, total := 0, -1
+		for i, team := range s.Lineup {
+			sum := 0
+			for _, p := range team.Players {
+				sum += s.Points[s.seat(p)]
+			}
+			if sum > total {
+				best, total = i, sum
+			}
+		}
+		return common.CloneSlice(s.Lineup[best].Players)
+	}
+	var winners []uuid.UUID
+	most := -1
+	for i, p := range s.Players {
+		if s.isOut(p) {
+			continue
+		}
+		if s.Points[i] > most {
+			winners, most = nil, s.Points[i]
+		}
+		if s.Points[i] == most {
+			winners = append(winners, p)
+		}
+	}
+	return winners
+}
+
+func (s tallyState) ValidMoves() ([]game.Move, error) {
+	acting, err := s.ActingPlayers()
+	if err != nil {
+		return nil, err
+	}
+	var moves []game.Move
+	for _, p := range acting {
+		for add := 1; add <= 3; add++ {
+			moves = append(moves, &tallyMove{Player: p, Add: add})
+		}
+	}
+	return moves, nil
+}
+
+func (s tallyState) Eliminated() []uuid.UUID {
+	return common.CloneSlice(s.Out)
+}
+
+func (s tallyState) Resign(player uuid.UUID) (game.StateData, error) {
+	if s.seat(player) < 0 || s.isOut(player) {
+		return nil, fmt.Errorf("Player %s is not playing", player)
+	}
+	s = s.Clone().(tallyState)
+	s.Out = append(s.Out, player)
+	s.Picked[s.seat(player)] = 0
+	if !s.Simultaneous && s.Players[s.Next].Equal(player) {
+		return s.advance(), nil
+	}
+	if s.Simultaneous {
+		return s.settle(), nil
+	}
+	return s, nil
+}
+
+func (s tallyState) StartAt(seat int) (game.StateData, error) {
+	if seat >= len(s.Players) {
+		return nil, fmt.Errorf("Invalid start seat %d", seat)
+	}
+	s.Next, s.Start = seat, seat
+	return s, nil
+}
+
+// CanTakeBack refuses to go back past a three, it can't be unseen
+func (s tallyState) CanTakeBack(to game.StateData) bool {
+	return to.(tallyState).Revealed == s.Revealed
+}
+
+func (s tallyState) Teams() ([]game.Team, error) {
+	return s.Lineup, nil
+}
+
+// advance moves the turn on to the next seat still playing, a new round
+// starts once it goes back around
+func (s tallyState) advance() tallyState {
+	for i := 1; i <= len(s.Players); i++ {
+		next := (s.Next + i) % len(s.Players)
+		if s.isOut(s.Players[next]) {
+			continue
+		}
+		// seats are counted from the start seat
+		n := len(s.Players)
+		if (next-s.Start+n)%n <= (s.Next-s.Start+n)%n {
+			s.Round++
+		}
+		s.Next = next
+		return s
+	}
+	return s
+}
+
+// settle adds up the picks once everyone still playing has made one
+func (s tallyState) settle() tallyState {
+	for i, p := range s.Players {
+		if s.Picked[i] == 0 && !s.isOut(p) {
+			return s
+		}
+	}
+	for i := range s.Players {
+		s.Points[i] += s.Picked[i]
+		s.Picked[i] = 0
+	}
+	s.Round++
+	return s
+}
+
+type tallyMove struct {
+	tallyMeta
+	Player uuid.UUID
+	Add    int
+}
+
+func (m *tallyMove) Apply(raw game.StateData) (*game.MoveResult, error) {
+	s, ok := raw.(tallyState)
+	if !ok {
+		return nil, fmt.Errorf("Invalid State Type")
+	}
+	seat := s.seat(m.Player)
+	acting, err := s.ActingPlayers()
+	if err != nil {
+		return nil, err
+	}
+	if m.Add < 1 || m.Add > 3 || seat < 0 || !containsID(acting, m.Player) {
+		return &game.MoveResult{Valid: false, State: s}, nil
+	}
+	s = s.Clone().(tallyState)
+	if m.Add == 3 {
+		s.Revealed++
+	}
+	if s.Simultaneous {
+		s.Picked[seat] = m.Add
+		return &game.MoveResult{Valid: true, State: s.settle()}, nil
+	}
+	s.Points[seat] += m.Add
+	return &game.MoveResult{Valid: true, State: s.advance()}, nil
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, other := range ids {
+		if other.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// recorder keeps every packet it is sent
+type recorder struct {
+	sync.Mutex
+	id       uuid.UUID
+	username string
+	packets  []wire.Packet
+}
+
+func newRecorder(username string) *recorder {
+	return &recorder{id: uuid.V4(), username: username}
+}
+
+func (r *recorder) GetID() uuid.UUID    { return r.id }
+func (r *recorder) GetUsername() string { return r.username }
+
+func (r *recorder) Send(_ context.Context, packet wire.Packet) error {
+	r.Lock()
+	defer r.Unlock()
+	r.packets = append(r.packets, packet)
+	return nil
+}
+
+// received returns the packets of the type sent so far
+func (r *recorder) received(t wire.PacketType) []wire.Packet {
+	r.Lock()
+	defer r.Unlock()
+	var packets []wire.Packet
+	for _, packet := range r.packets {
+		if packet.Type == t {
+			packets = append(packets, packet)
+		}
+	}
+	return packets
+}
+
+// tallyTable seats the players at a correspondence game so every packet is
+// played as soon as it is received, then starts it
+func tallyTable(it *assert.Assertions, g game.Game, store persist.Interface, players ...*recorder) *engine.Engine {
+	ctx := context.Background()
+	e := engine.NewEngine(g, nil)
+	e.MoveDeadline = time.Hour
+	e.Persist = store
+	for _, p := range players {
+		it.Nil(e.Join(ctx, p))
+	}
+	it.Nil(e.Start(ctx))
+	return e
+}
+
+// add sends the player's move to the engine
+func add(it *assert.Assertions, e *engine.Engine, player uuid.UUID, points int) error {
+	packet, err := e.MessageProvider.MessagePlayerMove(&tallyMove{Player: player, Add: points}, player)
+	it.Nil(err)
+	packet.Origin = player
+	return e.Receive(context.Background(), *packet)
+}
+
+// tally returns the state the engine is at
+func tally(e *engine.Engine) tallyState {
+	e.Lock()
+	defer e.Unlock()
+	return e.State.Data.(tallyState)
+}
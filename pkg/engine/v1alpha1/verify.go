@@ -0,0 +1,110 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	migration "github.com/mat285/boardgames/pkg/migration/v1alpha1"
+)
+
+// Verify checks a finished game against the commitment made when it
+// started. The reveal has to hash to the commitment, dealing again from the
+// revealed seed has to give the revealed initial state and replaying the
+// move log from it has to end in the recorded state and standings
+func Verify(g game.Game, record game.Record) error {
+	if len(record.Commitment) == 0 || record.Reveal == nil {
+		return fmt.Errorf("Record has no commitment")
+	}
+	reveal := *record.Reveal
+	if reveal.Initial == nil {
+		return fmt.Errorf("Reveal has no initial state")
+	}
+	commitment, err := reveal.Commitment()
+	if err != nil {
+		return err
+	}
+	if commitment != record.Commitment {
+		return fmt.Errorf("Reveal does not match the commitment %s", record.Commitment)
+	}
+
+	// the commitment covers the state as it was written, migrate a copy
+	record.Moves = append([]game.RecordedMove{}, record.Moves...)
+	record.Initial = reveal.Initial
+	err = migration.MigrateRecord(migration.Migrations(g), &record, APIVersion)
+	if err != nil {
+		return err
+	}
+
+	initial, err := g.DeserializeState(record.Initial)
+	if err != nil {
+		return err
+	}
+	// pick up the config the game was created with
+	err = g.Load(initial)
+	if err != nil {
+		return err
+	}
+	pids := make([]uuid.UUID, len(record.Players))
+	for i := range record.Players {
+		pids[i] = record.Players[i].ID
+	}
+	dealt, err := Deal(g, pids, record.Teams, reveal.Seed, record.StartSeat)
+	if err != nil {
+		return err
+	}
+	if err := sameState(g, dealt, initial); err != nil {
+		return fmt.Errorf("Revealed seed does not deal the initial state: %w", err)
+	}
+
+	final, err := replay(g, record.Initial, reveal.Seed, record.Moves)
+	if err != nil {
+		return err
+	}
+	if record.Standings != nil {
+		standings, err := game.Standings(final, pids)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(standings, record.Standings) {
+			return fmt.Errorf("Move log does not end in the recorded standings")
+		}
+		if record.TeamStandings != nil {
+			teams, err := game.StateTeams(final, record.Teams)
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(game.TeamStandings(final, teams, standings), record.TeamStandings) {
+				return fmt.Errorf("Move log does not end in the recorded team standings")
+			}
+		}
+	}
+	if record.State == nil {
+		return nil
+	}
+	recorded, err := g.DeserializeState(record.State)
+	if err != nil {
+		return err
+	}
+	if err := sameState(g, final, recorded); err != nil {
+		return fmt.Errorf("Move log does not end in the recorded state: %w", err)
+	}
+	return nil
+}
+
+func sameState(g game.Game, a, b game.StateData) error {
+	sa, err := g.SerializeState(a)
+	if err != nil {
+		return err
+	}
+	sb, err := g.SerializeState(b)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sa.Data, sb.Data) {
+		return fmt.Errorf("States differ")
+	}
+	return nil
+}
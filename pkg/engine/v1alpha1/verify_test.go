@@ -0,0 +1,180 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games/machikoro"
+	mkgame "github.com/mat285/boardgames/games/machikoro/pkg/game"
+	"github.com/mat285/boardgames/games/splendor"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+func playRecord(it *assert.Assertions, seed int64, moves int) game.Record {
+	g, err := splendor.New(nil)
+	it.Nil(err)
+	return playGameRecord(it, g, seed, moves)
+}
+
+func playGameRecord(it *assert.Assertions, g game.Game, seed int64, moves int) game.Record {
+	players := []game.Player{{ID: uuid.V4()}, {ID: uuid.V4()}}
+	state, err := g.Initialize([]uuid.UUID{players[0].ID, players[1].ID}, common.NewRandom(seed))
+	it.Nil(err)
+	initial, err := g.SerializeState(state)
+	it.Nil(err)
+	reveal, err := game.NewReveal(seed, initial)
+	it.Nil(err)
+	commitment, err := reveal.Commitment()
+	it.Nil(err)
+
+	record := game.Record{
+		Game:       g.Name(),
+		APIVersion: engine.APIVersion,
+		Seed:       seed,
+		Players:    players,
+		Initial:    initial,
+		Commitment: commitment,
+		Reveal:     reveal,
+	}
+	for i := 0; i < moves; i++ {
+		record.Version++
+		var move game.Move
+		pid := players[i%len(players)].ID
+		if chance, ok := game.IsChance(state); ok {
+			move, err = engine.ChanceMove(chance, seed, record.Version)
+			it.Nil(err)
+			pid = game.SystemActor
+		} else {
+			valid, err := state.ValidMoves()
+			it.Nil(err)
+			move = valid[len(valid)-1]
+		}
+		res, err := move.Apply(state)
+		it.Nil(err)
+		it.True(res.Valid)
+		so, err := g.SerializeMove(move)
+		it.Nil(err)
+		record.Moves = append(record.Moves, game.RecordedMove{Version: record.Version, Player: pid, Move: so})
+		state = res.State
+	}
+	record.State, err = g.SerializeState(state)
+	it.Nil(err)
+	return record
+}
+
+func TestVerify(t *testing.T) {
+	it := assert.New(t)
+	g, err := splendor.New(nil)
+	it.Nil(err)
+
+	record := playRecord(it, 42, 10)
+	it.Nil(engine.Verify(g, record))
+
+	// a reveal that doesn't hash to the commitment
+	tampered := record
+	reveal := *record.Reveal
+	reveal.Seed++
+	tampered.Reveal = &reveal
+	it.NotNil(engine.Verify(g, tampered))
+
+	// a deal the seed doesn't produce, committed to honestly
+	other := playRecord(it, 7, 0)
+	stacked := record
+	reveal = *other.Reveal
+	reveal.Seed = record.Reveal.Seed
+	stacked.Reveal = &reveal
+	stacked.Commitment, err = reveal.Commitment()
+	it.Nil(err)
+	it.NotNil(engine.Verify(g, stacked))
+
+	// a move log that doesn't end where the record does
+	short := record
+	short.Moves = record.Moves[:len(record.Moves)-1]
+	it.NotNil(engine.Verify(g, short))
+
+	// standings have to be the ones the final state ranks
+	final, err := g.DeserializeState(record.State)
+	it.Nil(err)
+	ranked := record
+	ranked.Standings, err = game.Standings(final, []uuid.UUID{record.Players[0].ID, record.Players[1].ID})
+	it.Nil(err)
+	it.Nil(engine.Verify(g, ranked))
+	ranked.Standings = []game.Standing{{Score: game.Score{Player: record.Players[1].ID, Points: 99}, Place: 1}}
+	it.NotNil(engine.Verify(g, ranked))
+}
+
+func TestVerifyChance(t *testing.T) {
+	it := assert.New(t)
+	g, err := machikoro.New(nil)
+	it.Nil(err)
+
+	record := playGameRecord(it, g, 42, 30)
+	it.Nil(engine.Verify(g, record))
+	_, err = engine.Replay(g, record)
+	it.Nil(err)
+
+	// the first roll is a single die at version 2, swap in another face
+	loaded := record
+	loaded.Moves = append([]game.RecordedMove{}, record.Moves...)
+	move, err := g.DeserializeMove(record.Moves[1].Move)
+	it.Nil(err)
+	dice := move.(*mkgame.Move).Dice
+	it.NotNil(dice)
+	face := dice.Values[0]%6 + 1
+	loaded.Moves[1].Move, err = g.SerializeMove(&mkgame.Move{Dice: &mkgame.DiceMove{Values: []int{face}}})
+	it.Nil(err)
+	it.NotNil(engine.Verify(g, loaded))
+	_, err = engine.Replay(g, loaded)
+	it.NotNil(err)
+}
+
+func TestReplayResign(t *testing.T) {
+	it := assert.New(t)
+	g, err := splendor.New(nil)
+	it.Nil(err)
+
+	record := playRecord(it, 42, 3)
+	record.Moves = append(record.Moves, game.RecordedMove{
+		Version:  uint64(len(record.Moves) + 1),
+		Player:   record.Players[0].ID,
+		Resigned: true,
+	})
+	state, err := engine.Replay(g, record)
+	it.Nil(err)
+	it.True(state.IsDone())
+	it.Equal([]uuid.UUID{record.Players[1].ID}, state.Winners())
+
+	standings, err := game.Standings(state, []uuid.UUID{record.Players[0].ID, record.Players[1].ID})
+	it.Nil(err)
+	it.Equal(record.Players[0].ID, standings[1].Player)
+	it.Equal(2, standings[1].Place)
+}
+
+func TestReplayTakeBack(t *testing.T) {
+	it := assert.New(t)
+	g, err := splendor.New(nil)
+	it.Nil(err)
+
+	record := playRecord(it, 42, 3)
+	before := record
+	before.Moves = record.Moves[:2]
+	expected, err := engine.Replay(g, before)
+	it.Nil(err)
+
+	record.Moves = append(record.Moves, game.RecordedMove{
+		Version:  4,
+		Player:   record.Players[0].ID,
+		TakeBack: 3,
+	})
+	state, err := engine.Replay(g, record)
+	it.Nil(err)
+	it.Equal(expected, state)
+
+	// only the player's own last move can be taken back
+	record.Moves[3].TakeBack = 2
+	_, err = engine.Replay(g, record)
+	it.NotNil(err)
+}
@@ -0,0 +1,66 @@
+package v1alpha1
+
+import (
+	"sort"
+	"sync"
+)
+
+// Friends holds who each user added, by username, they are friends once
+// both added each other
+type Friends struct {
+	sync.Mutex
+	added map[string]map[string]bool
+}
+
+func NewFriends() *Friends {
+	return &Friends{added: make(map[string]map[string]bool)}
+}
+
+// Add sends the friend a request from the user, or accepts theirs
+func (f *Friends) Add(user, friend string) {
+	f.Lock()
+	defer f.Unlock()
+	if f.added[user] == nil {
+		f.added[user] = make(map[string]bool)
+	}
+	f.added[user][friend] = true
+}
+
+// Remove unfriends the two users, it also drops a request either way
+func (f *Friends) Remove(user, friend string) {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.added[user], friend)
+	delete(f.added[friend], user)
+}
+
+// AreFriends returns if both users added each other
+func (f *Friends) AreFriends(a, b string) bool {
+	f.Lock()
+	defer f.Unlock()
+	return f.added[a][b] && f.added[b][a]
+}
+
+// List returns the user's friends, the users that added them and the ones
+// they added that haven't added them back yet, each sorted
+func (f *Friends) List(user string) (friends, incoming, outgoing []string) {
+	f.Lock()
+	defer f.Unlock()
+	friends, incoming, outgoing = []string{}, []string{}, []string{}
+	for added := range f.added[user] {
+		if f.added[added][user] {
+			friends = append(friends, added)
+		} else {
+			outgoing = append(outgoing, added)
+		}
+	}
+	for other, added := range f.added {
+		if added[user] && !f.added[user][other] {
+			incoming = append(incoming, other)
+		}
+	}
+	sort.Strings(friends)
+	sort.Strings(incoming)
+	sort.Strings(outgoing)
+	return friends, incoming, outgoing
+}
@@ -0,0 +1,84 @@
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	friends "github.com/mat285/boardgames/pkg/friends/v1alpha1"
+)
+
+func TestFriends(t *testing.T) {
+	it := assert.New(t)
+	f := friends.NewFriends()
+
+	// a request only goes one way until it is added back
+	f.Add("ann", "bob")
+	f.Add("cat", "ann")
+	it.False(f.AreFriends("ann", "bob"))
+	mine, incoming, outgoing := f.List("ann")
+	it.Empty(mine)
+	it.Equal([]string{"cat"}, incoming)
+	it.Equal([]string{"bob"}, outgoing)
+
+	f.Add("bob", "ann")
+	f.Add("ann", "cat")
+	it.True(f.AreFriends("bob", "ann"))
+	mine, incoming, outgoing = f.List("ann")
+	it.Equal([]string{"bob", "cat"}, mine)
+	it.Empty(incoming)
+	it.Empty(outgoing)
+
+	// removing drops it both ways
+	f.Remove("bob", "ann")
+	it.False(f.AreFriends("ann", "bob"))
+	mine, _, outgoing = f.List("ann")
+	it.Equal([]string{"cat"}, mine)
+	it.Empty(outgoing)
+}
+
+func TestInvitations(t *testing.T) {
+	it := assert.New(t)
+	invitations := friends.NewInvitations()
+	game, other := uuid.V4(), uuid.V4()
+	host, guest, stranger := uuid.V4(), uuid.V4(), uuid.V4()
+	now := time.Now().UTC()
+
+	// nobody is invited to a private game yet, a public one takes anyone
+	it.False(invitations.CanJoin(game, guest, true))
+	it.True(invitations.CanJoin(game, stranger, false))
+
+	first, created := invitations.Invite(friends.Invitation{Game: game, From: host, To: guest, Created: now})
+	it.True(created)
+	it.Equal(friends.StatusPending, first.Status)
+	again, created := invitations.Invite(friends.Invitation{Game: game, From: host, To: guest})
+	it.False(created)
+	it.Equal(first.ID, again.ID)
+	second, _ := invitations.Invite(friends.Invitation{Game: other, From: host, To: guest, Created: now.Add(time.Minute)})
+
+	pending := invitations.Pending(guest)
+	it.Len(pending, 2)
+	it.Equal(second.ID, pending[0].ID)
+	it.Empty(invitations.Pending(stranger))
+	_, has := invitations.Get(first.ID, stranger)
+	it.False(has)
+
+	// an accepted invitation still lets the player in, a declined one
+	// doesn't and neither can be answered twice
+	it.True(invitations.CanJoin(game, guest, true))
+	it.False(invitations.CanJoin(game, stranger, true))
+	accepted, err := invitations.Answer(first.ID, friends.StatusAccepted)
+	it.Nil(err)
+	it.Equal(friends.StatusAccepted, accepted.Status)
+	it.True(invitations.CanJoin(game, guest, true))
+	_, err = invitations.Answer(first.ID, friends.StatusDeclined)
+	it.NotNil(err)
+
+	_, err = invitations.Answer(second.ID, friends.StatusDeclined)
+	it.Nil(err)
+	it.False(invitations.CanJoin(other, guest, true))
+	it.Empty(invitations.Pending(guest))
+	_, err = invitations.Answer(uuid.V4(), friends.StatusAccepted)
+	it.NotNil(err)
+}
@@ -0,0 +1,123 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// Status is where an invitation is at
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusAccepted Status = "accepted"
+	StatusDeclined Status = "declined"
+)
+
+// Invitation asks a friend to take a seat at a game before it starts
+type Invitation struct {
+	ID           uuid.UUID
+	Game         uuid.UUID
+	GameName     string
+	From         uuid.UUID
+	FromUsername string
+	To           uuid.UUID
+	Created      time.Time
+	Status       Status
+}
+
+// Invitations holds every invitation sent, keyed by id
+type Invitations struct {
+	sync.Mutex
+	byID map[string]*Invitation
+}
+
+func NewInvitations() *Invitations {
+	return &Invitations{byID: make(map[string]*Invitation)}
+}
+
+// Invite keeps the invitation as pending, it returns the one already
+// pending if the player was invited to the game before and if it is new
+func (i *Invitations) Invite(inv Invitation) (Invitation, bool) {
+	i.Lock()
+	defer i.Unlock()
+	for _, existing := range i.byID {
+		if existing.Game.Equal(inv.Game) && existing.To.Equal(inv.To) && existing.Status == StatusPending {
+			return *existing, false
+		}
+	}
+	if inv.ID == nil {
+		inv.ID = uuid.V4()
+	}
+	if inv.Created.IsZero() {
+		inv.Created = time.Now().UTC()
+	}
+	inv.Status = StatusPending
+	i.byID[inv.ID.ToFullString()] = &inv
+	return inv, true
+}
+
+// Get returns the invitation if it was sent to the player
+func (i *Invitations) Get(id, player uuid.UUID) (Invitation, bool) {
+	i.Lock()
+	defer i.Unlock()
+	inv, has := i.byID[id.ToFullString()]
+	if !has || !inv.To.Equal(player) {
+		return Invitation{}, false
+	}
+	return *inv, true
+}
+
+// Pending lists the invitations the player hasn't answered, newest first
+func (i *Invitations) Pending(player uuid.UUID) []Invitation {
+	i.Lock()
+	defer i.Unlock()
+	res := []Invitation{}
+	for _, inv := range i.byID {
+		if inv.To.Equal(player) && inv.Status == StatusPending {
+			res = append(res, *inv)
+		}
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Created.After(res[b].Created)
+	})
+	return res
+}
+
+// Answer sets the status of a pending invitation
+func (i *Invitations) Answer(id uuid.UUID, status Status) (Invitation, error) {
+	i.Lock()
+	defer i.Unlock()
+	inv, has := i.byID[id.ToFullString()]
+	if !has {
+		return Invitation{}, fmt.Errorf("No invitation %s", id)
+	}
+	if inv.Status != StatusPending {
+		return Invitation{}, fmt.Errorf("Invitation already %s", inv.Status)
+	}
+	inv.Status = status
+	return *inv, nil
+}
+
+// Invited returns if the player was invited to the game and hasn't turned
+// it down
+func (i *Invitations) Invited(game, player uuid.UUID) bool {
+	i.Lock()
+	defer i.Unlock()
+	for _, inv := range i.byID {
+		if inv.Game.Equal(game) && inv.To.Equal(player) && inv.Status != StatusDeclined {
+			return true
+		}
+	}
+	return false
+}
+
+// CanJoin returns if the player may take a seat at the game, private games
+// only take the players invited to them whatever way they come in by
+func (i *Invitations) CanJoin(game, player uuid.UUID, private bool) bool {
+	return !private || i.Invited(game, player)
+}
@@ -0,0 +1,52 @@
+package v1alpha1
+
+import "github.com/blend/go-sdk/uuid"
+
+// Simultaneous is a state where more than the current player may act, like
+// drafting, secret bids or others reacting out of turn with a challenge or
+// a block. When more than one player is acting the engine seals each move
+// as it comes in and applies them all in seat order once every acting
+// player has moved, so nobody sees the others' picks before making their own
+type Simultaneous interface {
+	StateData
+	ActingPlayers() ([]uuid.UUID, error)
+}
+
+// ActingPlayers returns everyone who may move in the state, that's just the
+// current player unless the state is Simultaneous
+func ActingPlayers(state StateData) ([]uuid.UUID, error) {
+	if simultaneous, ok := state.(Simultaneous); ok {
+		return simultaneous.ActingPlayers()
+	}
+	pid, err := state.CurrentPlayer()
+	if err != nil {
+		return nil, err
+	}
+	return []uuid.UUID{pid}, nil
+}
+
+// SealedMove is a move held back until every acting player has moved, the
+// others only see its commitment until it is opened
+type SealedMove struct {
+	Player uuid.UUID
+	Nonce  []byte
+	Move   *SerializedObject
+}
+
+func NewSealedMove(player uuid.UUID, move *SerializedObject) (*SealedMove, error) {
+	nonce, err := NewNonce()
+	if err != nil {
+		return nil, err
+	}
+	return &SealedMove{
+		Player: player,
+		Nonce:  nonce,
+		Move:   move,
+	}, nil
+}
+
+// Commitment is the hex sha256 of the sealed move, it is sent out in place
+// of the move until it is opened
+func (s SealedMove) Commitment() (string, error) {
+	return commitment(s)
+}
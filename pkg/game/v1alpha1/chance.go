@@ -0,0 +1,72 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/blend/go-sdk/uuid"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+)
+
+var (
+	// SystemActor is the current player whenever chance decides the next
+	// step, no client can ever send moves as it
+	SystemActor = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+)
+
+// Chance is a state that can hand the next step to chance, like a dice roll.
+// While CurrentPlayer returns SystemActor the engine picks one of the
+// outcomes with its seeded source and logs it like any other move
+type Chance interface {
+	StateData
+	ChanceOutcomes() ([]ChanceOutcome, error)
+}
+
+// ChanceOutcome is one way chance can go, it happens Weight times out of the
+// total weight of all the outcomes
+type ChanceOutcome struct {
+	Move   Move
+	Weight int
+}
+
+// IsChance returns the state as a Chance when the next step belongs to the
+// system actor
+func IsChance(state StateData) (Chance, bool) {
+	chance, ok := state.(Chance)
+	if !ok {
+		return nil, false
+	}
+	pid, err := state.CurrentPlayer()
+	if err != nil || !pid.Equal(SystemActor) {
+		return nil, false
+	}
+	return chance, true
+}
+
+// PickOutcome draws the index of one of the outcomes by weight
+func PickOutcome(outcomes []ChanceOutcome, r common.Random) (int, error) {
+	total := TotalWeight(outcomes)
+	if total <= 0 {
+		return 0, fmt.Errorf("No chance outcomes")
+	}
+	n := r.Intn(total)
+	for i := range outcomes {
+		if outcomes[i].Weight <= 0 {
+			continue
+		}
+		if n < outcomes[i].Weight {
+			return i, nil
+		}
+		n -= outcomes[i].Weight
+	}
+	return 0, fmt.Errorf("No chance outcomes")
+}
+
+func TotalWeight(outcomes []ChanceOutcome) int {
+	total := 0
+	for _, o := range outcomes {
+		if o.Weight > 0 {
+			total += o.Weight
+		}
+	}
+	return total
+}
@@ -0,0 +1,17 @@
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// ChatMessage is something said at the table, it isn't part of the game
+// and never changes the state
+type ChatMessage struct {
+	ID       uuid.UUID
+	Player   uuid.UUID
+	Username string
+	Text     string
+	Time     time.Time
+}
@@ -0,0 +1,65 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	CodecJSON = "json"
+	CodecGob  = "gob"
+)
+
+// Codec turns game objects into bytes and back
+type Codec interface {
+	Name() string
+	Marshal(interface{}) ([]byte, error)
+	Unmarshal([]byte, interface{}) error
+}
+
+func CodecByName(name string) (Codec, error) {
+	switch name {
+	case "", CodecJSON:
+		return JSONCodec{}, nil
+	case CodecGob:
+		return GobCodec{}, nil
+	default:
+		return nil, fmt.Errorf("Unknown codec %s", name)
+	}
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) Name() string {
+	return CodecJSON
+}
+
+func (JSONCodec) Marshal(i interface{}) ([]byte, error) {
+	return json.Marshal(i)
+}
+
+func (JSONCodec) Unmarshal(data []byte, out interface{}) error {
+	return json.Unmarshal(data, out)
+}
+
+// GobCodec is a compact binary codec, the bytes are only readable from go
+type GobCodec struct{}
+
+func (GobCodec) Name() string {
+	return CodecGob
+}
+
+func (GobCodec) Marshal(i interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(i)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, out interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(out)
+}
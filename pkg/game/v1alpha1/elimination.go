@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// Eliminating is a state that can knock players out while the others play
+// on, Eliminated lists them in the order they went out. Eliminated players
+// never act again and only watch the rest of the game
+type Eliminating interface {
+	StateData
+	Eliminated() []uuid.UUID
+}
+
+// Resignable is a state that lets a player resign, they are eliminated
+// like anyone the game knocks out
+type Resignable interface {
+	Eliminating
+	Resign(player uuid.UUID) (StateData, error)
+}
+
+// IsEliminated returns if the player is out of the game
+func IsEliminated(state StateData, player uuid.UUID) bool {
+	eliminating, ok := state.(Eliminating)
+	if !ok {
+		return false
+	}
+	for _, out := range eliminating.Eliminated() {
+		if out.Equal(player) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resign takes the player out of the game
+func Resign(state StateData, player uuid.UUID) (StateData, error) {
+	resignable, ok := state.(Resignable)
+	if !ok {
+		return nil, fmt.Errorf("Game does not allow resigning")
+	}
+	if state.IsDone() {
+		return nil, fmt.Errorf("Game is already over")
+	}
+	if IsEliminated(state, player) {
+		return nil, fmt.Errorf("Player %s is already out", player)
+	}
+	return resignable.Resign(player)
+}
@@ -1,10 +1,15 @@
 package v1alpha1
 
-import "github.com/blend/go-sdk/uuid"
+import (
+	"github.com/blend/go-sdk/uuid"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+)
 
 type Game interface {
 	Meta
 	Serializer
-	Initialize([]uuid.UUID) (StateData, error)
+	// Initialize deals a new game for the players in seat order, any
+	// shuffling or dealing has to draw from the random source it is given
+	Initialize([]uuid.UUID, common.Random) (StateData, error)
 	Load(StateData) error
 }
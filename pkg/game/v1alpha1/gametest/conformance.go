@@ -0,0 +1,461 @@
+package gametest
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/blend/go-sdk/uuid"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+const (
+	DefaultGames      = 1000
+	DefaultShortGames = 50
+	DefaultRaceGames  = 5
+	DefaultMaxMoves   = 5000
+
+	// EnvFull set to anything plays at least DefaultGames whatever Games
+	// asks for, e.g. GAMETEST_FULL=1 go test ./games/...
+	EnvFull = "GAMETEST_FULL"
+)
+
+// Options tune a conformance run, the zero value plays DefaultGames games
+// of two to four players. Slow games should set a small Games and leave the
+// full sweep to EnvFull. With -short or -race the run is capped at
+// DefaultShortGames or DefaultRaceGames whatever Games asks for
+type Options struct {
+	Games    int
+	Players  []int
+	MaxMoves int
+	Seed     int64
+
+	// MovesPerTurn caps how many of the listed moves are checked each turn,
+	// a random sample is taken when there are more. Zero checks them all
+	MovesPerTurn int
+}
+
+func (o Options) games() int {
+	games := DefaultGames
+	if o.Games > 0 {
+		games = o.Games
+	}
+	if len(os.Getenv(EnvFull)) > 0 && games < DefaultGames {
+		games = DefaultGames
+	}
+	if testing.Short() && games > DefaultShortGames {
+		games = DefaultShortGames
+	}
+	if raceEnabled && games > DefaultRaceGames {
+		games = DefaultRaceGames
+	}
+	return games
+}
+
+func (o Options) players() []int {
+	if len(o.Players) > 0 {
+		return o.Players
+	}
+	return []int{2, 3, 4}
+}
+
+func (o Options) maxMoves() int {
+	if o.MaxMoves > 0 {
+		return o.MaxMoves
+	}
+	return DefaultMaxMoves
+}
+
+// Run plays seeded random games of g checking the contract every game has
+// to hold, a failure reports the seed that reproduces it
+func Run(t *testing.T, g game.Game, opts Options) {
+	t.Helper()
+	players := opts.players()
+	for i := 0; i < opts.games(); i++ {
+		seed := opts.Seed + int64(i)
+		err := Play(g, players[i%len(players)], seed, opts)
+		if err != nil {
+			t.Fatalf("%s game %d (seed %d, %d players): %v", g.Name(), i, seed, players[i%len(players)], err)
+		}
+	}
+}
+
+// Play runs a single random game to completion
+func Play(g game.Game, numPlayers int, seed int64, opts Options) error {
+	r := rand.New(rand.NewSource(seed))
+	pids := make([]uuid.UUID, numPlayers)
+	for i := range pids {
+		pids[i] = seededUUID(r)
+	}
+	state, err := CheckSeeded(g, pids, seed)
+	if err != nil {
+		return err
+	}
+	if err := CheckResign(state, pids); err != nil {
+		return err
+	}
+
+	for moves := 0; !state.IsDone(); moves++ {
+		if moves >= opts.maxMoves() {
+			return fmt.Errorf("not finished after %d moves", moves)
+		}
+		before, err := CheckRoundTrip(g, state)
+		if err != nil {
+			return fmt.Errorf("move %d: %w", moves, err)
+		}
+		if err := CheckClone(g, state, before); err != nil {
+			return fmt.Errorf("move %d: %w", moves, err)
+		}
+		pid, err := state.CurrentPlayer()
+		if err != nil {
+			return fmt.Errorf("move %d: current player: %w", moves, err)
+		}
+		if chance, ok := game.IsChance(state); ok {
+			next, err := playChance(g, chance, before, r)
+			if err != nil {
+				return fmt.Errorf("move %d: %w", moves, err)
+			}
+			state = next
+			continue
+		}
+		if !containsUUID(pids, pid) {
+			return fmt.Errorf("move %d: current player %s is not seated", moves, pid)
+		}
+		acting, err := game.ActingPlayers(state)
+		if err != nil {
+			return fmt.Errorf("move %d: acting players: %w", moves, err)
+		}
+		for _, id := range acting {
+			if !containsUUID(pids, id) {
+				return fmt.Errorf("move %d: acting player %s is not seated", moves, id)
+			}
+		}
+
+		valid, err := state.ValidMoves()
+		if err != nil {
+			return fmt.Errorf("move %d: valid moves: %w", moves, err)
+		}
+		if len(valid) == 0 {
+			return fmt.Errorf("move %d: no valid moves", moves)
+		}
+		if err := CheckPhase(state, valid); err != nil {
+			return fmt.Errorf("move %d: %w", moves, err)
+		}
+		if opts.MovesPerTurn > 0 && len(valid) > opts.MovesPerTurn {
+			r.Shuffle(len(valid), func(i, j int) { valid[i], valid[j] = valid[j], valid[i] })
+			valid = valid[:opts.MovesPerTurn]
+		}
+		results, err := applyAll(g, state, valid)
+		if err != nil {
+			return fmt.Errorf("move %d: %w", moves, err)
+		}
+		if err := checkUnchanged(g, state, before); err != nil {
+			return fmt.Errorf("move %d: %w", moves, err)
+		}
+		state = results[r.Intn(len(results))]
+	}
+
+	for _, winner := range state.Winners() {
+		if !containsUUID(pids, winner) {
+			return fmt.Errorf("winner %s is not seated", winner)
+		}
+	}
+	if err := CheckStandings(state, pids); err != nil {
+		return err
+	}
+	return CheckTeams(state, pids)
+}
+
+// CheckResign resigns every player but the last one by one, states that
+// allow it have to skip the resigned players, end with the last one
+// winning and rank the others in reverse order of leaving
+func CheckResign(state game.StateData, pids []uuid.UUID) error {
+	if _, ok := state.(game.Resignable); !ok || len(pids) < 2 {
+		return nil
+	}
+	for i, pid := range pids[:len(pids)-1] {
+		next, err := game.Resign(state, pid)
+		if err != nil {
+			return fmt.Errorf("resign %d: %w", i, err)
+		}
+		if !game.IsEliminated(next, pid) {
+			return fmt.Errorf("resign %d: player %s is not eliminated", i, pid)
+		}
+		if game.IsEliminated(state, pid) {
+			return fmt.Errorf("resign %d: resigning mutated the input state", i)
+		}
+		state = next
+		current, err := state.CurrentPlayer()
+		if err != nil {
+			return fmt.Errorf("resign %d: current player: %w", i, err)
+		}
+		if game.IsEliminated(state, current) {
+			return fmt.Errorf("resign %d: eliminated player %s is still playing", i, current)
+		}
+	}
+	last := pids[len(pids)-1]
+	winners := state.Winners()
+	if !state.IsDone() || len(winners) != 1 || !winners[0].Equal(last) {
+		return fmt.Errorf("last player left did not win")
+	}
+	standings, err := game.Standings(state, pids)
+	if err != nil {
+		return fmt.Errorf("standings: %w", err)
+	}
+	for i, standing := range standings {
+		if !standing.Player.Equal(pids[len(pids)-1-i]) || standing.Place != i+1 {
+			return fmt.Errorf("standings don't follow the order players left")
+		}
+	}
+	return nil
+}
+
+// CheckTeams makes sure a state played in teams puts every seated player on
+// exactly one team and only ever has whole teams win
+func CheckTeams(state game.StateData, pids []uuid.UUID) error {
+	teamed, ok := state.(game.Teamed)
+	if !ok {
+		return nil
+	}
+	teams, err := teamed.Teams()
+	if err != nil {
+		return fmt.Errorf("teams: %w", err)
+	}
+	for _, pid := range pids {
+		count := 0
+		for _, team := range teams {
+			if team.Has(pid) {
+				count++
+			}
+		}
+		if count != 1 {
+			return fmt.Errorf("player %s is on %d teams", pid, count)
+		}
+	}
+	winners := state.Winners()
+	for _, team := range teams {
+		won := 0
+		for _, p := range team.Players {
+			if containsUUID(winners, p) {
+				won++
+			}
+		}
+		if won > 0 && won != len(team.Players) {
+			return fmt.Errorf("only part of team %s won", team.Name)
+		}
+	}
+	return nil
+}
+
+// CheckStandings makes sure every seated player is ranked once and the
+// first place is exactly the winners
+func CheckStandings(state game.StateData, pids []uuid.UUID) error {
+	standings, err := game.Standings(state, pids)
+	if err != nil {
+		return fmt.Errorf("standings: %w", err)
+	}
+	if len(standings) != len(pids) {
+		return fmt.Errorf("standings rank %d players not %d", len(standings), len(pids))
+	}
+	var first []uuid.UUID
+	for _, standing := range standings {
+		if !containsUUID(pids, standing.Player) {
+			return fmt.Errorf("ranked player %s is not seated", standing.Player)
+		}
+		if standing.Place == 1 {
+			first = append(first, standing.Player)
+		}
+	}
+	winners := state.Winners()
+	if len(first) != len(winners) {
+		return fmt.Errorf("standings put %d players first but there are %d winners", len(first), len(winners))
+	}
+	for _, winner := range winners {
+		if !containsUUID(first, winner) {
+			return fmt.Errorf("winner %s is not ranked first", winner)
+		}
+	}
+	return nil
+}
+
+// playChance applies every outcome of a chance step and follows one of them
+// picked by weight
+func playChance(g game.Game, state game.Chance, before *game.SerializedObject, r *rand.Rand) (game.StateData, error) {
+	outcomes, err := state.ChanceOutcomes()
+	if err != nil {
+		return nil, fmt.Errorf("chance outcomes: %w", err)
+	}
+	if len(outcomes) == 0 {
+		return nil, fmt.Errorf("no chance outcomes")
+	}
+	moves := make([]game.Move, len(outcomes))
+	for i := range outcomes {
+		if outcomes[i].Weight <= 0 {
+			return nil, fmt.Errorf("chance outcome %d has weight %d", i, outcomes[i].Weight)
+		}
+		moves[i] = outcomes[i].Move
+	}
+	results, err := applyAll(g, state, moves)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUnchanged(g, state, before); err != nil {
+		return nil, err
+	}
+	i, err := game.PickOutcome(outcomes, r)
+	if err != nil {
+		return nil, err
+	}
+	return results[i], nil
+}
+
+// CheckSeeded initializes the game twice from the same seed and makes sure
+// both deals are the same
+func CheckSeeded(g game.Game, pids []uuid.UUID, seed int64) (game.StateData, error) {
+	state, err := g.Initialize(pids, common.NewRandom(seed))
+	if err != nil {
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+	again, err := g.Initialize(pids, common.NewRandom(seed))
+	if err != nil {
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+	so, err := g.SerializeState(state)
+	if err != nil {
+		return nil, fmt.Errorf("serialize state: %w", err)
+	}
+	other, err := g.SerializeState(again)
+	if err != nil {
+		return nil, fmt.Errorf("serialize state: %w", err)
+	}
+	if !bytes.Equal(so.Data, other.Data) {
+		return nil, fmt.Errorf("initialize dealt differently from the same seed")
+	}
+	return state, nil
+}
+
+// applyAll applies every move to the same parent concurrently so running
+// under -race also catches moves writing into state they share
+func applyAll(g game.Game, state game.StateData, moves []game.Move) ([]game.StateData, error) {
+	results := make([]game.StateData, len(moves))
+	errs := make([]error, len(moves))
+	wg := sync.WaitGroup{}
+	for i := range moves {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := moves[i].Apply(state)
+			if err != nil {
+				errs[i] = fmt.Errorf("listed move %d: %w", i, err)
+				return
+			}
+			if res == nil || !res.Valid {
+				errs[i] = fmt.Errorf("listed move %d is not valid", i)
+				return
+			}
+			if _, err := g.SerializeMove(moves[i]); err != nil {
+				errs[i] = fmt.Errorf("listed move %d: %w", i, err)
+				return
+			}
+			results[i] = res.State
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func checkUnchanged(g game.Game, state game.StateData, before *game.SerializedObject) error {
+	after, err := g.SerializeState(state)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(before.Data, after.Data) {
+		return fmt.Errorf("applying moves mutated the input state")
+	}
+	return nil
+}
+
+// CheckPhase makes sure the phase belongs to the current player and takes
+// every move the state lists
+func CheckPhase(state game.StateData, valid []game.Move) error {
+	phase, err := game.CurrentPhase(state)
+	if err != nil {
+		return err
+	}
+	pid, err := state.CurrentPlayer()
+	if err != nil {
+		return err
+	}
+	if !phase.Actor.Equal(pid) {
+		return fmt.Errorf("phase %s is acted on by %s not the current player %s", phase.Name, phase.Actor, pid)
+	}
+	for _, move := range valid {
+		kinded, ok := move.(game.KindedMove)
+		if !ok {
+			continue
+		}
+		if !phase.Allows(kinded.Kind()) {
+			return fmt.Errorf("phase %s lists a %s move it doesn't take", phase.Name, kinded.Kind())
+		}
+	}
+	return nil
+}
+
+// CheckClone makes sure a clone serializes the same as the original
+func CheckClone(g game.Game, state game.StateData, so *game.SerializedObject) error {
+	clone, err := g.SerializeState(state.Clone())
+	if err != nil {
+		return fmt.Errorf("serialize clone: %w", err)
+	}
+	if !bytes.Equal(so.Data, clone.Data) {
+		return fmt.Errorf("clone does not match the original state")
+	}
+	return nil
+}
+
+// CheckRoundTrip serializes the state and makes sure it survives a trip
+// through the game's serializer unchanged
+func CheckRoundTrip(g game.Game, state game.StateData) (*game.SerializedObject, error) {
+	so, err := g.SerializeState(state)
+	if err != nil {
+		return nil, fmt.Errorf("serialize state: %w", err)
+	}
+	out, err := g.DeserializeState(so)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize state: %w", err)
+	}
+	again, err := g.SerializeState(out)
+	if err != nil {
+		return nil, fmt.Errorf("serialize state: %w", err)
+	}
+	if !bytes.Equal(so.Data, again.Data) {
+		return nil, fmt.Errorf("state changed round tripping through the serializer")
+	}
+	return so, nil
+}
+
+func seededUUID(r *rand.Rand) uuid.UUID {
+	id := make(uuid.UUID, 16)
+	r.Read(id)
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return id
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, i := range ids {
+		if i.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
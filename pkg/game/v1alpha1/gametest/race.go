@@ -0,0 +1,7 @@
+//go:build race
+
+package gametest
+
+// raceEnabled cuts the run down, every game is many times slower under the
+// race detector
+const raceEnabled = true
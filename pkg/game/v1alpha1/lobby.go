@@ -0,0 +1,28 @@
+package v1alpha1
+
+// LobbyGame is a game that tells the lobby how many can sit down and what
+// rules it is set up with
+type LobbyGame interface {
+	Game
+	// Seats is the fewest and most players the game deals for
+	Seats() (min, max int)
+	// Variant names the rules the game is set up with, e.g. a shorter game
+	Variant() string
+}
+
+// GameSeats returns the seat limits of the game, a max of zero is no limit
+func GameSeats(g Game) (min, max int) {
+	if lobby, ok := g.(LobbyGame); ok {
+		return lobby.Seats()
+	}
+	return 1, 0
+}
+
+// GameVariant returns the variant of the game, empty for games that don't
+// have any
+func GameVariant(g Game) string {
+	if lobby, ok := g.(LobbyGame); ok {
+		return lobby.Variant()
+	}
+	return ""
+}
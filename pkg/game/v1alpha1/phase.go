@@ -0,0 +1,58 @@
+package v1alpha1
+
+import "github.com/blend/go-sdk/uuid"
+
+const (
+	// PhaseTurn is the only phase of a state that doesn't split its turns up
+	PhaseTurn = "turn"
+)
+
+// Phase is the step of a turn a state is waiting on. A turn can run
+// through several phases with the same actor, like taking gems and then
+// handing some back, Moves names the kinds of move the phase accepts
+type Phase struct {
+	Name  string
+	Actor uuid.UUID
+	Moves []string
+}
+
+// Phased is a state whose turns are split into phases, the actor of the
+// phase is always the current player
+type Phased interface {
+	StateData
+	Phase() Phase
+}
+
+// KindedMove is a move that names its kind, the names line up with the
+// moves a phase takes
+type KindedMove interface {
+	Move
+	Kind() string
+}
+
+// CurrentPhase returns the phase the state is waiting on, states without
+// phases are always in a single turn phase that takes any move
+func CurrentPhase(state StateData) (Phase, error) {
+	if phased, ok := state.(Phased); ok {
+		return phased.Phase(), nil
+	}
+	pid, err := state.CurrentPlayer()
+	if err != nil {
+		return Phase{}, err
+	}
+	return Phase{Name: PhaseTurn, Actor: pid}, nil
+}
+
+// Allows returns if the phase takes moves of the given kind, an empty list
+// takes anything
+func (p Phase) Allows(kind string) bool {
+	if len(p.Moves) == 0 {
+		return true
+	}
+	for _, m := range p.Moves {
+		if m == kind {
+			return true
+		}
+	}
+	return false
+}
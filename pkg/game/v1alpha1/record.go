@@ -0,0 +1,108 @@
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// Record is the persisted form of a game, everything in it is serialized
+// with the game's own serializer at APIVersion
+type Record struct {
+	ID         uuid.UUID
+	Game       string
+	Variant    string    `json:",omitempty"`
+	Created    time.Time `json:",omitempty"`
+	APIVersion string
+	Version    uint64
+	Seed       int64
+	StartSeat  int `json:",omitempty"`
+	Players    []Player
+	Teams      []Team
+	Private    bool `json:",omitempty"`
+	Casual     bool `json:",omitempty"`
+	Seeded     bool `json:",omitempty"`
+
+	// Previous and Next link the game to the one it is a rematch of and
+	// its own rematch
+	Previous uuid.UUID `json:",omitempty"`
+	Next     uuid.UUID `json:",omitempty"`
+
+	// MoveDeadline is how long each move can take in a correspondence game
+	// and Deadline is when the current one is due
+	MoveDeadline time.Duration `json:",omitempty"`
+	Deadline     time.Time     `json:",omitempty"`
+
+	Initial *SerializedObject
+	State   *SerializedObject
+	Moves   []RecordedMove
+	// Sealed are the moves held back until every acting player has moved
+	Sealed []SealedMove `json:",omitempty"`
+	// Queued are the moves players made ahead of their turn
+	Queued []QueuedMoves `json:",omitempty"`
+
+	// Commitment is published at the start, Reveal has to stay private
+	// until the game is over
+	Commitment string
+	Reveal     *Reveal
+
+	// Chat keeps the last messages said at the table
+	Chat []ChatMessage `json:",omitempty"`
+	// Muted is who hid whose messages and Spectators watch without a seat
+	Muted      []Mute   `json:",omitempty"`
+	Spectators []Player `json:",omitempty"`
+
+	// Standings is only set once the game is over
+	Standings     []Standing
+	TeamStandings []TeamStanding
+}
+
+// QueuedMoves are the moves the player made ahead of their turn, in the
+// order they are played
+type QueuedMoves struct {
+	Player uuid.UUID
+	Moves  []*SerializedObject
+}
+
+// Mute is a player hiding the messages of another from themself
+type Mute struct {
+	Player uuid.UUID
+	Muted  uuid.UUID
+}
+
+// RecordedMove is one step of the game. A resignation has no move and a
+// takeback rewinds the game to before the move at the TakeBack version
+type RecordedMove struct {
+	Version  uint64
+	Player   uuid.UUID
+	Move     *SerializedObject
+	Resigned bool   `json:",omitempty"`
+	TakeBack uint64 `json:",omitempty"`
+}
+
+// Played returns the moves that still stand once the takebacks are undone,
+// without the takebacks and resignations themselves
+func (r Record) Played() []RecordedMove {
+	var stand []RecordedMove
+	for _, recorded := range r.Moves {
+		if recorded.TakeBack == 0 {
+			stand = append(stand, recorded)
+			continue
+		}
+		// the game went back to before the move that was taken back
+		// a move split up by a migration goes back whole
+		for i := len(stand) - 1; i >= 0; i-- {
+			if stand[i].Version == recorded.TakeBack && (i == 0 || stand[i-1].Version != recorded.TakeBack) {
+				stand = stand[:i]
+				break
+			}
+		}
+	}
+	played := make([]RecordedMove, 0, len(stand))
+	for _, recorded := range stand {
+		if !recorded.Resigned {
+			played = append(played, recorded)
+		}
+	}
+	return played
+}
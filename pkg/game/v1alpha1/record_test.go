@@ -0,0 +1,38 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+func TestRecordPlayed(t *testing.T) {
+	it := assert.New(t)
+	a, b := uuid.V4(), uuid.V4()
+	record := game.Record{
+		Moves: []game.RecordedMove{
+			{Version: 1, Player: a},
+			{Version: 2, Player: b},
+			{Version: 3, Player: a},
+			{Version: 4, Player: a, TakeBack: 3},
+			{Version: 5, Player: a},
+			{Version: 6, Player: b, Resigned: true},
+		},
+	}
+	var versions []uint64
+	for _, recorded := range record.Played() {
+		versions = append(versions, recorded.Version)
+	}
+	it.Equal([]uint64{1, 2, 5}, versions)
+
+	// a move split in two by a migration is taken back whole
+	record.Moves = []game.RecordedMove{
+		{Version: 1, Player: a},
+		{Version: 2, Player: b},
+		{Version: 2, Player: b},
+		{Version: 3, Player: b, TakeBack: 2},
+	}
+	it.Len(record.Played(), 1)
+}
@@ -0,0 +1,58 @@
+package v1alpha1
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+const (
+	NonceSize = 32
+)
+
+// Reveal is everything the engine committed to when the game started, the
+// seed and the initial state which holds every shuffled deck. It is kept
+// secret until the game is over, the nonce keeps the seed from being
+// guessed from the commitment
+type Reveal struct {
+	Seed    int64
+	Nonce   []byte
+	Initial *SerializedObject
+}
+
+func NewReveal(seed int64, initial *SerializedObject) (*Reveal, error) {
+	nonce, err := NewNonce()
+	if err != nil {
+		return nil, err
+	}
+	return &Reveal{
+		Seed:    seed,
+		Nonce:   nonce,
+		Initial: initial,
+	}, nil
+}
+
+func NewNonce() ([]byte, error) {
+	nonce := make([]byte, NonceSize)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// Commitment is the hex sha256 of the reveal, it is published when the game
+// starts
+func (r Reveal) Commitment() (string, error) {
+	return commitment(r)
+}
+
+func commitment(obj interface{}) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -27,8 +27,10 @@ type Serializable interface {
 }
 
 type SerializedObject struct {
-	ID   uuid.UUID
-	Data []byte
+	ID      uuid.UUID
+	Version string `json:",omitempty"`
+	Codec   string `json:",omitempty"`
+	Data    []byte
 }
 
 func (so SerializedObject) Serialize() ([]byte, error) {
@@ -0,0 +1,169 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+type SeriesFormat string
+
+const (
+	// SeriesBestOf plays up to Target games, the first to win more than
+	// half of them takes the series
+	SeriesBestOf SeriesFormat = "best-of"
+	// SeriesFirstTo keeps playing until someone has Target points added up
+	// over the games
+	SeriesFirstTo SeriesFormat = "first-to"
+)
+
+const (
+	SeriesWins   = "wins"
+	SeriesPoints = "points"
+)
+
+// Series links games between the same players and keeps score across them
+type Series struct {
+	ID      uuid.UUID
+	Format  SeriesFormat
+	Target  int
+	Players []uuid.UUID
+	Games   []SeriesGame
+}
+
+// SeriesGame is one game of the series, Standings is only set once the game
+// is over
+type SeriesGame struct {
+	ID        uuid.UUID
+	Standings []Standing
+}
+
+func NewSeries(format SeriesFormat, target int, players []uuid.UUID) (*Series, error) {
+	switch format {
+	case SeriesBestOf, SeriesFirstTo:
+	default:
+		return nil, fmt.Errorf("Unknown series format %q", format)
+	}
+	if target < 1 {
+		return nil, fmt.Errorf("Series target has to be at least 1")
+	}
+	if len(players) == 0 {
+		return nil, fmt.Errorf("Series has no players")
+	}
+	return &Series{
+		ID:      uuid.V4(),
+		Format:  format,
+		Target:  target,
+		Players: append([]uuid.UUID{}, players...),
+	}, nil
+}
+
+// AddGame adds the next game of the series, only one game is played at a
+// time and none once the series is decided
+func (s *Series) AddGame(id uuid.UUID) error {
+	if s.IsDone() {
+		return fmt.Errorf("Series is already over")
+	}
+	if n := len(s.Games); n > 0 && s.Games[n-1].Standings == nil {
+		return fmt.Errorf("Game %s of the series is still being played", s.Games[n-1].ID)
+	}
+	s.Games = append(s.Games, SeriesGame{ID: id})
+	return nil
+}
+
+// Finish records how the game went, the players have to be the players of
+// the series
+func (s *Series) Finish(id uuid.UUID, standings []Standing) error {
+	for i := range s.Games {
+		if !s.Games[i].ID.Equal(id) {
+			continue
+		}
+		for _, standing := range standings {
+			if indexOf(s.Players, standing.Player) < 0 {
+				return fmt.Errorf("Player %s is not in the series", standing.Player)
+			}
+		}
+		s.Games[i].Standings = standings
+		return nil
+	}
+	return fmt.Errorf("Game %s is not part of the series", id)
+}
+
+// Has returns if the game is part of the series
+func (s Series) Has(id uuid.UUID) bool {
+	for _, g := range s.Games {
+		if g.ID.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scores adds up the finished games, a win is a first place. Best of
+// series count wins and break ties on points, first to series the other
+// way around
+func (s Series) Scores() []Score {
+	scores := make([]Score, len(s.Players))
+	for i, p := range s.Players {
+		wins, points := 0, 0
+		for _, g := range s.Games {
+			for _, standing := range g.Standings {
+				if !standing.Player.Equal(p) {
+					continue
+				}
+				points += standing.Points
+				if standing.Place == 1 {
+					wins++
+				}
+			}
+		}
+		scores[i] = Score{
+			Player:    p,
+			Breakdown: map[string]int{SeriesWins: wins, SeriesPoints: points},
+		}
+		if s.Format == SeriesBestOf {
+			scores[i].Points, scores[i].TieBreak = wins, []int{points}
+		} else {
+			scores[i].Points, scores[i].TieBreak = points, []int{wins}
+		}
+	}
+	return scores
+}
+
+// Standings ranks the players over the games finished so far
+func (s Series) Standings() []Standing {
+	return Rank(s.Scores())
+}
+
+// IsDone returns if the series has been decided
+func (s Series) IsDone() bool {
+	played := 0
+	for _, g := range s.Games {
+		if g.Standings != nil {
+			played++
+		}
+	}
+	for _, score := range s.Scores() {
+		if s.Format == SeriesBestOf && score.Points*2 > s.Target {
+			return true
+		}
+		if s.Format == SeriesFirstTo && score.Points >= s.Target {
+			return true
+		}
+	}
+	return s.Format == SeriesBestOf && played >= s.Target
+}
+
+// Winners are the players in first place once the series is over
+func (s Series) Winners() []uuid.UUID {
+	if !s.IsDone() {
+		return nil
+	}
+	var winners []uuid.UUID
+	for _, standing := range s.Standings() {
+		if standing.Place == 1 {
+			winners = append(winners, standing.Player)
+		}
+	}
+	return winners
+}
@@ -0,0 +1,54 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+func TestSeriesBestOf(t *testing.T) {
+	it := assert.New(t)
+	a, b := uuid.V4(), uuid.V4()
+	series, err := game.NewSeries(game.SeriesBestOf, 3, []uuid.UUID{a, b})
+	it.Nil(err)
+
+	won := func(winner, loser uuid.UUID) []game.Standing {
+		return game.Rank([]game.Score{{Player: winner, Points: 15}, {Player: loser, Points: 9}})
+	}
+	first, second := uuid.V4(), uuid.V4()
+	it.Nil(series.AddGame(first))
+	// the next game waits on the one being played
+	it.NotNil(series.AddGame(second))
+	it.Nil(series.Finish(first, won(a, b)))
+	it.False(series.IsDone())
+
+	it.Nil(series.AddGame(second))
+	it.Nil(series.Finish(second, won(a, b)))
+	it.True(series.IsDone())
+	it.Equal([]uuid.UUID{a}, series.Winners())
+	it.NotNil(series.AddGame(uuid.V4()))
+
+	standings := series.Standings()
+	it.Equal(2, standings[0].Points)
+	it.Equal(30, standings[0].Breakdown[game.SeriesPoints])
+}
+
+func TestSeriesFirstTo(t *testing.T) {
+	it := assert.New(t)
+	a, b := uuid.V4(), uuid.V4()
+	series, err := game.NewSeries(game.SeriesFirstTo, 20, []uuid.UUID{a, b})
+	it.Nil(err)
+
+	for _, points := range [][2]int{{15, 12}, {4, 9}} {
+		id := uuid.V4()
+		it.Nil(series.AddGame(id))
+		it.Nil(series.Finish(id, game.Rank([]game.Score{{Player: a, Points: points[0]}, {Player: b, Points: points[1]}})))
+	}
+	it.True(series.IsDone())
+	it.Equal([]uuid.UUID{b}, series.Winners())
+
+	_, err = game.NewSeries("round-robin", 3, []uuid.UUID{a, b})
+	it.NotNil(err)
+}
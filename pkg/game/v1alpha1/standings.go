@@ -0,0 +1,122 @@
+package v1alpha1
+
+import (
+	"sort"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// Score is how one player is doing. Breakdown splits the points up by where
+// they came from and TieBreak orders players on the same points, each entry
+// is compared in turn and higher is better
+type Score struct {
+	Player    uuid.UUID
+	Points    int
+	Breakdown map[string]int
+	TieBreak  []int
+}
+
+// Scored is a state that can score every player rather than only name the
+// winners, the top of its ranking has to match Winners once it is done
+type Scored interface {
+	StateData
+	Scores() ([]Score, error)
+}
+
+// Standing is a player's place in the ranking, players level on points and
+// every tie break share a place and the next place is skipped
+type Standing struct {
+	Score
+	Place int
+}
+
+// Standings ranks every player in the state. States that aren't Scored only
+// know their winners, they get a point each and everyone else shares the
+// place after them. Eliminated players come after everyone still in, the
+// later they went out the higher they place
+func Standings(state StateData, players []uuid.UUID) ([]Standing, error) {
+	var scores []Score
+	if scored, ok := state.(Scored); ok {
+		var err error
+		scores, err = scored.Scores()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		winners := state.Winners()
+		for _, p := range players {
+			score := Score{Player: p}
+			for _, w := range winners {
+				if w.Equal(p) {
+					score.Points = 1
+				}
+			}
+			scores = append(scores, score)
+		}
+	}
+	eliminating, ok := state.(Eliminating)
+	if !ok {
+		return Rank(scores), nil
+	}
+
+	eliminated := eliminating.Eliminated()
+	var in []Score
+	out := make([]Score, len(eliminated))
+	for _, score := range scores {
+		i := indexOf(eliminated, score.Player)
+		if i < 0 {
+			in = append(in, score)
+			continue
+		}
+		// the first out is last
+		out[len(eliminated)-1-i] = score
+	}
+	standings := Rank(in)
+	for _, score := range out {
+		if score.Player == nil {
+			continue
+		}
+		standings = append(standings, Standing{Score: score, Place: len(standings) + 1})
+	}
+	return standings, nil
+}
+
+func indexOf(ids []uuid.UUID, id uuid.UUID) int {
+	for i := range ids {
+		if ids[i].Equal(id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Rank orders the scores into standings, best first
+func Rank(scores []Score) []Standing {
+	standings := make([]Standing, len(scores))
+	for i := range scores {
+		standings[i] = Standing{Score: scores[i]}
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return compareScores(standings[i].Score, standings[j].Score) > 0
+	})
+	for i := range standings {
+		if i > 0 && compareScores(standings[i].Score, standings[i-1].Score) == 0 {
+			standings[i].Place = standings[i-1].Place
+			continue
+		}
+		standings[i].Place = i + 1
+	}
+	return standings
+}
+
+func compareScores(a, b Score) int {
+	if a.Points != b.Points {
+		return a.Points - b.Points
+	}
+	for i := 0; i < len(a.TieBreak) && i < len(b.TieBreak); i++ {
+		if a.TieBreak[i] != b.TieBreak[i] {
+			return a.TieBreak[i] - b.TieBreak[i]
+		}
+	}
+	return 0
+}
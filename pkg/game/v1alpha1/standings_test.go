@@ -0,0 +1,69 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+type doneState struct {
+	winners []uuid.UUID
+}
+
+func (s doneState) Meta() game.Meta                   { return nil }
+func (s doneState) CurrentPlayer() (uuid.UUID, error) { return nil, nil }
+func (s doneState) IsDone() bool                      { return true }
+func (s doneState) Winners() []uuid.UUID              { return s.winners }
+func (s doneState) ValidMoves() ([]game.Move, error)  { return nil, nil }
+func (s doneState) Clone() game.StateData             { return s }
+
+func TestRank(t *testing.T) {
+	it := assert.New(t)
+	a, b, c, d := uuid.V4(), uuid.V4(), uuid.V4(), uuid.V4()
+
+	standings := game.Rank([]game.Score{
+		{Player: a, Points: 10, TieBreak: []int{-5}},
+		{Player: b, Points: 12},
+		{Player: c, Points: 10, TieBreak: []int{-4}},
+		{Player: d, Points: 10, TieBreak: []int{-5}},
+	})
+	it.Len(standings, 4)
+	it.Equal(b, standings[0].Player)
+	it.Equal(1, standings[0].Place)
+	it.Equal(c, standings[1].Player)
+	it.Equal(2, standings[1].Place)
+	it.Equal(3, standings[2].Place)
+	it.Equal(3, standings[3].Place)
+}
+
+func TestTeamStandings(t *testing.T) {
+	it := assert.New(t)
+	a, b, c, d := uuid.V4(), uuid.V4(), uuid.V4(), uuid.V4()
+	teams := []game.Team{
+		{Name: "north", Players: []uuid.UUID{a, c}},
+		{Name: "east", Players: []uuid.UUID{b, d}},
+	}
+	state := doneState{winners: []uuid.UUID{b, d}}
+	standings, err := game.Standings(state, []uuid.UUID{a, b, c, d})
+	it.Nil(err)
+
+	ranked := game.TeamStandings(state, teams, standings)
+	it.Len(ranked, 2)
+	it.Equal("east", ranked[0].Name)
+	it.True(ranked[0].Won)
+	it.Equal(1, ranked[0].Place)
+	it.Equal(2, ranked[0].Points)
+	it.False(ranked[1].Won)
+	it.Equal(2, ranked[1].Place)
+
+	// a cooperative game lost to the game
+	coop := []game.Team{{Name: "everyone", Players: []uuid.UUID{a, b, c, d}}}
+	lost := doneState{}
+	standings, err = game.Standings(lost, []uuid.UUID{a, b, c, d})
+	it.Nil(err)
+	ranked = game.TeamStandings(lost, coop, standings)
+	it.Len(ranked, 1)
+	it.False(ranked[0].Won)
+}
@@ -0,0 +1,23 @@
+package v1alpha1
+
+import "fmt"
+
+// Rotated is a state that can hand the first turn to any seat, a rematch
+// uses it to move the start player on while everyone keeps their seat
+type Rotated interface {
+	StateData
+	StartAt(seat int) (StateData, error)
+}
+
+// StartAt hands the first turn of a freshly dealt state to the seat, in
+// states that can't the first seat keeps it
+func StartAt(state StateData, seat int) (StateData, error) {
+	if seat < 0 {
+		return nil, fmt.Errorf("Invalid start seat %d", seat)
+	}
+	rotated, ok := state.(Rotated)
+	if !ok || seat == 0 {
+		return state, nil
+	}
+	return rotated.StartAt(seat)
+}
@@ -2,10 +2,17 @@ package v1alpha1
 
 import "github.com/blend/go-sdk/uuid"
 
+// StateData is treated as immutable, applying a move must return a new
+// state and leave the one it was given untouched since search trees share
+// parents between siblings. Anything the new state changes has to be
+// copied first, the helpers in pkg/common make that cheap
 type StateData interface {
 	Meta() Meta
 	CurrentPlayer() (uuid.UUID, error)
 	IsDone() bool
 	Winners() []uuid.UUID
 	ValidMoves() ([]Move, error)
+
+	// Clone returns a deep copy that shares nothing mutable with the original
+	Clone() StateData
 }
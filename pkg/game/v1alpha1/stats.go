@@ -0,0 +1,23 @@
+package v1alpha1
+
+import "github.com/blend/go-sdk/uuid"
+
+// Stats are numbers a game keeps about a player beyond wins and points,
+// keyed by name
+type Stats map[string]interface{}
+
+// StatsGame is a game with stats of its own, they are worked out from the
+// records of the finished games the player played
+type StatsGame interface {
+	Stats(player uuid.UUID, records []Record) (Stats, error)
+}
+
+// GameStats returns the game's own stats for the player, nil for games
+// without any
+func GameStats(g Game, player uuid.UUID, records []Record) (Stats, error) {
+	typed, ok := g.(StatsGame)
+	if !ok {
+		return nil, nil
+	}
+	return typed.Stats(player, records)
+}
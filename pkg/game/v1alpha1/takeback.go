@@ -0,0 +1,18 @@
+package v1alpha1
+
+// Takebacks is a state that decides if the game can go back to an earlier
+// state, like refusing once a move has turned over a card nobody had seen.
+// States without it always allow it
+type Takebacks interface {
+	StateData
+	CanTakeBack(to StateData) bool
+}
+
+// CanTakeBack returns if the state can be rewound to the earlier one
+func CanTakeBack(state, to StateData) bool {
+	takebacks, ok := state.(Takebacks)
+	if !ok {
+		return true
+	}
+	return takebacks.CanTakeBack(to)
+}
@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	"sort"
+
+	"github.com/blend/go-sdk/uuid"
+	common "github.com/mat285/boardgames/pkg/common/v1alpha1"
+)
+
+// Team is a group of seats that win or lose together. A cooperative game
+// is a single team of everyone playing against the game, it either wins or
+// loses to the game
+type Team struct {
+	Name    string
+	Players []uuid.UUID
+}
+
+// Has returns if the player is on the team
+func (t Team) Has(player uuid.UUID) bool {
+	for _, p := range t.Players {
+		if p.Equal(player) {
+			return true
+		}
+	}
+	return false
+}
+
+// TeamGame is a game that can be dealt with the teams picked in the lobby,
+// it decides how the teams sit around the table
+type TeamGame interface {
+	Game
+	InitializeTeams([]Team, common.Random) (StateData, error)
+}
+
+// Teamed is a state played in teams, every seat is on exactly one team and
+// its winners are always whole teams
+type Teamed interface {
+	StateData
+	Teams() ([]Team, error)
+}
+
+// TeamStanding is where a team finished, Points adds up the points of its
+// players and Won says if the team is among the winners. A cooperative team
+// that lost to the game still comes first, it just didn't win
+type TeamStanding struct {
+	Team
+	Points int
+	Won    bool
+	Place  int
+}
+
+// StateTeams returns the teams of the state, or the fallback when the state
+// doesn't report any
+func StateTeams(state StateData, fallback []Team) ([]Team, error) {
+	if teamed, ok := state.(Teamed); ok {
+		return teamed.Teams()
+	}
+	return fallback, nil
+}
+
+// TeamStandings ranks the teams from the standings of their players, the
+// winning teams first and then by points
+func TeamStandings(state StateData, teams []Team, standings []Standing) []TeamStanding {
+	winners := state.Winners()
+	ranked := make([]TeamStanding, len(teams))
+	for i, team := range teams {
+		ranked[i] = TeamStanding{Team: team}
+		for _, standing := range standings {
+			if team.Has(standing.Player) {
+				ranked[i].Points += standing.Points
+			}
+		}
+		for _, w := range winners {
+			if team.Has(w) {
+				ranked[i].Won = true
+			}
+		}
+	}
+	better := func(a, b TeamStanding) bool {
+		if a.Won != b.Won {
+			return a.Won
+		}
+		return a.Points > b.Points
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return better(ranked[i], ranked[j])
+	})
+	for i := range ranked {
+		if i > 0 && !better(ranked[i-1], ranked[i]) {
+			ranked[i].Place = ranked[i-1].Place
+			continue
+		}
+		ranked[i].Place = i + 1
+	}
+	return ranked
+}
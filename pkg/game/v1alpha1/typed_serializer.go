@@ -0,0 +1,118 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/pkg/apiversions"
+)
+
+// MovePointer is satisfied by a pointer to a game's move type
+type MovePointer[M any] interface {
+	*M
+	Move
+}
+
+// TypedSerializer serializes one game's concrete state and move types. The zero
+// value writes json at the latest api version, states are returned as S and
+// moves as *M.
+type TypedSerializer[S StateData, M any, PM MovePointer[M]] struct {
+	Codec   Codec
+	Version string
+}
+
+func NewTypedSerializer[S StateData, M any, PM MovePointer[M]](codec Codec) TypedSerializer[S, M, PM] {
+	return TypedSerializer[S, M, PM]{
+		Codec: codec,
+	}
+}
+
+func (ts TypedSerializer[S, M, PM]) SerializeMove(move Move) (*SerializedObject, error) {
+	typed, ok := move.(PM)
+	if !ok || typed == nil {
+		return nil, fmt.Errorf("Incorrect move type for serializer %T", move)
+	}
+	return ts.serialize(typed.Meta(), typed)
+}
+
+func (ts TypedSerializer[S, M, PM]) DeserializeMove(obj *SerializedObject) (Move, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	var move M
+	err := ts.deserialize(obj, &move)
+	if err != nil {
+		return nil, err
+	}
+	return PM(&move), nil
+}
+
+func (ts TypedSerializer[S, M, PM]) SerializeState(state StateData) (*SerializedObject, error) {
+	typed, ok := state.(S)
+	if !ok {
+		return nil, fmt.Errorf("Incorrect state type for serializer %T", state)
+	}
+	return ts.serialize(typed.Meta(), typed)
+}
+
+func (ts TypedSerializer[S, M, PM]) DeserializeState(obj *SerializedObject) (StateData, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	var state S
+	err := ts.deserialize(obj, &state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (ts TypedSerializer[S, M, PM]) serialize(meta Meta, i interface{}) (*SerializedObject, error) {
+	if !meta.ID().Equal(ts.gameID()) {
+		return nil, fmt.Errorf("Incorrect object metadata for serializer")
+	}
+	codec := ts.codec()
+	data, err := codec.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	return &SerializedObject{
+		ID:      meta.ID(),
+		Version: ts.version(),
+		Codec:   codec.Name(),
+		Data:    data,
+	}, nil
+}
+
+func (ts TypedSerializer[S, M, PM]) deserialize(obj *SerializedObject, out interface{}) error {
+	if !obj.ID.Equal(ts.gameID()) {
+		return fmt.Errorf("Incorrect Serializer for Object")
+	}
+	if version := apiversions.OrDefault(obj.Version); version != ts.version() {
+		return fmt.Errorf("Object is version %s, needs migrating to %s", version, ts.version())
+	}
+	codec, err := CodecByName(obj.Codec)
+	if err != nil {
+		return err
+	}
+	return codec.Unmarshal(obj.Data, out)
+}
+
+func (ts TypedSerializer[S, M, PM]) gameID() uuid.UUID {
+	var state S
+	return state.Meta().ID()
+}
+
+func (ts TypedSerializer[S, M, PM]) codec() Codec {
+	if ts.Codec == nil {
+		return JSONCodec{}
+	}
+	return ts.Codec
+}
+
+func (ts TypedSerializer[S, M, PM]) version() string {
+	if len(ts.Version) == 0 {
+		return apiversions.Latest
+	}
+	return ts.Version
+}
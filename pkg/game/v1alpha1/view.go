@@ -0,0 +1,21 @@
+package v1alpha1
+
+import "github.com/blend/go-sdk/uuid"
+
+// Concealed is a state holding what players mustn't see, like the order
+// of a shuffled pile. View is the state as the player sees it, a nil
+// player is a spectator
+type Concealed interface {
+	StateData
+	View(player uuid.UUID) StateData
+}
+
+// View returns the state as it is sent to the player, states with nothing
+// to hide are the same for everyone
+func View(state StateData, player uuid.UUID) StateData {
+	concealed, ok := state.(Concealed)
+	if !ok {
+		return state
+	}
+	return concealed.View(player)
+}
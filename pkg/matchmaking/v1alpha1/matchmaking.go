@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	"sort"
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// Ticket is a player waiting in the queue for a game of the given size,
+// Window keeps opponents within that many rating points and zero takes
+// anyone
+type Ticket struct {
+	Player  uuid.UUID
+	Game    string
+	Players int
+	Rating  int
+	Window  int
+	Queued  time.Time
+}
+
+// Table is a game put together from the queue, Bots fill the seats nobody
+// queued for
+type Table struct {
+	Game    string
+	Players []uuid.UUID
+	Bots    int
+}
+
+// Form puts waiting players together, oldest tickets first. Everyone at a
+// table has to be within the rating window of everyone else and a player
+// that waited for botWait gets bots for the seats still empty, a negative
+// botWait never adds bots
+func Form(tickets []Ticket, now time.Time, botWait time.Duration) []Table {
+	waiting := append([]Ticket{}, tickets...)
+	sort.SliceStable(waiting, func(i, j int) bool {
+		return waiting[i].Queued.Before(waiting[j].Queued)
+	})
+
+	taken := make(map[int]bool)
+	var tables []Table
+	for i, anchor := range waiting {
+		if taken[i] {
+			continue
+		}
+		seated := []int{i}
+		for j := i + 1; j < len(waiting) && len(seated) < anchor.Players; j++ {
+			other := waiting[j]
+			if taken[j] || other.Game != anchor.Game || other.Players != anchor.Players {
+				continue
+			}
+			fits := true
+			for _, k := range seated {
+				fits = fits && InWindow(waiting[k], other) && InWindow(other, waiting[k])
+			}
+			if fits {
+				seated = append(seated, j)
+			}
+		}
+		bots := anchor.Players - len(seated)
+		if bots > 0 && (botWait < 0 || now.Sub(anchor.Queued) < botWait) {
+			continue
+		}
+		table := Table{Game: anchor.Game, Bots: bots}
+		for _, k := range seated {
+			taken[k] = true
+			table.Players = append(table.Players, waiting[k].Player)
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// InWindow returns if the other player is close enough to the ticket's
+// rating
+func InWindow(ticket, other Ticket) bool {
+	if ticket.Window == 0 {
+		return true
+	}
+	diff := ticket.Rating - other.Rating
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= ticket.Window
+}
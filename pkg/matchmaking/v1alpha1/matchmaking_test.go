@@ -0,0 +1,117 @@
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	matchmaking "github.com/mat285/boardgames/pkg/matchmaking/v1alpha1"
+)
+
+func TestForm(t *testing.T) {
+	it := assert.New(t)
+	now := time.Now().UTC()
+	a, b, c, d := uuid.V4(), uuid.V4(), uuid.V4(), uuid.V4()
+	// ticket queued the player for a game of splendor the given time ago
+	ticket := func(player uuid.UUID, players, rating, window int, waited time.Duration) matchmaking.Ticket {
+		return matchmaking.Ticket{
+			Player:  player,
+			Game:    "splendor",
+			Players: players,
+			Rating:  rating,
+			Window:  window,
+			Queued:  now.Add(-waited),
+		}
+	}
+	table := func(bots int, players ...uuid.UUID) matchmaking.Table {
+		return matchmaking.Table{Game: "splendor", Players: players, Bots: bots}
+	}
+	other := ticket(b, 2, 1500, 0, time.Minute)
+	other.Game = "machikoro"
+
+	cases := []struct {
+		name    string
+		tickets []matchmaking.Ticket
+		botWait time.Duration
+		tables  []matchmaking.Table
+	}{
+		{
+			name:    "within each other's window",
+			tickets: []matchmaking.Ticket{ticket(a, 2, 1500, 100, time.Minute), ticket(b, 2, 1580, 100, time.Second)},
+			botWait: time.Hour,
+			tables:  []matchmaking.Table{table(0, a, b)},
+		},
+		{
+			name:    "outside one window",
+			tickets: []matchmaking.Ticket{ticket(a, 2, 1500, 100, time.Minute), ticket(b, 2, 1700, 0, time.Second)},
+			botWait: time.Hour,
+		},
+		{
+			name:    "no window takes anyone",
+			tickets: []matchmaking.Ticket{ticket(a, 2, 1200, 0, time.Minute), ticket(b, 2, 2200, 0, time.Second)},
+			botWait: time.Hour,
+			tables:  []matchmaking.Table{table(0, a, b)},
+		},
+		{
+			name: "within the window of everyone seated",
+			tickets: []matchmaking.Ticket{
+				ticket(a, 3, 1500, 100, time.Minute),
+				ticket(b, 3, 1580, 100, 2*time.Second),
+				ticket(c, 3, 1420, 100, time.Second),
+			},
+			botWait: time.Hour,
+		},
+		{
+			name:    "different games and sizes",
+			tickets: []matchmaking.Ticket{ticket(a, 2, 1500, 0, time.Minute), other, ticket(c, 3, 1500, 0, time.Second)},
+			botWait: time.Hour,
+		},
+		{
+			name: "tables in queue order",
+			tickets: []matchmaking.Ticket{
+				ticket(d, 2, 1500, 0, time.Second),
+				ticket(b, 2, 1500, 0, 3*time.Second),
+				ticket(c, 2, 1500, 0, 2*time.Second),
+				ticket(a, 2, 1500, 0, 4*time.Second),
+			},
+			botWait: time.Hour,
+			tables:  []matchmaking.Table{table(0, a, b), table(0, c, d)},
+		},
+		{
+			name: "the oldest ticket picks first",
+			tickets: []matchmaking.Ticket{
+				ticket(c, 2, 1580, 100, time.Minute),
+				ticket(b, 2, 1650, 200, 3*time.Minute),
+				ticket(a, 2, 1500, 100, 5*time.Minute),
+			},
+			botWait: time.Hour,
+			tables:  []matchmaking.Table{table(0, a, c)},
+		},
+		{
+			name:    "bots once the oldest waited long enough",
+			tickets: []matchmaking.Ticket{ticket(a, 4, 1500, 0, 2*time.Minute), ticket(b, 4, 1500, 0, time.Second)},
+			botWait: time.Minute,
+			tables:  []matchmaking.Table{table(2, a, b)},
+		},
+		{
+			name:    "no bots before the wait",
+			tickets: []matchmaking.Ticket{ticket(a, 3, 1500, 0, 30*time.Second)},
+			botWait: time.Minute,
+		},
+		{
+			name:    "no bots at all",
+			tickets: []matchmaking.Ticket{ticket(a, 3, 1500, 0, time.Hour)},
+			botWait: -1,
+		},
+		{
+			name:    "a full table needs no wait",
+			tickets: []matchmaking.Ticket{ticket(a, 2, 1500, 0, 0), ticket(b, 2, 1500, 0, 0)},
+			botWait: -1,
+			tables:  []matchmaking.Table{table(0, a, b)},
+		},
+	}
+	for _, tc := range cases {
+		it.Equal(tc.tables, matchmaking.Form(tc.tickets, now, tc.botWait), tc.name)
+	}
+}
@@ -11,9 +11,33 @@ const (
 	PacketTypeStateUpdate    wire.PacketType = wire.PacketTypeGameData + 102
 	PacketTypeGameOver       wire.PacketType = wire.PacketTypeGameData + 103
 	PacketTypeGameStopped    wire.PacketType = wire.PacketTypeGameData + 104
+	PacketTypeGameStarted    wire.PacketType = wire.PacketTypeGameData + 105
+	PacketTypeGameReveal     wire.PacketType = wire.PacketTypeGameData + 106
+	PacketTypeMoveSealed     wire.PacketType = wire.PacketTypeGameData + 107
+	PacketTypeMoveOpened     wire.PacketType = wire.PacketTypeGameData + 108
+	PacketTypePlayerResigned wire.PacketType = wire.PacketTypeGameData + 109
+	PacketTypeTakebackVote   wire.PacketType = wire.PacketTypeGameData + 110
+	PacketTypeTakebackResult wire.PacketType = wire.PacketTypeGameData + 111
+	PacketTypeQueuedMoves    wire.PacketType = wire.PacketTypeGameData + 112
+	PacketTypeChatMessages   wire.PacketType = wire.PacketTypeGameData + 113
 
 	PacketTypeRequestMove wire.PacketType = wire.PacketTypeGameData + 201
 	PacketTypePlayerMove  wire.PacketType = wire.PacketTypeGameData + 202
+	PacketTypeResign      wire.PacketType = wire.PacketTypeGameData + 203
+
+	PacketTypeTakebackRequest wire.PacketType = wire.PacketTypeGameData + 204
+	PacketTypeTakebackAnswer  wire.PacketType = wire.PacketTypeGameData + 205
+	PacketTypeQueueMoves      wire.PacketType = wire.PacketTypeGameData + 206
+	PacketTypeSendChat        wire.PacketType = wire.PacketTypeGameData + 207
+	PacketTypeMute            wire.PacketType = wire.PacketTypeGameData + 208
+	PacketTypeChatHistory     wire.PacketType = wire.PacketTypeGameData + 209
+)
+
+const (
+	// PacketHeaderPhase names the phase of the turn a move is requested for
+	PacketHeaderPhase = "Phase"
+	// PacketHeaderPhaseMoves lists the kinds of move the phase takes, comma separated
+	PacketHeaderPhaseMoves = "Phase-Moves"
 )
 
 type MessageBodyPlayerMoveInfo struct {
@@ -21,4 +45,73 @@ type MessageBodyPlayerMoveInfo struct {
 	Move   *game.SerializedObject
 }
 
-type MessageBodyWinners []uuid.UUID
+// MessageBodyGameOver names the winners and where everyone finished
+type MessageBodyGameOver struct {
+	Winners   []uuid.UUID
+	Standings []game.Standing
+	// Teams is only set for games played in teams
+	Teams []game.TeamStanding
+}
+
+// MessageBodyGameStarted carries the commitment to the deal, players keep
+// it to check the reveal against once the game is over
+type MessageBodyGameStarted struct {
+	Players    []uuid.UUID
+	Teams      []game.Team
+	Commitment string
+}
+
+// MessageBodyMoveSealed tells everyone a player has moved without saying
+// what the move was, the commitment is checked against the move once it
+// is opened
+type MessageBodyMoveSealed struct {
+	Player     uuid.UUID
+	Commitment string
+}
+
+// MessageBodyPlayerResigned names the player that resigned, they stay
+// connected as a spectator
+type MessageBodyPlayerResigned struct {
+	Player uuid.UUID
+}
+
+// MessageBodyTakeback asks the other players to let Player take back the
+// move at Version, everything after it is undone too
+type MessageBodyTakeback struct {
+	ID      uuid.UUID
+	Player  uuid.UUID
+	Version uint64
+}
+
+type MessageBodyTakebackAnswer struct {
+	ID      uuid.UUID
+	Approve bool
+}
+
+type MessageBodyTakebackResult struct {
+	ID       uuid.UUID
+	Approved bool
+}
+
+// MessageBodyQueuedMoves is a player's queue of moves for their next turns,
+// it is only ever sent to that player
+type MessageBodyQueuedMoves struct {
+	Moves []*game.SerializedObject
+}
+
+// MessageBodySendChat is a message a player wants to say at the table
+type MessageBodySendChat struct {
+	Text string
+}
+
+// MessageBodyChatMessages carries new chat messages, or the whole history
+// for a client that just joined or asked for it
+type MessageBodyChatMessages struct {
+	Messages []game.ChatMessage
+}
+
+// MessageBodyMute stops or starts the sender seeing Player's messages
+type MessageBodyMute struct {
+	Player uuid.UUID
+	Mute   bool
+}
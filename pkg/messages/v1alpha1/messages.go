@@ -3,20 +3,25 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/pkg/apiversions"
 	connection "github.com/mat285/boardgames/pkg/connection/v1alpha1"
 	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	migration "github.com/mat285/boardgames/pkg/migration/v1alpha1"
 	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
 )
 
 type Provider struct {
 	game.Serializer
+	Migrations []migration.Migration
 }
 
 func NewProvider(s game.Serializer) Provider {
 	return Provider{
 		Serializer: s,
+		Migrations: migration.Migrations(s),
 	}
 }
 
@@ -58,8 +63,234 @@ func (mp Provider) MessagePlayerMove(move game.Move, req uuid.UUID) (*wire.Packe
 	return mp.NewPacket(PacketTypePlayerMove, so, wire.OptPacketHeaderValue(connection.PacketHeaderRequestID, req.ToFullString()))
 }
 
-func (mp Provider) MessageGameOver(winners []uuid.UUID) (*wire.Packet, error) {
-	return mp.NewPacket(PacketTypeGameOver, MessageBodyWinners(winners))
+// MessageResign asks the engine to take the sender out of the game
+func (mp Provider) MessageResign(req uuid.UUID) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeResign, struct{}{}, wire.OptPacketHeaderValue(connection.PacketHeaderRequestID, req.ToFullString()))
+}
+
+func (mp Provider) MessagePlayerResigned(player uuid.UUID) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypePlayerResigned, MessageBodyPlayerResigned{Player: player})
+}
+
+func (mp Provider) ExtractPlayerResigned(packet wire.Packet) (*MessageBodyPlayerResigned, error) {
+	var data MessageBodyPlayerResigned
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// MessageTakebackRequest asks to take back the sender's last move
+func (mp Provider) MessageTakebackRequest(req uuid.UUID) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeTakebackRequest, struct{}{}, wire.OptPacketHeaderValue(connection.PacketHeaderRequestID, req.ToFullString()))
+}
+
+func (mp Provider) MessageTakebackVote(takeback MessageBodyTakeback) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeTakebackVote, takeback)
+}
+
+func (mp Provider) ExtractTakebackVote(packet wire.Packet) (*MessageBodyTakeback, error) {
+	var data MessageBodyTakeback
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (mp Provider) MessageTakebackAnswer(id uuid.UUID, approve bool, req uuid.UUID) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeTakebackAnswer, MessageBodyTakebackAnswer{ID: id, Approve: approve}, wire.OptPacketHeaderValue(connection.PacketHeaderRequestID, req.ToFullString()))
+}
+
+func (mp Provider) ExtractTakebackAnswer(packet wire.Packet) (*MessageBodyTakebackAnswer, error) {
+	var data MessageBodyTakebackAnswer
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (mp Provider) MessageTakebackResult(id uuid.UUID, approved bool) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeTakebackResult, MessageBodyTakebackResult{ID: id, Approved: approved})
+}
+
+func (mp Provider) ExtractTakebackResult(packet wire.Packet) (*MessageBodyTakebackResult, error) {
+	var data MessageBodyTakebackResult
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// MessageQueueMoves replaces the sender's queue of moves, an empty queue
+// clears it
+func (mp Provider) MessageQueueMoves(moves []game.Move, req uuid.UUID) (*wire.Packet, error) {
+	body, err := mp.queuedMoves(moves)
+	if err != nil {
+		return nil, err
+	}
+	return mp.NewPacket(PacketTypeQueueMoves, body, wire.OptPacketHeaderValue(connection.PacketHeaderRequestID, req.ToFullString()))
+}
+
+// MessageQueuedMoves tells a player what is left in their queue
+func (mp Provider) MessageQueuedMoves(moves []game.Move) (*wire.Packet, error) {
+	body, err := mp.queuedMoves(moves)
+	if err != nil {
+		return nil, err
+	}
+	return mp.NewPacket(PacketTypeQueuedMoves, body)
+}
+
+func (mp Provider) queuedMoves(moves []game.Move) (MessageBodyQueuedMoves, error) {
+	var body MessageBodyQueuedMoves
+	for _, move := range moves {
+		so, err := mp.SerializeMove(move)
+		if err != nil {
+			return body, err
+		}
+		body.Moves = append(body.Moves, so)
+	}
+	return body, nil
+}
+
+// ExtractQueuedMoves reads the moves of either queue packet
+func (mp Provider) ExtractQueuedMoves(packet wire.Packet) ([]game.Move, error) {
+	if packet.Type != PacketTypeQueueMoves && packet.Type != PacketTypeQueuedMoves {
+		return nil, fmt.Errorf("Wrong Packet Type")
+	}
+	var data MessageBodyQueuedMoves
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	moves := make([]game.Move, 0, len(data.Moves))
+	for _, so := range data.Moves {
+		migrated, err := migration.MigrateMove(mp.Migrations, packet.APIVersion, apiversions.Latest, so)
+		if err != nil {
+			return nil, err
+		}
+		move, err := mp.DeserializeMove(migrated)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
+
+// MessageSendChat says the text at the table
+func (mp Provider) MessageSendChat(text string, req uuid.UUID) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeSendChat, MessageBodySendChat{Text: text}, wire.OptPacketHeaderValue(connection.PacketHeaderRequestID, req.ToFullString()))
+}
+
+func (mp Provider) ExtractSendChat(packet wire.Packet) (*MessageBodySendChat, error) {
+	var data MessageBodySendChat
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (mp Provider) MessageChatMessages(messages []game.ChatMessage) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeChatMessages, MessageBodyChatMessages{Messages: messages})
+}
+
+func (mp Provider) ExtractChatMessages(packet wire.Packet) (*MessageBodyChatMessages, error) {
+	var data MessageBodyChatMessages
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// MessageChatHistory asks the engine to send the chat so far again
+func (mp Provider) MessageChatHistory(req uuid.UUID) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeChatHistory, struct{}{}, wire.OptPacketHeaderValue(connection.PacketHeaderRequestID, req.ToFullString()))
+}
+
+// MessageMute hides the player's messages from the sender, or shows them
+// again
+func (mp Provider) MessageMute(player uuid.UUID, mute bool, req uuid.UUID) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeMute, MessageBodyMute{Player: player, Mute: mute}, wire.OptPacketHeaderValue(connection.PacketHeaderRequestID, req.ToFullString()))
+}
+
+func (mp Provider) ExtractMute(packet wire.Packet) (*MessageBodyMute, error) {
+	var data MessageBodyMute
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (mp Provider) MessageGameOver(winners []uuid.UUID, standings []game.Standing, teams []game.TeamStanding) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeGameOver, MessageBodyGameOver{Winners: winners, Standings: standings, Teams: teams})
+}
+
+func (mp Provider) ExtractGameOver(packet wire.Packet) (*MessageBodyGameOver, error) {
+	var data MessageBodyGameOver
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (mp Provider) MessageGameStarted(players []uuid.UUID, teams []game.Team, commitment string) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeGameStarted, MessageBodyGameStarted{Players: players, Teams: teams, Commitment: commitment})
+}
+
+func (mp Provider) ExtractGameStarted(packet wire.Packet) (*MessageBodyGameStarted, error) {
+	var data MessageBodyGameStarted
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (mp Provider) MessageGameReveal(reveal game.Reveal) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeGameReveal, reveal)
+}
+
+func (mp Provider) ExtractGameReveal(packet wire.Packet) (*game.Reveal, error) {
+	var data game.Reveal
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (mp Provider) MessageMoveSealed(player uuid.UUID, commitment string) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeMoveSealed, MessageBodyMoveSealed{Player: player, Commitment: commitment})
+}
+
+func (mp Provider) ExtractMoveSealed(packet wire.Packet) (*MessageBodyMoveSealed, error) {
+	var data MessageBodyMoveSealed
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (mp Provider) MessageMoveOpened(sealed game.SealedMove) (*wire.Packet, error) {
+	return mp.NewPacket(PacketTypeMoveOpened, sealed)
+}
+
+func (mp Provider) ExtractMoveOpened(packet wire.Packet) (*game.SealedMove, error) {
+	var data game.SealedMove
+	err := json.Unmarshal(packet.Payload, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
 }
 
 func (mp Provider) MessageRequestMove(state game.StateData) (*wire.Packet, error) {
@@ -67,7 +298,27 @@ func (mp Provider) MessageRequestMove(state game.StateData) (*wire.Packet, error
 	if err != nil {
 		return nil, err
 	}
-	return mp.NewPacket(PacketTypeRequestMove, so)
+	phase, err := game.CurrentPhase(state)
+	if err != nil {
+		return nil, err
+	}
+	return mp.NewPacket(PacketTypeRequestMove, so,
+		wire.OptPacketHeaderValue(PacketHeaderPhase, phase.Name),
+		wire.OptPacketHeaderValue(PacketHeaderPhaseMoves, strings.Join(phase.Moves, ",")),
+	)
+}
+
+// ExtractPhase reads the phase a move request is asking for, the actor is
+// whoever the request was sent to
+func (mp Provider) ExtractPhase(packet wire.Packet) game.Phase {
+	phase := game.Phase{
+		Name:  packet.Options.Value(PacketHeaderPhase),
+		Actor: packet.Destination,
+	}
+	if moves := packet.Options.Value(PacketHeaderPhaseMoves); len(moves) > 0 {
+		phase.Moves = strings.Split(moves, ",")
+	}
+	return phase
 }
 
 func (mp Provider) ExtractMove(packet wire.Packet) (game.Move, error) {
@@ -79,7 +330,11 @@ func (mp Provider) ExtractMove(packet wire.Packet) (game.Move, error) {
 	if err != nil {
 		return nil, err
 	}
-	return mp.DeserializeMove(&so)
+	migrated, err := migration.MigrateMove(mp.Migrations, packet.APIVersion, apiversions.Latest, &so)
+	if err != nil {
+		return nil, err
+	}
+	return mp.DeserializeMove(migrated)
 }
 
 func (mp Provider) ExtractState(packet wire.Packet) (game.StateData, error) {
@@ -88,5 +343,9 @@ func (mp Provider) ExtractState(packet wire.Packet) (game.StateData, error) {
 	if err != nil {
 		return nil, err
 	}
-	return mp.DeserializeState(&so)
+	migrated, err := migration.MigrateState(mp.Migrations, packet.APIVersion, apiversions.Latest, &so)
+	if err != nil {
+		return nil, err
+	}
+	return mp.DeserializeState(migrated)
 }
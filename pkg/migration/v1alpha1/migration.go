@@ -0,0 +1,216 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/mat285/boardgames/pkg/apiversions"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+// Func upgrades a serialized object from one shape to the next
+type Func func(*game.SerializedObject) (*game.SerializedObject, error)
+
+// Split upgrades a move that became several moves
+type Split func(*game.SerializedObject) ([]*game.SerializedObject, error)
+
+// Migration upgrades the serialized states and moves of a game from one
+// api version to another. A nil func means that shape didn't change.
+// Split is used in place of Move when it is set
+type Migration struct {
+	From  string
+	To    string
+	State Func
+	Move  Func
+	Split Split
+}
+
+// Provider is implemented by games whose serialized shapes have changed
+// between api versions
+type Provider interface {
+	Migrations() []Migration
+}
+
+func Migrations(i interface{}) []Migration {
+	provider, ok := i.(Provider)
+	if !ok {
+		return nil
+	}
+	return provider.Migrations()
+}
+
+// Plan finds the ordered migrations needed to go from one version to
+// another, a known version the game has no migration from moves on to the
+// next one unchanged
+func Plan(migrations []Migration, from, to string) ([]Migration, error) {
+	from = apiversions.OrDefault(from)
+	to = apiversions.OrDefault(to)
+	steps := make(map[string]Migration, len(migrations))
+	for _, m := range migrations {
+		key := apiversions.OrDefault(m.From)
+		if _, has := steps[key]; has {
+			return nil, fmt.Errorf("Multiple migrations from %s", key)
+		}
+		steps[key] = m
+	}
+
+	plan := []Migration{}
+	seen := map[string]bool{}
+	for curr := from; curr != to; {
+		if seen[curr] {
+			return nil, fmt.Errorf("Migration cycle at %s", curr)
+		}
+		seen[curr] = true
+		step, has := steps[curr]
+		if !has {
+			next, known := apiversions.Next(curr)
+			if !known {
+				return nil, fmt.Errorf("No migration from %s to %s", curr, to)
+			}
+			step = Migration{From: curr, To: next}
+		}
+		plan = append(plan, step)
+		curr = apiversions.OrDefault(step.To)
+	}
+	return plan, nil
+}
+
+func MigrateState(migrations []Migration, from, to string, obj *game.SerializedObject) (*game.SerializedObject, error) {
+	return migrate(migrations, from, to, obj, func(m Migration) Func { return m.State })
+}
+
+// MigrateMove upgrades a single move, a move that was split up since has
+// to be sent as the moves it became
+func MigrateMove(migrations []Migration, from, to string, obj *game.SerializedObject) (*game.SerializedObject, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	moves, err := MigrateMoves(migrations, from, to, obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(moves) != 1 {
+		return nil, fmt.Errorf("Move is %d moves from %s on, send them one at a time", len(moves), to)
+	}
+	return moves[0], nil
+}
+
+// MigrateMoves upgrades a move into the moves it became
+func MigrateMoves(migrations []Migration, from, to string, obj *game.SerializedObject) ([]*game.SerializedObject, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	if len(obj.Version) > 0 {
+		from = obj.Version
+	}
+	plan, err := Plan(migrations, from, to)
+	if err != nil {
+		return nil, err
+	}
+	moves := []*game.SerializedObject{obj}
+	for _, step := range plan {
+		var next []*game.SerializedObject
+		for _, move := range moves {
+			split := []*game.SerializedObject{move}
+			switch {
+			case step.Split != nil:
+				split, err = step.Split(move)
+			case step.Move != nil:
+				split[0], err = step.Move(move)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("migrating %s to %s: %w", step.From, step.To, err)
+			}
+			for _, migrated := range split {
+				stamped := *migrated
+				stamped.Version = apiversions.OrDefault(step.To)
+				next = append(next, &stamped)
+			}
+		}
+		moves = next
+	}
+	return moves, nil
+}
+
+// MigrateRecord upgrades every state and move in the record to the given version
+func MigrateRecord(migrations []Migration, record *game.Record, to string) error {
+	if record == nil {
+		return nil
+	}
+	from := apiversions.OrDefault(record.APIVersion)
+	to = apiversions.OrDefault(to)
+	if from == to {
+		record.APIVersion = to
+		return nil
+	}
+	var err error
+	record.Initial, err = MigrateState(migrations, from, to, record.Initial)
+	if err != nil {
+		return err
+	}
+	record.State, err = MigrateState(migrations, from, to, record.State)
+	if err != nil {
+		return err
+	}
+	moves := make([]game.RecordedMove, 0, len(record.Moves))
+	for _, recorded := range record.Moves {
+		if recorded.Move == nil {
+			moves = append(moves, recorded)
+			continue
+		}
+		migrated, err := MigrateMoves(migrations, from, to, recorded.Move)
+		if err != nil {
+			return err
+		}
+		// the moves a move was split into share its version
+		for _, move := range migrated {
+			recorded.Move = move
+			moves = append(moves, recorded)
+		}
+	}
+	record.Moves = moves
+	for i := range record.Sealed {
+		record.Sealed[i].Move, err = MigrateMove(migrations, from, to, record.Sealed[i].Move)
+		if err != nil {
+			return err
+		}
+	}
+	for i := range record.Queued {
+		var queued []*game.SerializedObject
+		for _, move := range record.Queued[i].Moves {
+			migrated, err := MigrateMoves(migrations, from, to, move)
+			if err != nil {
+				return err
+			}
+			queued = append(queued, migrated...)
+		}
+		record.Queued[i].Moves = queued
+	}
+	record.APIVersion = to
+	return nil
+}
+
+func migrate(migrations []Migration, from, to string, obj *game.SerializedObject, fn func(Migration) Func) (*game.SerializedObject, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	if len(obj.Version) > 0 {
+		// objects that know their own version win over their container
+		from = obj.Version
+	}
+	plan, err := Plan(migrations, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range plan {
+		if f := fn(step); f != nil {
+			obj, err = f(obj)
+			if err != nil {
+				return nil, fmt.Errorf("migrating %s to %s: %w", step.From, step.To, err)
+			}
+		}
+		migrated := *obj
+		migrated.Version = apiversions.OrDefault(step.To)
+		obj = &migrated
+	}
+	return obj, nil
+}
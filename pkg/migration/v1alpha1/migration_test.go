@@ -0,0 +1,123 @@
+package v1alpha1_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/mat285/boardgames/pkg/apiversions"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	migration "github.com/mat285/boardgames/pkg/migration/v1alpha1"
+)
+
+func appendData(suffix string) migration.Func {
+	return func(obj *game.SerializedObject) (*game.SerializedObject, error) {
+		return &game.SerializedObject{ID: obj.ID, Data: append(append([]byte{}, obj.Data...), suffix...)}, nil
+	}
+}
+
+func testMigrations() []migration.Migration {
+	return []migration.Migration{
+		{From: "v0beta1", To: "v0beta2", State: appendData("-s2"), Move: appendData("-m2")},
+		{From: "v0beta2", To: apiversions.V1Alpha1, State: appendData("-s3")},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	it := assert.New(t)
+
+	plan, err := migration.Plan(testMigrations(), "v0beta1", apiversions.V1Alpha1)
+	it.Nil(err)
+	it.Len(plan, 2)
+
+	plan, err = migration.Plan(testMigrations(), "", apiversions.V1Alpha1)
+	it.Nil(err)
+	it.Empty(plan)
+
+	_, err = migration.Plan(testMigrations(), "v9", apiversions.V1Alpha1)
+	it.NotNil(err)
+
+	cycle := []migration.Migration{
+		{From: "a", To: "b"},
+		{From: "b", To: "a"},
+	}
+	_, err = migration.Plan(cycle, "a", "c")
+	it.NotNil(err)
+}
+
+func TestMigrateRecord(t *testing.T) {
+	it := assert.New(t)
+
+	record := &game.Record{
+		APIVersion: "v0beta1",
+		Initial:    &game.SerializedObject{Data: []byte("init")},
+		State:      &game.SerializedObject{Data: []byte("state")},
+		Moves: []game.RecordedMove{
+			{Version: 1, Move: &game.SerializedObject{Data: []byte("move")}},
+		},
+	}
+	err := migration.MigrateRecord(testMigrations(), record, apiversions.V1Alpha1)
+	it.Nil(err)
+	it.Equal(apiversions.V1Alpha1, record.APIVersion)
+	it.Equal("init-s2-s3", string(record.Initial.Data))
+	it.Equal("state-s2-s3", string(record.State.Data))
+	it.Equal("move-m2", string(record.Moves[0].Move.Data))
+}
+
+func TestMigrateError(t *testing.T) {
+	it := assert.New(t)
+
+	migrations := []migration.Migration{
+		{From: "v0beta1", To: apiversions.V1Alpha1, Move: func(*game.SerializedObject) (*game.SerializedObject, error) {
+			return nil, fmt.Errorf("unsupported")
+		}},
+	}
+	_, err := migration.MigrateMove(migrations, "v0beta1", apiversions.V1Alpha1, &game.SerializedObject{})
+	it.NotNil(err)
+}
+
+func TestPlanKnownVersions(t *testing.T) {
+	it := assert.New(t)
+
+	// games without migrations move through the known versions unchanged
+	plan, err := migration.Plan(nil, apiversions.V1Alpha1, apiversions.V1Alpha2)
+	it.Nil(err)
+	it.Len(plan, 1)
+	it.Nil(plan[0].Move)
+
+	moved, err := migration.MigrateMove(nil, apiversions.V1Alpha1, apiversions.V1Alpha2, &game.SerializedObject{Data: []byte("move")})
+	it.Nil(err)
+	it.Equal(apiversions.V1Alpha2, moved.Version)
+	it.Equal("move", string(moved.Data))
+}
+
+func TestMigrateSplit(t *testing.T) {
+	it := assert.New(t)
+
+	migrations := []migration.Migration{
+		{From: apiversions.V1Alpha1, To: apiversions.V1Alpha2, Split: func(obj *game.SerializedObject) ([]*game.SerializedObject, error) {
+			if string(obj.Data) != "both" {
+				return []*game.SerializedObject{obj}, nil
+			}
+			return []*game.SerializedObject{{Data: []byte("one")}, {Data: []byte("two")}}, nil
+		}},
+	}
+	record := &game.Record{
+		APIVersion: apiversions.V1Alpha1,
+		Moves: []game.RecordedMove{
+			{Version: 1, Move: &game.SerializedObject{Data: []byte("both")}},
+			{Version: 2, Resigned: true},
+			{Version: 3, Move: &game.SerializedObject{Data: []byte("three")}},
+		},
+	}
+	it.Nil(migration.MigrateRecord(migrations, record, apiversions.V1Alpha2))
+	it.Len(record.Moves, 4)
+	it.Equal("one", string(record.Moves[0].Move.Data))
+	it.Equal("two", string(record.Moves[1].Move.Data))
+	it.Equal(uint64(1), record.Moves[1].Version)
+	it.True(record.Moves[2].Resigned)
+	it.Equal("three", string(record.Moves[3].Move.Data))
+
+	_, err := migration.MigrateMove(migrations, apiversions.V1Alpha1, apiversions.V1Alpha2, &game.SerializedObject{Data: []byte("both")})
+	it.NotNil(err)
+}
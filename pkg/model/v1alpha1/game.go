@@ -1,16 +1,34 @@
 package v1alpha1
 
-import "github.com/blend/go-sdk/uuid"
+import (
+	"time"
 
+	"github.com/blend/go-sdk/uuid"
+)
+
+// Game is how a game shows up in the lobby
 type Game struct {
 	ID      uuid.UUID
 	Game    string
+	Variant string
+	Host    Player
 	Players []Player
-	Status  GameStatus
+	// MaxPlayers is zero for games that take any number of players
+	MaxPlayers int
+	OpenSeats  int
+	Status     GameStatus
+	Created    time.Time
 }
 
 type Player struct {
+	ID       uuid.UUID
 	Username string
 }
 
 type GameStatus string
+
+const (
+	GameStatusOpen    GameStatus = "open"
+	GameStatusPlaying GameStatus = "playing"
+	GameStatusOver    GameStatus = "over"
+)
@@ -0,0 +1,176 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+
+	// DefaultWebhookTimeout bounds each webhook post
+	DefaultWebhookTimeout = 10 * time.Second
+)
+
+var (
+	_ Channel = new(Webhook)
+	_ Channel = new(SMTP)
+)
+
+// Webhook posts the notification as JSON to the player's https webhook url,
+// Hosts limits the hosts it posts to when set. Loopback, private and link
+// local addresses are refused when dialing so a url can't reach into the
+// server's own network, AllowPrivate lets them through for webhooks run
+// next to the server
+type Webhook struct {
+	Client       *http.Client
+	Hosts        []string
+	AllowPrivate bool
+}
+
+func NewWebhook(timeout time.Duration, hosts ...string) *Webhook {
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	w := &Webhook{Hosts: hosts}
+	dialer := &net.Dialer{Timeout: timeout, Control: w.control}
+	w.Client = &http.Client{
+		Timeout: timeout,
+		// no proxy so the dialer sees where the post really goes
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("Webhook redirected too many times")
+			}
+			return CheckWebhook(req.URL.String(), w.Hosts)
+		},
+	}
+	return w
+}
+
+// CheckWebhook makes sure the url is one a webhook may post to, an https url
+// on one of the hosts when any are given
+func CheckWebhook(raw string, hosts []string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || len(u.Hostname()) == 0 {
+		return fmt.Errorf("Webhook must be an https url")
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	for _, host := range hosts {
+		if strings.EqualFold(host, u.Hostname()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Webhook host %s is not allowed", u.Hostname())
+}
+
+// control refuses to connect to an address on the server's own network
+func (w *Webhook) control(network, address string, _ syscall.RawConn) error {
+	if w.AllowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("Webhook address %s is not an ip", host)
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("Webhook address %s is refused", ip)
+	}
+	return nil
+}
+
+func (w *Webhook) Name() string {
+	return ChannelWebhook
+}
+
+func (w *Webhook) Deliver(ctx context.Context, prefs Preferences, n Notification) error {
+	if len(prefs.Webhook) == 0 {
+		return nil
+	}
+	if err := CheckWebhook(prefs.Webhook, w.Hosts); err != nil {
+		return err
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, prefs.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Client == nil {
+		return fmt.Errorf("Webhook has no client, use NewWebhook")
+	}
+	res, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("Webhook returned %d", res.StatusCode)
+	}
+	return nil
+}
+
+// SMTP emails the notification to the player's confirmed address through
+// the server at Addr, Auth is left nil for servers that don't need it
+type SMTP struct {
+	Addr string
+	From string
+	Auth smtp.Auth
+}
+
+func (s *SMTP) Name() string {
+	return ChannelEmail
+}
+
+func (s *SMTP) Deliver(ctx context.Context, prefs Preferences, n Notification) error {
+	if len(prefs.Email) == 0 || !prefs.EmailConfirmed {
+		return nil
+	}
+	return s.Send(prefs.Email, n)
+}
+
+// Send mails the notification to the address confirmed or not, it is how
+// the confirmation itself goes out
+func (s *SMTP) Send(to string, n Notification) error {
+	return smtp.SendMail(s.Addr, s.Auth, s.From, []string{to}, s.message(to, n))
+}
+
+func (s *SMTP) message(to string, n Notification) []byte {
+	// headers can't carry new lines or they'd start new ones
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(n.Title)
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(n.Text, "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
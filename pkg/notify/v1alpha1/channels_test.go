@@ -0,0 +1,115 @@
+package v1alpha1_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	notify "github.com/mat285/boardgames/pkg/notify/v1alpha1"
+)
+
+func TestWebhook(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	received := make(chan notify.Notification, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n notify.Notification
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- n
+	}))
+	defer server.Close()
+
+	webhook := notify.NewWebhook(0)
+	webhook.Client.Transport.(*http.Transport).TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+	n := notify.Notification{ID: uuid.V4(), Type: notify.TypeYourTurn, Title: "Your turn"}
+	prefs := notify.Preferences{Webhook: server.URL}
+	// the test server is on loopback, which is refused when dialing
+	err := webhook.Deliver(ctx, prefs, n)
+	it.NotNil(err)
+	it.Contains(err.Error(), "127.0.0.1 is refused")
+
+	webhook.AllowPrivate = true
+	it.Nil(webhook.Deliver(ctx, prefs, n))
+	it.Equal(n.ID, (<-received).ID)
+	// players without a webhook are skipped
+	it.Nil(webhook.Deliver(ctx, notify.Preferences{}, n))
+	it.NotNil(webhook.Deliver(ctx, notify.Preferences{Webhook: server.URL + "/%zz"}, n))
+	it.NotNil(webhook.Deliver(ctx, notify.Preferences{Webhook: strings.Replace(server.URL, "https", "http", 1)}, n))
+
+	// only the hosts the server allows are posted to
+	webhook.Hosts = []string{"hooks.example.com"}
+	it.NotNil(webhook.Deliver(ctx, prefs, n))
+	it.Nil(notify.CheckWebhook("https://HOOKS.example.com/games", webhook.Hosts))
+	it.NotNil(notify.CheckWebhook("http://hooks.example.com/games", webhook.Hosts))
+	it.Empty(received)
+}
+
+func TestSMTP(t *testing.T) {
+	it := assert.New(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	it.Nil(err)
+	defer listener.Close()
+	mail := make(chan string, 1)
+	go serveSMTP(listener, mail)
+
+	email := &notify.SMTP{Addr: listener.Addr().String(), From: "games@example.com"}
+	n := notify.Notification{Type: notify.TypeOver, Title: "Game over\r\nBcc: someone", Text: "You came first"}
+	// an address that isn't confirmed is never mailed
+	it.Nil(email.Deliver(context.Background(), notify.Preferences{Email: "player@example.com"}, n))
+	it.Nil(email.Deliver(context.Background(), notify.Preferences{Email: "player@example.com", EmailConfirmed: true}, n))
+	body := <-mail
+	it.Contains(body, "To: player@example.com")
+	it.Contains(body, "Subject: Game over  Bcc: someone")
+	it.Contains(body, "You came first")
+}
+
+// serveSMTP accepts one message, just enough of the protocol for the
+// standard library client
+func serveSMTP(listener net.Listener, mail chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+	reply("220 localhost ready")
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			mail <- data.String()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"sync"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// DefaultInboxSize is how many notifications each player keeps
+const DefaultInboxSize = 100
+
+// Inbox keeps the latest notifications of each player in process
+type Inbox struct {
+	sync.Mutex
+	Size int
+
+	entries map[string][]Notification
+}
+
+func NewInbox(size int) *Inbox {
+	return &Inbox{
+		Size:    size,
+		entries: make(map[string][]Notification),
+	}
+}
+
+// Add keeps the notification, dropping the oldest once the inbox is full
+func (i *Inbox) Add(n Notification) {
+	i.Lock()
+	defer i.Unlock()
+	key := n.Player.ToFullString()
+	entries := append(i.entries[key], n)
+	if i.Size > 0 && len(entries) > i.Size {
+		entries = append([]Notification{}, entries[len(entries)-i.Size:]...)
+	}
+	i.entries[key] = entries
+}
+
+// List returns the player's notifications newest first
+func (i *Inbox) List(player uuid.UUID, unread bool) []Notification {
+	i.Lock()
+	defer i.Unlock()
+	entries := i.entries[player.ToFullString()]
+	list := make([]Notification, 0, len(entries))
+	for j := len(entries) - 1; j >= 0; j-- {
+		if unread && entries[j].Read {
+			continue
+		}
+		list = append(list, entries[j])
+	}
+	return list
+}
+
+// MarkRead marks the notifications as read, all of them without ids, and
+// returns how many changed
+func (i *Inbox) MarkRead(player uuid.UUID, ids ...uuid.UUID) int {
+	i.Lock()
+	defer i.Unlock()
+	marked := 0
+	entries := i.entries[player.ToFullString()]
+	for j := range entries {
+		if entries[j].Read || (len(ids) > 0 && !hasID(ids, entries[j].ID)) {
+			continue
+		}
+		entries[j].Read = true
+		marked++
+	}
+	return marked
+}
+
+func hasID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, other := range ids {
+		if other.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package v1alpha1
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// Type is what a notification is about, players can turn each one off
+type Type string
+
+const (
+	TypeYourTurn Type = "your-turn"
+	TypeStarted  Type = "started"
+	TypeOver     Type = "over"
+	TypeInvite   Type = "invite"
+)
+
+// Notification tells a player something happened in one of their games
+type Notification struct {
+	ID     uuid.UUID
+	Player uuid.UUID
+	Type   Type
+	Game   uuid.UUID
+	Title  string
+	Text   string
+	Time   time.Time
+	Read   bool
+}
+
+// Channel delivers notifications outside of the server, it skips players
+// that haven't told it where to
+type Channel interface {
+	Name() string
+	Deliver(ctx context.Context, prefs Preferences, n Notification) error
+}
+
+// Preferences are how a player wants to be told, the inbox always gets
+// every notification that isn't disabled
+type Preferences struct {
+	Email string
+	// EmailConfirmed is set once the player proved the address is theirs,
+	// nothing is mailed to it before. Only ConfirmEmail sets it
+	EmailConfirmed bool
+	Webhook        string
+	// Disabled lists the types the player doesn't want to hear about
+	Disabled []Type
+	// Channels names the channels to deliver on, empty is all of them
+	Channels []string
+	// Quiet holds back the channels for part of the day
+	Quiet *QuietHours
+}
+
+// Wants returns if the player wants notifications of the type at all
+func (p Preferences) Wants(t Type) bool {
+	for _, disabled := range p.Disabled {
+		if disabled == t {
+			return false
+		}
+	}
+	return true
+}
+
+// Uses returns if the player wants notifications on the channel
+func (p Preferences) Uses(channel string) bool {
+	if len(p.Channels) == 0 {
+		return true
+	}
+	for _, name := range p.Channels {
+		if name == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// QuietHours run from the Start hour to the End hour in the player's time
+// zone, they can wrap past midnight
+type QuietHours struct {
+	Start    int
+	End      int
+	TimeZone string
+}
+
+// Validate checks the hours and time zone make sense
+func (q QuietHours) Validate() error {
+	if q.Start < 0 || q.Start > 23 || q.End < 0 || q.End > 23 {
+		return fmt.Errorf("Quiet hours must be between 0 and 23")
+	}
+	_, err := time.LoadLocation(q.TimeZone)
+	return err
+}
+
+// Contains returns if the time falls in the quiet hours, an unknown time
+// zone is read as UTC
+func (q QuietHours) Contains(t time.Time) bool {
+	if q.Start == q.End {
+		return false
+	}
+	if loc, err := time.LoadLocation(q.TimeZone); err == nil {
+		t = t.In(loc)
+	}
+	hour := t.Hour()
+	if q.Start < q.End {
+		return hour >= q.Start && hour < q.End
+	}
+	return hour >= q.Start || hour < q.End
+}
+
+// Notifier keeps the players' preferences and sends notifications to the
+// inbox and on to the channels, what comes in during a player's quiet hours
+// is held until Flush runs after they end
+type Notifier struct {
+	sync.Mutex
+	Inbox    *Inbox
+	Channels []Channel
+
+	preferences map[string]Preferences
+	held        map[string][]Notification
+	tokens      map[string]emailToken
+}
+
+// emailToken is mailed to the address to confirm it
+type emailToken struct {
+	Email string
+	Token string
+}
+
+func NewNotifier(channels ...Channel) *Notifier {
+	return &Notifier{
+		Inbox:       NewInbox(DefaultInboxSize),
+		Channels:    channels,
+		preferences: make(map[string]Preferences),
+		held:        make(map[string][]Notification),
+		tokens:      make(map[string]emailToken),
+	}
+}
+
+func (n *Notifier) Preferences(player uuid.UUID) Preferences {
+	n.Lock()
+	defer n.Unlock()
+	return n.preferences[player.ToFullString()]
+}
+
+// SetPreferences replaces the player's preferences, the email is kept as
+// just the address and has to be confirmed again when it changes
+func (n *Notifier) SetPreferences(player uuid.UUID, prefs Preferences) error {
+	if prefs.Quiet != nil {
+		if err := prefs.Quiet.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(prefs.Email) > 0 {
+		addr, err := mail.ParseAddress(prefs.Email)
+		if err != nil {
+			return fmt.Errorf("Invalid email %q: %v", prefs.Email, err)
+		}
+		prefs.Email = addr.Address
+	}
+	key := player.ToFullString()
+	n.Lock()
+	defer n.Unlock()
+	old := n.preferences[key]
+	prefs.EmailConfirmed = old.EmailConfirmed && old.Email == prefs.Email
+	if old.Email != prefs.Email {
+		delete(n.tokens, key)
+	}
+	n.preferences[key] = prefs
+	return nil
+}
+
+// NewEmailToken makes the token that confirms the player's email, it is
+// mailed there and handed back to ConfirmEmail. A new one replaces the last
+func (n *Notifier) NewEmailToken(player uuid.UUID) (string, error) {
+	key := player.ToFullString()
+	n.Lock()
+	defer n.Unlock()
+	prefs := n.preferences[key]
+	if len(prefs.Email) == 0 {
+		return "", fmt.Errorf("No email to confirm")
+	}
+	if prefs.EmailConfirmed {
+		return "", fmt.Errorf("Email is already confirmed")
+	}
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(data)
+	n.tokens[key] = emailToken{Email: prefs.Email, Token: token}
+	return token, nil
+}
+
+// ConfirmEmail confirms the player's email if the token is the last one
+// mailed to it
+func (n *Notifier) ConfirmEmail(player uuid.UUID, token string) error {
+	key := player.ToFullString()
+	n.Lock()
+	defer n.Unlock()
+	prefs := n.preferences[key]
+	sent, has := n.tokens[key]
+	if !has || sent.Email != prefs.Email || subtle.ConstantTimeCompare([]byte(sent.Token), []byte(token)) != 1 {
+		return fmt.Errorf("Wrong or expired confirmation token")
+	}
+	delete(n.tokens, key)
+	prefs.EmailConfirmed = true
+	n.preferences[key] = prefs
+	return nil
+}
+
+// Notify puts the notification in the player's inbox and delivers it on
+// their channels, during their quiet hours it is held for Flush instead.
+// The first failed delivery is returned after trying the rest
+func (n *Notifier) Notify(ctx context.Context, notification Notification) error {
+	if notification.ID.IsZero() {
+		notification.ID = uuid.V4()
+	}
+	if notification.Time.IsZero() {
+		notification.Time = time.Now().UTC()
+	}
+	prefs := n.Preferences(notification.Player)
+	if !prefs.Wants(notification.Type) {
+		return nil
+	}
+	n.Inbox.Add(notification)
+	key := notification.Player.ToFullString()
+	n.Lock()
+	if prefs.Quiet != nil && prefs.Quiet.Contains(notification.Time) {
+		n.held[key] = append(n.held[key], notification)
+		n.Unlock()
+		return nil
+	}
+	// anything still held goes out first so it arrives in order
+	pending := append(n.held[key], notification)
+	delete(n.held, key)
+	n.Unlock()
+	return n.deliver(ctx, prefs, pending)
+}
+
+// Flush delivers what was held for the players whose quiet hours are over
+// by now, the first failed delivery is returned after trying the rest
+func (n *Notifier) Flush(ctx context.Context, now time.Time) error {
+	n.Lock()
+	type player struct {
+		prefs   Preferences
+		pending []Notification
+	}
+	var due []player
+	for key, pending := range n.held {
+		prefs := n.preferences[key]
+		if prefs.Quiet != nil && prefs.Quiet.Contains(now) {
+			continue
+		}
+		due = append(due, player{prefs: prefs, pending: pending})
+		delete(n.held, key)
+	}
+	n.Unlock()
+	var first error
+	for _, p := range due {
+		if err := n.deliver(ctx, p.prefs, p.pending); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (n *Notifier) deliver(ctx context.Context, prefs Preferences, notifications []Notification) error {
+	var first error
+	for _, notification := range notifications {
+		for _, channel := range n.Channels {
+			if !prefs.Uses(channel.Name()) {
+				continue
+			}
+			if err := channel.Deliver(ctx, prefs, notification); err != nil && first == nil {
+				first = fmt.Errorf("Delivering on %s: %v", channel.Name(), err)
+			}
+		}
+	}
+	return first
+}
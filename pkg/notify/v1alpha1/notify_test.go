@@ -0,0 +1,129 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	notify "github.com/mat285/boardgames/pkg/notify/v1alpha1"
+)
+
+// recorder is a channel that keeps what it is sent
+type recorder struct {
+	sent []notify.Notification
+}
+
+func (r *recorder) Name() string { return "recorder" }
+
+func (r *recorder) Deliver(_ context.Context, _ notify.Preferences, n notify.Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func TestQuietHours(t *testing.T) {
+	it := assert.New(t)
+	at := func(hour int) time.Time { return time.Date(2024, 1, 1, hour, 30, 0, 0, time.UTC) }
+
+	night := notify.QuietHours{Start: 22, End: 7}
+	it.True(night.Contains(at(23)))
+	it.True(night.Contains(at(3)))
+	it.False(night.Contains(at(7)))
+	it.False(night.Contains(at(12)))
+
+	lunch := notify.QuietHours{Start: 12, End: 13, TimeZone: "Asia/Tokyo"}
+	it.True(lunch.Contains(at(3)))
+	it.False(lunch.Contains(at(12)))
+
+	it.False(notify.QuietHours{Start: 5, End: 5}.Contains(at(5)))
+	it.NotNil(notify.QuietHours{Start: 24}.Validate())
+	it.NotNil(notify.QuietHours{TimeZone: "Nowhere/Special"}.Validate())
+}
+
+func TestNotifier(t *testing.T) {
+	it := assert.New(t)
+	ctx := context.Background()
+	channel := &recorder{}
+	n := notify.NewNotifier(channel)
+	player := uuid.V4()
+	it.Nil(n.SetPreferences(player, notify.Preferences{
+		Disabled: []notify.Type{notify.TypeStarted},
+		Quiet:    &notify.QuietHours{Start: 22, End: 7},
+	}))
+
+	day := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	it.Nil(n.Notify(ctx, notify.Notification{Player: player, Type: notify.TypeYourTurn, Time: day}))
+	it.Nil(n.Notify(ctx, notify.Notification{Player: player, Type: notify.TypeStarted, Time: day}))
+	it.Nil(n.Notify(ctx, notify.Notification{Player: player, Type: notify.TypeOver, Time: night}))
+	// quiet hours only hold back the channels, the inbox still gets it
+	it.Len(channel.sent, 1)
+	inbox := n.Inbox.List(player, true)
+	it.Len(inbox, 2)
+	it.Equal(notify.TypeOver, inbox[0].Type)
+
+	it.Equal(1, n.Inbox.MarkRead(player, inbox[1].ID))
+	it.Len(n.Inbox.List(player, true), 1)
+	it.Equal(1, n.Inbox.MarkRead(player))
+	it.Empty(n.Inbox.List(player, true))
+	it.Len(n.Inbox.List(player, false), 2)
+
+	// what was held goes out once the quiet hours are over
+	it.Nil(n.Flush(ctx, night.Add(time.Hour)))
+	it.Len(channel.sent, 1)
+	it.Nil(n.Flush(ctx, day.Add(24*time.Hour)))
+	it.Len(channel.sent, 2)
+	it.Equal(notify.TypeOver, channel.sent[1].Type)
+	it.Nil(n.Flush(ctx, day.Add(24*time.Hour)))
+	it.Len(channel.sent, 2)
+
+	// or ahead of the next one sent outside of them
+	it.Nil(n.Notify(ctx, notify.Notification{Player: player, Type: notify.TypeOver, Time: night}))
+	it.Nil(n.Notify(ctx, notify.Notification{Player: player, Type: notify.TypeYourTurn, Time: day}))
+	it.Len(channel.sent, 4)
+	it.Equal(notify.TypeOver, channel.sent[2].Type)
+	it.Equal(notify.TypeYourTurn, channel.sent[3].Type)
+
+	it.Nil(n.SetPreferences(player, notify.Preferences{Channels: []string{notify.ChannelEmail}}))
+	it.Nil(n.Notify(ctx, notify.Notification{Player: player, Type: notify.TypeYourTurn}))
+	it.Len(channel.sent, 4)
+}
+
+func TestConfirmEmail(t *testing.T) {
+	it := assert.New(t)
+	n := notify.NewNotifier()
+	player := uuid.V4()
+	it.NotNil(n.SetPreferences(player, notify.Preferences{Email: "not an address"}))
+	_, err := n.NewEmailToken(player)
+	it.NotNil(err)
+
+	// only the address is kept and the player can't confirm it themselves
+	it.Nil(n.SetPreferences(player, notify.Preferences{Email: "Ann <ann@example.com>", EmailConfirmed: true}))
+	it.Equal("ann@example.com", n.Preferences(player).Email)
+	it.False(n.Preferences(player).EmailConfirmed)
+
+	first, err := n.NewEmailToken(player)
+	it.Nil(err)
+	second, err := n.NewEmailToken(player)
+	it.Nil(err)
+	it.NotNil(n.ConfirmEmail(player, first))
+	it.NotNil(n.ConfirmEmail(uuid.V4(), second))
+	it.Nil(n.ConfirmEmail(player, second))
+	it.True(n.Preferences(player).EmailConfirmed)
+	it.NotNil(n.ConfirmEmail(player, second))
+	_, err = n.NewEmailToken(player)
+	it.NotNil(err)
+
+	// saving the same address keeps it confirmed, a new one starts over and
+	// a token for the old one doesn't confirm it
+	it.Nil(n.SetPreferences(player, notify.Preferences{Email: "ann@example.com"}))
+	it.True(n.Preferences(player).EmailConfirmed)
+	it.Nil(n.SetPreferences(player, notify.Preferences{Email: "bob@example.com"}))
+	it.False(n.Preferences(player).EmailConfirmed)
+	token, err := n.NewEmailToken(player)
+	it.Nil(err)
+	it.Nil(n.SetPreferences(player, notify.Preferences{Email: "cat@example.com"}))
+	it.NotNil(n.ConfirmEmail(player, token))
+	it.False(n.Preferences(player).EmailConfirmed)
+}
@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"context"
+	"sync"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+var (
+	_ Interface = new(Memory)
+)
+
+// Memory keeps objects in process, it refuses writes older than what it holds
+type Memory struct {
+	sync.Mutex
+	objects map[string]Object
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		objects: make(map[string]Object),
+	}
+}
+
+func (m *Memory) CheckAndSet(ctx context.Context, obj Object) (*Object, error) {
+	m.Lock()
+	defer m.Unlock()
+	key := obj.ID.ToFullString()
+	if existing, has := m.objects[key]; has && existing.ObjectVersion > obj.ObjectVersion {
+		return &existing, ErrConflict
+	}
+	m.objects[key] = obj
+	return &obj, nil
+}
+
+func (m *Memory) Load(ctx context.Context, id uuid.UUID) (*Object, error) {
+	m.Lock()
+	defer m.Unlock()
+	obj, has := m.objects[id.ToFullString()]
+	if !has {
+		return nil, ErrNotFound
+	}
+	return &obj, nil
+}
@@ -0,0 +1,148 @@
+package v1alpha1
+
+import (
+	"math"
+
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+const (
+	DefaultRating     = 1500
+	DefaultDeviation  = 350
+	DefaultVolatility = 0.06
+	// ProvisionalDeviation is the deviation above which a rating isn't
+	// trusted yet
+	ProvisionalDeviation = 110
+
+	// tau limits how fast the volatility changes
+	tau = 0.5
+	// scale converts between the Glicko and Glicko-2 scales
+	scale     = 173.7178
+	tolerance = 0.000001
+)
+
+// Rating is a Glicko-2 rating, Deviation is how unsure it is and Volatility
+// how erratic the player's results are
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+	Games      int
+}
+
+// Result is one outcome against an opponent, 1 for a win, 0.5 for a draw
+// and 0 for a loss
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+func NewRating() Rating {
+	return Rating{
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// IsProvisional returns if the player hasn't played enough for the rating
+// to mean much
+func (r Rating) IsProvisional() bool {
+	return r.Deviation > ProvisionalDeviation
+}
+
+// Update rates the results as one rating period, without results only the
+// deviation grows
+func (r Rating) Update(results []Result) Rating {
+	mu := (r.Rating - DefaultRating) / scale
+	phi := r.Deviation / scale
+	if len(results) == 0 {
+		r.Deviation = math.Sqrt(phi*phi+r.Volatility*r.Volatility) * scale
+		return r
+	}
+
+	var variance, improvement float64
+	for _, result := range results {
+		muj := (result.Opponent.Rating - DefaultRating) / scale
+		g := weight(result.Opponent.Deviation / scale)
+		e := 1 / (1 + math.Exp(-g*(mu-muj)))
+		variance += g * g * e * (1 - e)
+		improvement += g * (result.Score - e)
+	}
+	v := 1 / variance
+	delta := v * improvement
+
+	sigma := volatility(phi, v, delta, r.Volatility)
+	pre := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(pre*pre)+1/v)
+	mu += phi * phi * improvement
+
+	return Rating{
+		Rating:     mu*scale + DefaultRating,
+		Deviation:  phi * scale,
+		Volatility: sigma,
+		Games:      r.Games + 1,
+	}
+}
+
+// Rate updates the ratings of everyone in a finished game, ratings are in
+// the order of the standings. A game of more than two is rated as every
+// pair of players playing each other, the better place wins
+func Rate(standings []game.Standing, ratings []Rating) []Rating {
+	updated := make([]Rating, len(ratings))
+	for i := range standings {
+		var results []Result
+		for j := range standings {
+			if i == j {
+				continue
+			}
+			result := Result{Opponent: ratings[j], Score: 0.5}
+			if standings[i].Place < standings[j].Place {
+				result.Score = 1
+			} else if standings[i].Place > standings[j].Place {
+				result.Score = 0
+			}
+			results = append(results, result)
+		}
+		updated[i] = ratings[i].Update(results)
+	}
+	return updated
+}
+
+func weight(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// volatility finds the new volatility with the Illinois algorithm
+func volatility(phi, v, delta, sigma float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > tolerance {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA = fA / 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
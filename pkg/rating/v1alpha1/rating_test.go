@@ -0,0 +1,52 @@
+package v1alpha1_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	rating "github.com/mat285/boardgames/pkg/rating/v1alpha1"
+)
+
+func near(expected, actual, within float64) bool {
+	return math.Abs(expected-actual) <= within
+}
+
+// TestUpdate is the worked example of the Glicko-2 paper
+func TestUpdate(t *testing.T) {
+	it := assert.New(t)
+	player := rating.Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	updated := player.Update([]rating.Result{
+		{Opponent: rating.Rating{Rating: 1400, Deviation: 30}, Score: 1},
+		{Opponent: rating.Rating{Rating: 1550, Deviation: 100}, Score: 0},
+		{Opponent: rating.Rating{Rating: 1700, Deviation: 300}, Score: 0},
+	})
+	it.True(near(1464.06, updated.Rating, 0.01), updated.Rating)
+	it.True(near(151.52, updated.Deviation, 0.01), updated.Deviation)
+	it.True(near(0.05999, updated.Volatility, 0.00001), updated.Volatility)
+	it.Equal(1, updated.Games)
+
+	idle := player.Update(nil)
+	it.Equal(player.Rating, idle.Rating)
+	it.True(idle.Deviation > player.Deviation)
+}
+
+func TestRate(t *testing.T) {
+	it := assert.New(t)
+	a, b, c := uuid.V4(), uuid.V4(), uuid.V4()
+	standings := game.Rank([]game.Score{
+		{Player: a, Points: 15},
+		{Player: b, Points: 12},
+		{Player: c, Points: 12},
+	})
+	ratings := []rating.Rating{rating.NewRating(), rating.NewRating(), rating.NewRating()}
+	it.True(ratings[0].IsProvisional())
+
+	updated := rating.Rate(standings, ratings)
+	it.True(updated[0].Rating > rating.DefaultRating)
+	it.True(updated[1].Rating < rating.DefaultRating)
+	// the two players tied for second drew each other
+	it.True(near(updated[1].Rating, updated[2].Rating, 0.000001))
+}
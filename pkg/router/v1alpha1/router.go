@@ -18,12 +18,14 @@ type Router struct {
 	sync.Mutex
 	clients map[string]*connection.MultiConn
 	servers map[string]connection.ServerInfo
+	online  map[string]int
 }
 
 func NewRouter() *Router {
 	s := &Router{
 		clients: make(map[string]*connection.MultiConn),
 		servers: make(map[string]connection.ServerInfo),
+		online:  make(map[string]int),
 	}
 	return s
 }
@@ -38,6 +40,14 @@ func (s *Router) ConnectClient(ctx context.Context, client connection.ClientInfo
 	return nil
 }
 
+// DisconnectClient forgets the client with all of its connections, packets
+// for it are dropped from now on
+func (s *Router) DisconnectClient(ctx context.Context, id uuid.UUID) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.clients, id.ToFullString())
+}
+
 func (s *Router) ConnectServer(ctx context.Context, server connection.ServerInfo) error {
 	s.Lock()
 	defer s.Unlock()
@@ -45,6 +55,14 @@ func (s *Router) ConnectServer(ctx context.Context, server connection.ServerInfo
 	return nil
 }
 
+// DisconnectServer forgets the server, packets for it are dropped from now
+// on
+func (s *Router) DisconnectServer(ctx context.Context, id uuid.UUID) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.servers, id.ToFullString())
+}
+
 func (s *Router) Receive(ctx context.Context, packet wire.Packet) error {
 	if s.GetClient(packet.Origin) != nil {
 		s := s.GetServer(packet.Destination)
@@ -73,8 +91,45 @@ func (s *Router) GetServer(id uuid.UUID) connection.ServerInfo {
 	return s.servers[id.ToFullString()]
 }
 
+// Servers lists every connected server
+func (s *Router) Servers() []connection.ServerInfo {
+	s.Lock()
+	defer s.Unlock()
+	servers := make([]connection.ServerInfo, 0, len(s.servers))
+	for _, server := range s.servers {
+		servers = append(servers, server)
+	}
+	return servers
+}
+
 func (s *Router) GetClient(id uuid.UUID) *connection.MultiConn {
 	s.Lock()
 	defer s.Unlock()
 	return s.clients[id.ToFullString()]
 }
+
+// Present counts the client as online until the returned func is called,
+// a client with several connections stays online until the last one closes
+func (s *Router) Present(id uuid.UUID) func() {
+	s.Lock()
+	s.online[id.ToFullString()]++
+	s.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.Lock()
+			defer s.Unlock()
+			key := id.ToFullString()
+			if s.online[key]--; s.online[key] <= 0 {
+				delete(s.online, key)
+			}
+		})
+	}
+}
+
+// Online returns if the client has an open connection
+func (s *Router) Online(id uuid.UUID) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.online[id.ToFullString()] > 0
+}
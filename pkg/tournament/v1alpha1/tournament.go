@@ -0,0 +1,477 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+)
+
+type Format string
+
+const (
+	// FormatSwiss pairs players on the same score each round for a set
+	// number of rounds
+	FormatSwiss Format = "swiss"
+	// FormatRoundRobin plays everyone against everyone once, two at a table
+	FormatRoundRobin Format = "round-robin"
+	// FormatSingleElimination only sends the winner of each table on to the
+	// next round
+	FormatSingleElimination Format = "single-elimination"
+)
+
+const (
+	ScoreMatchPoints = "match-points"
+	ScoreRoundsWon   = "rounds-won"
+	ScoreBuchholz    = "buchholz"
+	ScoreGamePoints  = "game-points"
+)
+
+// Tournament is a set of rounds played at tables by the same players, each
+// table is a game of its own
+type Tournament struct {
+	ID        uuid.UUID
+	Name      string
+	Game      string
+	Config    json.RawMessage `json:",omitempty"`
+	Format    Format
+	Organizer uuid.UUID
+	// Players are in seed order, the first seed is the strongest
+	Players   []game.Player
+	TableSize int
+	// Rounds is how many rounds are played, round robins work it out
+	// themselves and eliminations go until one player is left
+	Rounds int
+	Played []Round
+}
+
+type Round struct {
+	Number int
+	Tables []Table
+}
+
+// Table is one game of a round, Game is the engine playing it and stays
+// empty for a bye. Standings are set once the game is over
+type Table struct {
+	ID        uuid.UUID
+	Game      uuid.UUID `json:",omitempty"`
+	Players   []uuid.UUID
+	Standings []game.Standing
+}
+
+// IsBye returns if the player at the table sits the round out, a bye counts
+// as a win
+func (t Table) IsBye() bool {
+	return len(t.Players) == 1
+}
+
+func (t Table) IsDone() bool {
+	return t.Standings != nil
+}
+
+// Winner is the first player in first place at the table
+func (t Table) Winner() uuid.UUID {
+	for _, standing := range t.Standings {
+		if standing.Place == 1 {
+			return standing.Player
+		}
+	}
+	return nil
+}
+
+func New(name, gameName string, format Format, organizer uuid.UUID, players []game.Player, tableSize, rounds int) (*Tournament, error) {
+	if len(players) < 2 {
+		return nil, fmt.Errorf("Tournament needs at least 2 players")
+	}
+	seen := make(map[string]bool)
+	for _, p := range players {
+		if seen[p.ID.ToFullString()] {
+			return nil, fmt.Errorf("Player %s entered twice", p.Username)
+		}
+		seen[p.ID.ToFullString()] = true
+	}
+	if tableSize < 2 {
+		tableSize = 2
+	}
+	switch format {
+	case FormatSwiss:
+		if rounds <= 0 {
+			// enough rounds to leave one undefeated player in pairs
+			for n := 1; n < len(players); n *= 2 {
+				rounds++
+			}
+		}
+	case FormatRoundRobin:
+		tableSize = 2
+		rounds = len(players) - 1 + len(players)%2
+	case FormatSingleElimination:
+		rounds = 0
+	default:
+		return nil, fmt.Errorf("Unknown tournament format %q", format)
+	}
+	return &Tournament{
+		ID:        uuid.V4(),
+		Name:      name,
+		Game:      gameName,
+		Format:    format,
+		Organizer: organizer,
+		Players:   append([]game.Player{}, players...),
+		TableSize: tableSize,
+		Rounds:    rounds,
+	}, nil
+}
+
+// Start pairs the first round
+func (t *Tournament) Start() (*Round, error) {
+	if len(t.Played) > 0 {
+		return nil, fmt.Errorf("Tournament already started")
+	}
+	return t.nextRound(), nil
+}
+
+// Current is the round being played, nil before the start
+func (t *Tournament) Current() *Round {
+	if len(t.Played) == 0 {
+		return nil
+	}
+	return &t.Played[len(t.Played)-1]
+}
+
+// Record takes the result of the game played at a table of the current
+// round. Once the round is complete it pairs the next one and returns it,
+// unless the tournament is over
+func (t *Tournament) Record(gameID uuid.UUID, standings []game.Standing) (*Round, error) {
+	round := t.Current()
+	if round == nil {
+		return nil, fmt.Errorf("Tournament not started")
+	}
+	found := false
+	for i := range round.Tables {
+		table := &round.Tables[i]
+		if table.Game == nil || !table.Game.Equal(gameID) {
+			continue
+		}
+		if table.IsDone() {
+			return nil, fmt.Errorf("Table %s already has a result", table.ID)
+		}
+		table.Standings = standings
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("Game %s is not played in round %d", gameID, round.Number)
+	}
+	for _, table := range round.Tables {
+		if !table.IsDone() {
+			return nil, nil
+		}
+	}
+	if t.IsDone() {
+		return nil, nil
+	}
+	return t.nextRound(), nil
+}
+
+// IsDone returns if every round has been played
+func (t *Tournament) IsDone() bool {
+	round := t.Current()
+	if round == nil {
+		return false
+	}
+	for _, table := range round.Tables {
+		if !table.IsDone() {
+			return false
+		}
+	}
+	if t.Format == FormatSingleElimination {
+		return len(t.advancing()) <= 1
+	}
+	return len(t.Played) >= t.Rounds
+}
+
+// Winners are the players in first place once the tournament is over
+func (t *Tournament) Winners() []uuid.UUID {
+	if !t.IsDone() {
+		return nil
+	}
+	var winners []uuid.UUID
+	for _, standing := range t.Standings() {
+		if standing.Place == 1 {
+			winners = append(winners, standing.Player)
+		}
+	}
+	return winners
+}
+
+// Standings ranks the players over the finished tables. Swiss and round
+// robin go by match points, then Buchholz, the match points of everyone
+// they played, then the points they scored in their games. Eliminations go
+// by the rounds won first. Seeds break whatever is still level
+func (t *Tournament) Standings() []game.Standing {
+	matchPoints := make(map[string]int)
+	roundsWon := make(map[string]int)
+	gamePoints := make(map[string]int)
+	opponents := make(map[string][]uuid.UUID)
+	for _, round := range t.Played {
+		for _, table := range round.Tables {
+			if !table.IsDone() {
+				continue
+			}
+			winner := table.Winner()
+			for _, standing := range table.Standings {
+				key := standing.Player.ToFullString()
+				matchPoints[key] += t.matchPoints(table, standing)
+				gamePoints[key] += standing.Points
+				if winner.Equal(standing.Player) {
+					roundsWon[key]++
+				}
+				for _, other := range table.Players {
+					if !other.Equal(standing.Player) {
+						opponents[key] = append(opponents[key], other)
+					}
+				}
+			}
+		}
+	}
+
+	scores := make([]game.Score, len(t.Players))
+	for i, p := range t.Players {
+		key := p.ID.ToFullString()
+		buchholz := 0
+		for _, other := range opponents[key] {
+			buchholz += matchPoints[other.ToFullString()]
+		}
+		score := game.Score{
+			Player: p.ID,
+			Points: matchPoints[key],
+			Breakdown: map[string]int{
+				ScoreMatchPoints: matchPoints[key],
+				ScoreRoundsWon:   roundsWon[key],
+				ScoreBuchholz:    buchholz,
+				ScoreGamePoints:  gamePoints[key],
+			},
+			TieBreak: []int{buchholz, gamePoints[key], -i},
+		}
+		if t.Format == FormatSingleElimination {
+			score.Points = roundsWon[key]
+			score.TieBreak = []int{matchPoints[key], gamePoints[key], -i}
+		}
+		scores[i] = score
+	}
+	return game.Rank(scores)
+}
+
+// matchPoints scores a finished table, everyone gets a point for each
+// player they finished ahead of and a bye is a win
+func (t *Tournament) matchPoints(table Table, standing game.Standing) int {
+	if table.IsBye() {
+		return t.TableSize - 1
+	}
+	return len(table.Standings) - standing.Place
+}
+
+func (t *Tournament) nextRound() *Round {
+	var tables [][]uuid.UUID
+	switch t.Format {
+	case FormatRoundRobin:
+		tables = roundRobinTables(t.playerIDs(), len(t.Played))
+	case FormatSwiss:
+		tables = t.swissTables()
+	case FormatSingleElimination:
+		tables = chunkTables(t.advancing(), t.TableSize, nil)
+	}
+	round := Round{Number: len(t.Played) + 1}
+	for _, players := range tables {
+		table := Table{ID: uuid.V4(), Players: players}
+		if table.IsBye() {
+			table.Standings = []game.Standing{{Score: game.Score{Player: players[0]}, Place: 1}}
+		}
+		round.Tables = append(round.Tables, table)
+	}
+	t.Played = append(t.Played, round)
+	return t.Current()
+}
+
+func (t *Tournament) playerIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, len(t.Players))
+	for i, p := range t.Players {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+// advancing are the players still in an elimination, the winners of the
+// last round in table order
+func (t *Tournament) advancing() []uuid.UUID {
+	round := t.Current()
+	if round == nil {
+		return t.playerIDs()
+	}
+	var winners []uuid.UUID
+	for _, table := range round.Tables {
+		if winner := table.Winner(); winner != nil {
+			winners = append(winners, winner)
+		}
+	}
+	return winners
+}
+
+// swissTables seats players next to those on the same score, two player
+// tables avoid a rematch where they can
+func (t *Tournament) swissTables() [][]uuid.UUID {
+	var order []uuid.UUID
+	if len(t.Played) == 0 {
+		order = t.playerIDs()
+	} else {
+		for _, standing := range t.Standings() {
+			order = append(order, standing.Player)
+		}
+	}
+	if t.TableSize != 2 {
+		return chunkTables(order, t.TableSize, t.hadBye)
+	}
+
+	var bye uuid.UUID
+	if len(order)%2 == 1 {
+		bye = lowestWithout(order, t.hadBye)
+		order = without(order, bye)
+	}
+	var tables [][]uuid.UUID
+	paired := make(map[string]bool)
+	for i, p := range order {
+		if paired[p.ToFullString()] {
+			continue
+		}
+		var opponent uuid.UUID
+		for _, other := range order[i+1:] {
+			if paired[other.ToFullString()] {
+				continue
+			}
+			if opponent == nil {
+				opponent = other
+			}
+			if !t.played(p, other) {
+				opponent = other
+				break
+			}
+		}
+		paired[p.ToFullString()] = true
+		paired[opponent.ToFullString()] = true
+		tables = append(tables, []uuid.UUID{p, opponent})
+	}
+	if bye != nil {
+		tables = append(tables, []uuid.UUID{bye})
+	}
+	return tables
+}
+
+// played returns if the two players already met at a table
+func (t *Tournament) played(a, b uuid.UUID) bool {
+	for _, round := range t.Played {
+		for _, table := range round.Tables {
+			if contains(table.Players, a) && contains(table.Players, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *Tournament) hadBye(player uuid.UUID) bool {
+	for _, round := range t.Played {
+		for _, table := range round.Tables {
+			if table.IsBye() && table.Players[0].Equal(player) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// roundRobinTables pairs the players for the round with the circle method,
+// one player sits out each round when the count is odd
+func roundRobinTables(players []uuid.UUID, round int) [][]uuid.UUID {
+	circle := append([]uuid.UUID{}, players...)
+	if len(circle)%2 == 1 {
+		circle = append(circle, nil)
+	}
+	n := len(circle)
+	// the first seat stays put and the rest turn one seat each round
+	rotated := []uuid.UUID{circle[0]}
+	for i := 0; i < n-1; i++ {
+		rotated = append(rotated, circle[1+(i+round)%(n-1)])
+	}
+	var tables [][]uuid.UUID
+	for i := 0; i < n/2; i++ {
+		a, b := rotated[i], rotated[n-1-i]
+		switch {
+		case a == nil:
+			tables = append(tables, []uuid.UUID{b})
+		case b == nil:
+			tables = append(tables, []uuid.UUID{a})
+		default:
+			tables = append(tables, []uuid.UUID{a, b})
+		}
+	}
+	return tables
+}
+
+// chunkTables seats the players in order at tables of the size, the last
+// table takes whoever is left. A single player left over gets a bye, the
+// lowest one that hasn't had one if skip is given and the first otherwise
+func chunkTables(players []uuid.UUID, size int, skip func(uuid.UUID) bool) [][]uuid.UUID {
+	if len(players) == 0 {
+		return nil
+	}
+	var bye uuid.UUID
+	if len(players)%size == 1 {
+		if skip != nil {
+			bye = lowestWithout(players, skip)
+		} else {
+			bye = players[0]
+		}
+		players = without(players, bye)
+	}
+	var tables [][]uuid.UUID
+	for start := 0; start < len(players); start += size {
+		end := start + size
+		if end > len(players) {
+			end = len(players)
+		}
+		tables = append(tables, append([]uuid.UUID{}, players[start:end]...))
+	}
+	if bye != nil {
+		tables = append(tables, []uuid.UUID{bye})
+	}
+	return tables
+}
+
+// lowestWithout is the last player the check doesn't hold for, or the last
+// player when it holds for everyone
+func lowestWithout(players []uuid.UUID, check func(uuid.UUID) bool) uuid.UUID {
+	for i := len(players) - 1; i >= 0; i-- {
+		if !check(players[i]) {
+			return players[i]
+		}
+	}
+	return players[len(players)-1]
+}
+
+func without(players []uuid.UUID, player uuid.UUID) []uuid.UUID {
+	ret := make([]uuid.UUID, 0, len(players))
+	for _, p := range players {
+		if !p.Equal(player) {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+func contains(players []uuid.UUID, player uuid.UUID) bool {
+	for _, p := range players {
+		if p.Equal(player) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	tournament "github.com/mat285/boardgames/pkg/tournament/v1alpha1"
+)
+
+func players(n int) []game.Player {
+	ps := make([]game.Player, n)
+	for i := range ps {
+		ps[i] = game.Player{ID: uuid.V4(), Username: string(rune('a' + i))}
+	}
+	return ps
+}
+
+// play finishes every table of the round with the first seated player
+// winning and returns the next round
+func play(it *assert.Assertions, t *tournament.Tournament, round *tournament.Round) *tournament.Round {
+	for i := range round.Tables {
+		if round.Tables[i].IsBye() {
+			continue
+		}
+		round.Tables[i].Game = uuid.V4()
+	}
+	var next *tournament.Round
+	for _, table := range round.Tables {
+		if table.IsBye() {
+			continue
+		}
+		scores := make([]game.Score, len(table.Players))
+		for i, p := range table.Players {
+			scores[i] = game.Score{Player: p, Points: 10 - i}
+		}
+		var err error
+		next, err = t.Record(table.Game, game.Rank(scores))
+		it.Nil(err)
+	}
+	return next
+}
+
+func TestRoundRobin(t *testing.T) {
+	it := assert.New(t)
+	ps := players(5)
+	tm, err := tournament.New("monthly", "splendor", tournament.FormatRoundRobin, uuid.V4(), ps, 4, 0)
+	it.Nil(err)
+	it.Equal(5, tm.Rounds)
+
+	met := make(map[string]int)
+	byes := make(map[string]int)
+	round, err := tm.Start()
+	it.Nil(err)
+	for round != nil {
+		for _, table := range round.Tables {
+			if table.IsBye() {
+				byes[table.Players[0].ToFullString()]++
+				continue
+			}
+			it.Len(table.Players, 2)
+			met[table.Players[0].ToFullString()+table.Players[1].ToFullString()]++
+			met[table.Players[1].ToFullString()+table.Players[0].ToFullString()]++
+		}
+		round = play(it, tm, round)
+	}
+	it.True(tm.IsDone())
+	it.Len(tm.Played, 5)
+	// everyone met everyone else exactly once and sat out once
+	it.Len(met, 20)
+	for _, p := range ps {
+		it.Equal(1, byes[p.ID.ToFullString()])
+	}
+	it.Len(tm.Winners(), 1)
+}
+
+func TestSwiss(t *testing.T) {
+	it := assert.New(t)
+	ps := players(4)
+	tm, err := tournament.New("monthly", "splendor", tournament.FormatSwiss, uuid.V4(), ps, 2, 0)
+	it.Nil(err)
+	it.Equal(2, tm.Rounds)
+
+	round, err := tm.Start()
+	it.Nil(err)
+	it.Equal([]uuid.UUID{ps[0].ID, ps[1].ID}, round.Tables[0].Players)
+	round = play(it, tm, round)
+	// the two winners meet in the second round
+	it.Equal([]uuid.UUID{ps[0].ID, ps[2].ID}, round.Tables[0].Players)
+	it.Nil(play(it, tm, round))
+	it.True(tm.IsDone())
+	it.Equal([]uuid.UUID{ps[0].ID}, tm.Winners())
+
+	standings := tm.Standings()
+	it.Equal(2, standings[0].Breakdown[tournament.ScoreMatchPoints])
+	it.Equal(2, standings[0].Breakdown[tournament.ScoreBuchholz])
+}
+
+func TestSingleElimination(t *testing.T) {
+	it := assert.New(t)
+	ps := players(5)
+	tm, err := tournament.New("monthly", "splendor", tournament.FormatSingleElimination, uuid.V4(), ps, 2, 0)
+	it.Nil(err)
+
+	round, err := tm.Start()
+	it.Nil(err)
+	// the top seed sits out the odd first round
+	it.Len(round.Tables, 3)
+	it.Equal([]uuid.UUID{ps[0].ID}, round.Tables[2].Players)
+	for round != nil {
+		round = play(it, tm, round)
+	}
+	it.True(tm.IsDone())
+	it.Len(tm.Played, 3)
+	it.Len(tm.Winners(), 1)
+
+	_, err = tm.Record(uuid.V4(), nil)
+	it.NotNil(err)
+}
@@ -59,7 +59,7 @@ func NewHeader() Header {
 	return Header{
 		Metadata: Metadata{
 			ID:         uuid.V4(),
-			APIVersion: apiversions.V1Alpha1,
+			APIVersion: apiversions.Latest,
 		},
 		Options: make(HeaderOptions),
 	}
@@ -14,13 +14,52 @@ const (
 	RouteUserLogin = RouteUserBase + "/login"
 	RouteUserGames = RouteUserBase + "/games"
 
-	RouteGameBase   = RouteBase + "/game"
-	RouteGamesBase  = RouteBase + "/games"
-	RouteNewGame    = RouteGamesBase + "/:name/new"
-	RouteJoinGame   = RouteGameBase + "/:id/join"
-	RouteStartGame  = RouteGameBase + "/:id/start"
-	RouteGameState  = RouteGameBase + "/:id/state"
-	RouteGamePacket = RouteGameBase + "/:id/packet"
+	RouteGameBase     = RouteBase + "/game"
+	RouteGamesBase    = RouteBase + "/games"
+	RouteNewGame      = RouteGamesBase + "/:name/new"
+	RouteJoinGame     = RouteGameBase + "/:id/join"
+	RouteStartGame    = RouteGameBase + "/:id/start"
+	RouteGameTeam     = RouteGameBase + "/:id/team"
+	RouteGameState    = RouteGameBase + "/:id/state"
+	RouteGameRecord   = RouteGameBase + "/:id/record"
+	RouteGamePacket   = RouteGameBase + "/:id/packet"
+	RouteRematch      = RouteGameBase + "/:id/rematch"
+	RouteNewSeries    = RouteGameBase + "/:id/series"
+	RouteInvite       = RouteGameBase + "/:id/invite"
+	RoutePassword     = RouteGameBase + "/:id/password"
+	RouteSpectate     = RouteGameBase + "/:id/spectate"
+	RouteInviteFriend = RouteGameBase + "/:id/invitations"
+	RouteJoinCode     = RouteBase + "/join/:code"
+
+	RouteSeries = RouteBase + "/series/:id"
+
+	RouteLobby = RouteBase + "/lobby"
+
+	RouteMatchmaking  = RouteBase + "/matchmaking"
+	RouteQueue        = RouteMatchmaking + "/:name"
+	RouteConfirmMatch = RouteBase + "/match/:id/confirm"
+
+	RouteTournaments     = RouteBase + "/tournaments"
+	RouteNewTournament   = RouteTournaments + "/:name/new"
+	RouteTournament      = RouteBase + "/tournament/:id"
+	RouteStartTournament = RouteTournament + "/start"
+
+	RouteLeaderboard = RouteBase + "/leaderboard/:game"
+	RouteRatings     = RouteBase + "/ratings/:username"
+
+	RouteProfile = RouteBase + "/users/:name/profile"
+
+	RouteNotifications           = RouteBase + "/notifications"
+	RouteReadNotifications       = RouteNotifications + "/read"
+	RouteNotificationPreferences = RouteNotifications + "/preferences"
+	RouteConfirmEmail            = RouteNotificationPreferences + "/confirm"
+
+	RouteFriends = RouteBase + "/friends"
+	RouteFriend  = RouteFriends + "/:username"
+
+	RouteInvitations       = RouteBase + "/invitations"
+	RouteAcceptInvitation  = RouteInvitations + "/:id/accept"
+	RouteDeclineInvitation = RouteInvitations + "/:id/decline"
 
 	RouteWebSockets = RouteBase + "/websockets"
 )
@@ -32,4 +71,19 @@ const (
 	PacketTypeNewGameResponse   wire.PacketType = wire.PacketTypeAPI + 4
 	PacketTypeJoinGameRequest   wire.PacketType = wire.PacketTypeAPI + 5
 	PacketTypeJoinGameResponse  wire.PacketType = wire.PacketTypeAPI + 6
+
+	PacketTypeLobbySubscribe   wire.PacketType = wire.PacketTypeAPI + 7
+	PacketTypeLobbyUnsubscribe wire.PacketType = wire.PacketTypeAPI + 8
+	PacketTypeLobbyEvent       wire.PacketType = wire.PacketTypeAPI + 9
+
+	PacketTypeMatchFound     wire.PacketType = wire.PacketTypeAPI + 10
+	PacketTypeMatchStarted   wire.PacketType = wire.PacketTypeAPI + 11
+	PacketTypeMatchCancelled wire.PacketType = wire.PacketTypeAPI + 12
+	PacketTypeQueueTimeout   wire.PacketType = wire.PacketTypeAPI + 13
+
+	PacketTypeTournamentSubscribe   wire.PacketType = wire.PacketTypeAPI + 14
+	PacketTypeTournamentUnsubscribe wire.PacketType = wire.PacketTypeAPI + 15
+	PacketTypeTournamentUpdate      wire.PacketType = wire.PacketTypeAPI + 16
+
+	PacketTypeInvitation wire.PacketType = wire.PacketTypeAPI + 17
 )
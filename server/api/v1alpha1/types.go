@@ -1,6 +1,18 @@
 package v1alpha1
 
-import "github.com/blend/go-sdk/uuid"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+	friends "github.com/mat285/boardgames/pkg/friends/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	matchmaking "github.com/mat285/boardgames/pkg/matchmaking/v1alpha1"
+	model "github.com/mat285/boardgames/pkg/model/v1alpha1"
+	notify "github.com/mat285/boardgames/pkg/notify/v1alpha1"
+	rating "github.com/mat285/boardgames/pkg/rating/v1alpha1"
+	tournament "github.com/mat285/boardgames/pkg/tournament/v1alpha1"
+)
 
 type ListGamesResponse struct {
 	Games []string
@@ -14,3 +26,252 @@ type NewGameRequest struct {
 type GameResponse struct {
 	ID uuid.UUID
 }
+
+// LobbyQuery filters and pages the lobby, empty filters match everything
+type LobbyQuery struct {
+	Game    string
+	Variant string
+	Status  model.GameStatus
+	// Host is the username of the player that set the game up
+	Host string
+	// Seats is the fewest open seats a game needs, games without a seat
+	// limit always have room
+	Seats  int
+	Offset int
+	Limit  int
+}
+
+// Matches returns if the game passes the filters of the query
+func (q LobbyQuery) Matches(g model.Game) bool {
+	if len(q.Game) > 0 && q.Game != g.Game {
+		return false
+	}
+	if len(q.Variant) > 0 && q.Variant != g.Variant {
+		return false
+	}
+	if len(q.Status) > 0 && q.Status != g.Status {
+		return false
+	}
+	if len(q.Host) > 0 && q.Host != g.Host.Username {
+		return false
+	}
+	if q.Seats > 0 && g.MaxPlayers > 0 && g.OpenSeats < q.Seats {
+		return false
+	}
+	return true
+}
+
+// LobbyResponse is one page of the lobby, Total counts every game that
+// matched the filters
+type LobbyResponse struct {
+	Games  []model.Game
+	Total  int
+	Offset int
+	Limit  int
+}
+
+type LobbyEventType string
+
+const (
+	LobbyEventCreated    LobbyEventType = "created"
+	LobbyEventSeatFilled LobbyEventType = "seat-filled"
+	LobbyEventStarted    LobbyEventType = "started"
+)
+
+// LobbyEvent is pushed to everyone subscribed to the lobby when a public
+// game changes
+type LobbyEvent struct {
+	Type LobbyEventType
+	Game model.Game
+}
+
+// Invite is the short code a game is shared by, Code is empty once the host
+// turned it off
+type Invite struct {
+	Game        uuid.UUID
+	Code        string
+	HasPassword bool
+}
+
+// JoinRequest carries the password of a private table, it also sets the
+// password when the host sends it
+type JoinRequest struct {
+	Password string
+}
+
+// QueueRequest asks for a game of the given size, Window keeps opponents
+// within that many rating points and zero takes anyone
+type QueueRequest struct {
+	Players int
+	Window  int
+}
+
+type TicketStatus string
+
+const (
+	TicketWaiting TicketStatus = "waiting"
+	TicketMatched TicketStatus = "matched"
+)
+
+// Ticket is a player's place in the matchmaking queue, Match is set once a
+// table was found for them
+type Ticket struct {
+	matchmaking.Ticket
+	Username string
+	Status   TicketStatus
+	Match    uuid.UUID `json:",omitempty"`
+}
+
+// Match is a table the queue put together, it starts once every player
+// confirmed and Bots fill the seats nobody queued for
+type Match struct {
+	ID        uuid.UUID
+	Game      string
+	Players   []uuid.UUID
+	Bots      int
+	Confirmed []uuid.UUID
+	Created   time.Time
+	// Engine is the game, only set once the match started
+	Engine uuid.UUID `json:",omitempty"`
+}
+
+// NewTournamentRequest sets up a tournament, the players are entered by
+// username in seed order and Config is the config every table is played
+// with. TableSize defaults to the fewest players the game takes
+type NewTournamentRequest struct {
+	Name      string
+	Format    tournament.Format
+	Players   []string
+	TableSize int
+	Rounds    int
+	Config    json.RawMessage `json:",omitempty"`
+}
+
+// TournamentStatus is a tournament with where everyone stands, it is also
+// what's pushed to its players and subscribers after every finished table
+type TournamentStatus struct {
+	tournament.Tournament
+	Standings []game.Standing
+	Done      bool
+	Winners   []uuid.UUID
+}
+
+// TournamentSubscription is the body of the packets that start and stop
+// updates of a tournament
+type TournamentSubscription struct {
+	Tournament uuid.UUID
+}
+
+// PlayerRating is a player's rating in a game, History has the rating after
+// every rated game, oldest first
+type PlayerRating struct {
+	Player      uuid.UUID
+	Username    string
+	Game        string
+	Rating      rating.Rating
+	Provisional bool
+	History     []RatingChange `json:",omitempty"`
+}
+
+// RatingChange is the rating a game left the player with
+type RatingChange struct {
+	Game   uuid.UUID
+	Time   time.Time
+	Rating rating.Rating
+}
+
+// Leaderboard is a page of the players of a game, highest rated first
+type Leaderboard struct {
+	Game    string
+	Players []PlayerRating
+	Total   int
+	Offset  int
+	Limit   int
+}
+
+// Profile is what a player did across the finished games they played
+type Profile struct {
+	Player   uuid.UUID
+	Username string
+	Played   int
+	Wins     int
+	WinRate  float64
+	Games    []GameProfile
+	// Recent are the last games the player finished, newest first
+	Recent []RecentGame
+}
+
+// GameProfile is a player's results in one game, Stats are the game's own
+// if it keeps any
+type GameProfile struct {
+	Game             string
+	Played           int
+	Wins             int
+	WinRate          float64
+	AverageScore     float64
+	Variants         map[string]int
+	FavouriteVariant string
+	Rating           *PlayerRating `json:",omitempty"`
+	Stats            game.Stats    `json:",omitempty"`
+}
+
+// RecentGame is a finished game from the player's side of the table
+type RecentGame struct {
+	ID      uuid.UUID
+	Game    string
+	Variant string
+	Created time.Time
+	Players []game.Player
+	Place   int
+	Points  int
+}
+
+// Inbox is the current user's notifications, newest first
+type Inbox struct {
+	Notifications []notify.Notification
+	Unread        int
+}
+
+// ReadNotificationsRequest marks the notifications read, all of them when
+// IDs is empty
+type ReadNotificationsRequest struct {
+	IDs []uuid.UUID
+}
+
+// ConfirmEmailRequest carries the token mailed to the address
+type ConfirmEmailRequest struct {
+	Token string
+}
+
+// Friend is a user the current user added who added them back, Online is
+// set while they have a connection open
+type Friend struct {
+	Player   uuid.UUID
+	Username string
+	Online   bool
+}
+
+// Friends lists the current user's friends, Incoming are the users who
+// added them and Outgoing the ones they added, neither added back yet
+type Friends struct {
+	Friends  []Friend
+	Incoming []string
+	Outgoing []string
+}
+
+// InvitationStatus is where an invitation is at
+type InvitationStatus = friends.Status
+
+const (
+	InvitationPending  = friends.StatusPending
+	InvitationAccepted = friends.StatusAccepted
+	InvitationDeclined = friends.StatusDeclined
+)
+
+// Invitation asks a friend to take a seat at a game before it starts
+type Invitation = friends.Invitation
+
+// InviteFriendRequest invites the friend to the game
+type InviteFriendRequest struct {
+	Username string
+}
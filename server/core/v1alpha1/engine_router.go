@@ -8,6 +8,7 @@ import (
 	"github.com/blend/go-sdk/uuid"
 	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
 	"github.com/mat285/boardgames/pkg/game/v1alpha1"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
 	router "github.com/mat285/boardgames/pkg/router/v1alpha1"
 )
 
@@ -43,6 +44,21 @@ func (r *EngineRouter) GetEngine(id uuid.UUID) *engine.Engine {
 	return typed
 }
 
+// Engines lists every engine the router knows about
+func (r *EngineRouter) Engines() []*engine.Engine {
+	var engines []*engine.Engine
+	for _, server := range r.Servers() {
+		pipe, ok := server.(*Pipe)
+		if !ok {
+			continue
+		}
+		if typed, ok := pipe.Receiver.(*engine.Engine); ok {
+			engines = append(engines, typed)
+		}
+	}
+	return engines
+}
+
 func (r *EngineRouter) NewEngine(ctx context.Context, g v1alpha1.Game, host *engine.Player) (*engine.Engine, error) {
 	e := engine.NewEngine(g, host)
 	pipe := PipeEngine(e)
@@ -53,6 +69,82 @@ func (r *EngineRouter) NewEngine(ctx context.Context, g v1alpha1.Game, host *eng
 	return e, nil
 }
 
+// Rematch sets up the rematch of a finished game, its players are joined
+// to it already
+func (r *EngineRouter) Rematch(ctx context.Context, id uuid.UUID) (*engine.Engine, error) {
+	e := r.GetEngine(id)
+	if e == nil {
+		return nil, fmt.Errorf("No Engine")
+	}
+	next, err := e.Rematch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = r.ConnectServer(ctx, PipeEngine(next))
+	if err != nil {
+		return nil, err
+	}
+	r.clientEnginesLock.Lock()
+	for _, pid := range next.PlayerIDs() {
+		if _, has := r.clientEngines[pid.ToFullString()]; !has {
+			r.clientEngines[pid.ToFullString()] = make(map[string]bool)
+		}
+		r.clientEngines[pid.ToFullString()][next.ID.ToFullString()] = true
+	}
+	r.clientEnginesLock.Unlock()
+	return next, nil
+}
+
+func (r *EngineRouter) LoadEngine(ctx context.Context, g v1alpha1.Game, store persist.Interface, id uuid.UUID) (*engine.Engine, error) {
+	e, err := engine.Load(ctx, g, store, id)
+	if err != nil {
+		return nil, err
+	}
+	err = r.ConnectServer(ctx, PipeEngine(e))
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// DisconnectClient forgets the client and the engines it joined
+func (r *EngineRouter) DisconnectClient(ctx context.Context, id uuid.UUID) {
+	r.Router.DisconnectClient(ctx, id)
+	r.clientEnginesLock.Lock()
+	defer r.clientEnginesLock.Unlock()
+	delete(r.clientEngines, id.ToFullString())
+}
+
+// Hibernate drops the engine from memory once it is saved, ClientEngines
+// leaves it out until it is woken
+func (r *EngineRouter) Hibernate(ctx context.Context, id uuid.UUID) {
+	r.DisconnectServer(ctx, id)
+}
+
+// Wake loads a hibernated engine back and points its connected players at
+// it again
+func (r *EngineRouter) Wake(ctx context.Context, g v1alpha1.Game, store persist.Interface, id uuid.UUID) (*engine.Engine, error) {
+	e, err := r.LoadEngine(ctx, g, store, id)
+	if err != nil {
+		return nil, err
+	}
+	e.Lock()
+	players := e.PlayerIDs()
+	e.Unlock()
+	r.clientEnginesLock.Lock()
+	defer r.clientEnginesLock.Unlock()
+	for _, pid := range players {
+		if client := r.GetClient(pid); client != nil {
+			e.Reconnect(client)
+		}
+		if _, has := r.clientEngines[pid.ToFullString()]; !has {
+			r.clientEngines[pid.ToFullString()] = make(map[string]bool)
+		}
+		r.clientEngines[pid.ToFullString()][id.ToFullString()] = true
+	}
+	return e, nil
+}
+
 func (r *EngineRouter) StartEngine(ctx context.Context, id uuid.UUID) error {
 	e := r.GetEngine(id)
 	if e == nil {
@@ -84,6 +176,19 @@ func (r *EngineRouter) Join(ctx context.Context, clientID uuid.UUID, engine uuid
 	return nil
 }
 
+// Spectate has the client watch the engine without taking a seat
+func (r *EngineRouter) Spectate(ctx context.Context, clientID uuid.UUID, engine uuid.UUID) error {
+	client := r.GetClient(clientID)
+	if client == nil {
+		return fmt.Errorf("Unknown client")
+	}
+	e := r.GetEngine(engine)
+	if e == nil {
+		return fmt.Errorf("No Engine")
+	}
+	return e.Spectate(ctx, client)
+}
+
 func (r *EngineRouter) ClientEngines(ctx context.Context, client uuid.UUID) []*engine.Engine {
 	r.clientEnginesLock.Lock()
 	engines := r.clientEngines[client.ToFullString()]
@@ -97,7 +202,10 @@ func (r *EngineRouter) ClientEngines(ctx context.Context, client uuid.UUID) []*e
 		if err != nil {
 			continue
 		}
-		ret = append(ret, r.GetEngine(id))
+		// hibernated engines aren't in memory
+		if e := r.GetEngine(id); e != nil {
+			ret = append(ret, e)
+		}
 	}
 	return ret
 }
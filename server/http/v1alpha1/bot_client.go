@@ -0,0 +1,98 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	bot "github.com/mat285/boardgames/pkg/bot/v1alpha1"
+	connection "github.com/mat285/boardgames/pkg/connection/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+	core "github.com/mat285/boardgames/server/core/v1alpha1"
+)
+
+var (
+	_ connection.Client = new(BotClient)
+)
+
+// BotClient plays a seat on the server itself, packets from the engine go
+// straight to the bot and its moves go back through the router
+type BotClient struct {
+	ID       uuid.UUID
+	Username string
+	Engine   uuid.UUID
+	Router   *core.EngineRouter
+	Handler  connection.PacketHandler
+}
+
+// NewServerBot connects a bot that picks random moves to the router
+func NewServerBot(ctx context.Context, router *core.EngineRouter, username string, g game.Game) (*BotClient, error) {
+	c := &BotClient{
+		ID:       uuid.V4(),
+		Username: username,
+		Router:   router,
+	}
+	b := bot.NewBot(username, g, c, bot.NewRandom())
+	c.Handler = b.Handle
+	err := c.Connect(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *BotClient) GetID() uuid.UUID {
+	return c.ID
+}
+
+func (c *BotClient) GetUsername() string {
+	return c.Username
+}
+
+func (c *BotClient) Connect(ctx context.Context, _ connection.ConnectionInfo) error {
+	if c.Router == nil {
+		return fmt.Errorf("No Router")
+	}
+	return c.Router.ConnectClient(ctx, &core.Pipe{
+		ID:       c.ID,
+		Username: c.Username,
+		Receiver: c,
+	})
+}
+
+// Disconnect takes the bot off the router, it doesn't hear from its game
+// any more
+func (c *BotClient) Disconnect(ctx context.Context) {
+	c.Router.DisconnectClient(ctx, c.ID)
+}
+
+func (c *BotClient) Join(ctx context.Context, id uuid.UUID) error {
+	c.Engine = id
+	return c.Router.Join(ctx, c.ID, id)
+}
+
+func (c *BotClient) Listen(ctx context.Context, handler connection.PacketHandler) error {
+	c.Handler = handler
+	return nil
+}
+
+// Send is the bot talking to its game
+func (c *BotClient) Send(ctx context.Context, packet wire.Packet) error {
+	packet.Origin = c.ID
+	packet.Destination = c.Engine
+	return c.Router.Receive(ctx, packet)
+}
+
+// Receive is the game talking to the bot, the bot answers on its own time
+// so the engine isn't held up
+func (c *BotClient) Receive(ctx context.Context, packet wire.Packet) error {
+	if c.Handler == nil {
+		return nil
+	}
+	go func() {
+		logger.MaybeError(logger.GetLogger(ctx), c.Handler(ctx, packet))
+	}()
+	return nil
+}
@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+)
+
+// Spectate has the current user watch a public game without a seat
+func (s *Server) Spectate(r *web.Ctx) web.Result {
+	userID, username, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	e := s.engine(r.Context(), id)
+	if e == nil {
+		return web.JSON.NotFound()
+	}
+	e.Lock()
+	private := e.Private
+	e.Unlock()
+	if private {
+		return web.JSON.Forbidden()
+	}
+	if s.Router.GetClient(userID) == nil {
+		s.Router.ConnectClient(s.Ctx, NewWebsocket(userID, username, nil, s.InboundPackets))
+	}
+	err = s.Router.Spectate(s.Ctx, userID, id)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	return web.JSON.OK()
+}
+
+// filterChat holds players to the rate limit and stars out the filtered
+// words
+func (s *Server) filterChat(player uuid.UUID, text string) (string, error) {
+	cfg := s.Config.Chat
+	now := time.Now().UTC()
+	s.chatLock.Lock()
+	key := player.ToFullString()
+	var recent []time.Time
+	for _, sent := range s.chatSent[key] {
+		if now.Sub(sent) < cfg.RateWindowOrDefault() {
+			recent = append(recent, sent)
+		}
+	}
+	if len(recent) >= cfg.RateLimitOrDefault() {
+		s.chatSent[key] = recent
+		s.chatLock.Unlock()
+		return "", fmt.Errorf("Sending messages too fast, wait a moment")
+	}
+	s.chatSent[key] = append(recent, now)
+	s.chatLock.Unlock()
+
+	if s.chatWords == nil {
+		return text, nil
+	}
+	return s.chatWords.ReplaceAllStringFunc(text, func(word string) string {
+		return strings.Repeat("*", len(word))
+	}), nil
+}
+
+// wordFilter matches any of the words on its own in any case, nil when
+// there are none
+func wordFilter(words []string) *regexp.Regexp {
+	var quoted []string
+	for _, word := range words {
+		if word = strings.TrimSpace(word); len(word) > 0 {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+}
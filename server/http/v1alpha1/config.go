@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/blend/go-sdk/configutil"
 	"github.com/blend/go-sdk/logger"
@@ -11,8 +12,123 @@ import (
 )
 
 type Config struct {
-	Web web.Config `json:"web" yaml:"web"`
-	TLS TLS        `json:"tls" yaml:"tls"`
+	Web           web.Config    `json:"web" yaml:"web"`
+	TLS           TLS           `json:"tls" yaml:"tls"`
+	Matchmaking   Matchmaking   `json:"matchmaking" yaml:"matchmaking"`
+	Ratings       Ratings       `json:"ratings" yaml:"ratings"`
+	Chat          Chat          `json:"chat" yaml:"chat"`
+	Notifications Notifications `json:"notifications" yaml:"notifications"`
+}
+
+// Notifications sets up where notifications go besides the inbox, webhooks
+// are on for players that set one unless DisableWebhooks turns them off.
+// WebhookHosts limits the hosts webhooks post to when set
+type Notifications struct {
+	WebhookTimeout  time.Duration `json:"webhookTimeout" yaml:"webhookTimeout"`
+	DisableWebhooks bool          `json:"disableWebhooks" yaml:"disableWebhooks"`
+	WebhookHosts    []string      `json:"webhookHosts" yaml:"webhookHosts"`
+	// SMTP emails notifications when its address is set
+	SMTP SMTP `json:"smtp" yaml:"smtp"`
+}
+
+// SMTP is the mail server notifications are sent through, the username and
+// password are only used when set
+type SMTP struct {
+	Addr     string `json:"addr" yaml:"addr"`
+	From     string `json:"from" yaml:"from"`
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// Ratings picks which finished games change the players' ratings
+type Ratings struct {
+	// ExcludeCasual leaves out games created as casual
+	ExcludeCasual bool `json:"excludeCasual" yaml:"excludeCasual"`
+	// ExcludeBots leaves out games a bot played in
+	ExcludeBots bool `json:"excludeBots" yaml:"excludeBots"`
+	// Bots are the usernames of programs that log in to play, they count
+	// as bots like the ones the server adds
+	Bots []string `json:"bots" yaml:"bots"`
+}
+
+// IsBot returns if the username is listed as a bot
+func (r Ratings) IsBot(username string) bool {
+	for _, bot := range r.Bots {
+		if bot == username {
+			return true
+		}
+	}
+	return false
+}
+
+// Matchmaking tunes the queue, zero values use the defaults
+type Matchmaking struct {
+	// QueueTimeout is how long a player waits for a table before giving up
+	QueueTimeout time.Duration `json:"queueTimeout" yaml:"queueTimeout"`
+	// BotWait is how long a player waits before bots fill the empty seats,
+	// negative never adds bots
+	BotWait time.Duration `json:"botWait" yaml:"botWait"`
+	// ConfirmTimeout is how long a table waits on everyone to confirm
+	ConfirmTimeout time.Duration `json:"confirmTimeout" yaml:"confirmTimeout"`
+}
+
+// Chat moderates the table chat, zero values use the defaults
+type Chat struct {
+	// History is how many messages each game keeps
+	History int `json:"history" yaml:"history"`
+	// Spectators lets spectators read and talk in the chat
+	Spectators bool `json:"spectators" yaml:"spectators"`
+	// RateLimit is how many messages a player can send within RateWindow
+	RateLimit  int           `json:"rateLimit" yaml:"rateLimit"`
+	RateWindow time.Duration `json:"rateWindow" yaml:"rateWindow"`
+	// Filter lists words that are starred out, in any case
+	Filter []string `json:"filter" yaml:"filter"`
+}
+
+const (
+	DefaultChatRateLimit  = 5
+	DefaultChatRateWindow = 10 * time.Second
+)
+
+func (c Chat) RateLimitOrDefault() int {
+	if c.RateLimit > 0 {
+		return c.RateLimit
+	}
+	return DefaultChatRateLimit
+}
+
+func (c Chat) RateWindowOrDefault() time.Duration {
+	if c.RateWindow > 0 {
+		return c.RateWindow
+	}
+	return DefaultChatRateWindow
+}
+
+const (
+	DefaultQueueTimeout   = 10 * time.Minute
+	DefaultBotWait        = 2 * time.Minute
+	DefaultConfirmTimeout = 30 * time.Second
+)
+
+func (m Matchmaking) QueueTimeoutOrDefault() time.Duration {
+	if m.QueueTimeout > 0 {
+		return m.QueueTimeout
+	}
+	return DefaultQueueTimeout
+}
+
+func (m Matchmaking) BotWaitOrDefault() time.Duration {
+	if m.BotWait != 0 {
+		return m.BotWait
+	}
+	return DefaultBotWait
+}
+
+func (m Matchmaking) ConfirmTimeoutOrDefault() time.Duration {
+	if m.ConfirmTimeout > 0 {
+		return m.ConfirmTimeout
+	}
+	return DefaultConfirmTimeout
 }
 
 // Resolve populates configuration fields from a variety of input sources
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/blend/go-sdk/uuid"
 	"github.com/blend/go-sdk/web"
@@ -15,6 +17,20 @@ import (
 	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
 	websockets "github.com/mat285/boardgames/pkg/websockets"
 	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+const (
+	// QueryKeySeed fixes the deal of a new casual game, the same seed and
+	// seating always deal the same game. The host knows the deal so the
+	// game is never rated
+	QueryKeySeed = "seed"
+	// QueryKeyTeam names the team to put the current user on in the lobby
+	QueryKeyTeam = "team"
+	// QueryKeyFormat and QueryKeyTarget set up a series, e.g. best-of 3 or
+	// first-to 50 points
+	QueryKeyFormat = "format"
+	QueryKeyTarget = "target"
 )
 
 func (s *Server) Register(app *web.App) {
@@ -28,9 +44,45 @@ func (s *Server) Register(app *web.App) {
 
 	app.POST("/api/v1alpha1/games/:name/new", s.NewGame)
 	app.POST("/api/v1alpha1/game/:id/join", s.JoinGame)
+	app.POST("/api/v1alpha1/game/:id/team", s.SetTeam)
 	app.POST("/api/v1alpha1/game/:id/start", s.StartGame)
 	app.GET("/api/v1alpha1/game/:id/state", s.GetGameState)
+	app.GET("/api/v1alpha1/game/:id/record", s.GetGameRecord)
 	app.POST("/api/v1alpha1/game/:id/packet", s.SendPacket)
+	app.POST("/api/v1alpha1/game/:id/rematch", s.Rematch)
+	app.POST("/api/v1alpha1/game/:id/series", s.NewSeries)
+	app.GET("/api/v1alpha1/series/:id", s.GetSeries)
+	app.GET("/api/v1alpha1/lobby", s.ListLobby)
+	app.GET("/api/v1alpha1/game/:id/invite", s.GetInvite)
+	app.POST("/api/v1alpha1/game/:id/invite", s.RotateInvite)
+	app.DELETE("/api/v1alpha1/game/:id/invite", s.DisableInvite)
+	app.POST("/api/v1alpha1/game/:id/password", s.SetPassword)
+	app.POST("/api/v1alpha1/join/:code", s.JoinByCode)
+	app.GET("/api/v1alpha1/matchmaking", s.GetTicket)
+	app.DELETE("/api/v1alpha1/matchmaking", s.LeaveQueue)
+	app.POST("/api/v1alpha1/matchmaking/:name", s.Queue)
+	app.POST("/api/v1alpha1/match/:id/confirm", s.ConfirmMatch)
+	app.POST("/api/v1alpha1/tournaments/:name/new", s.NewTournament)
+	app.GET("/api/v1alpha1/tournament/:id", s.GetTournament)
+	app.POST("/api/v1alpha1/tournament/:id/start", s.StartTournament)
+	app.GET("/api/v1alpha1/leaderboard/:game", s.Leaderboard)
+	app.GET("/api/v1alpha1/ratings/:username", s.GetRatings)
+	app.GET("/api/v1alpha1/users/:name/profile", s.GetProfile)
+	app.POST("/api/v1alpha1/game/:id/spectate", s.Spectate)
+
+	app.GET("/api/v1alpha1/notifications", s.GetNotifications)
+	app.POST("/api/v1alpha1/notifications/read", s.ReadNotifications)
+	app.GET("/api/v1alpha1/notifications/preferences", s.GetNotificationPreferences)
+	app.PUT("/api/v1alpha1/notifications/preferences", s.SetNotificationPreferences)
+	app.POST("/api/v1alpha1/notifications/preferences/confirm", s.ConfirmEmail)
+
+	app.GET("/api/v1alpha1/friends", s.GetFriends)
+	app.POST("/api/v1alpha1/friends/:username", s.AddFriend)
+	app.DELETE("/api/v1alpha1/friends/:username", s.RemoveFriend)
+	app.POST("/api/v1alpha1/game/:id/invitations", s.InviteFriend)
+	app.GET("/api/v1alpha1/invitations", s.GetInvitations)
+	app.POST("/api/v1alpha1/invitations/:id/accept", s.AcceptInvitation)
+	app.POST("/api/v1alpha1/invitations/:id/decline", s.DeclineInvitation)
 
 	app.RouteTree.Handle("GET", "/api/v1alpha1/websockets/:name", s.OpenWebSocketsConnection)
 
@@ -59,6 +111,15 @@ func (s *Server) Login(r *web.Ctx) web.Result {
 	}
 	id := s.GetOrSetUserID(p.Username)
 	p.ID = id
+	// only the config says who is a bot, a player taken off the list is
+	// unmarked the next time they log in
+	mark := s.unmarkBot
+	if s.Config.Ratings.IsBot(p.Username) {
+		mark = s.markBot
+	}
+	if err := mark(r.Context(), id); err != nil {
+		return web.JSON.InternalError(err)
+	}
 	data, err := json.Marshal(p)
 	if err != nil {
 		return web.JSON.BadRequest(err)
@@ -96,10 +157,52 @@ func (s *Server) NewGame(r *web.Ctx) web.Result {
 	if err != nil {
 		return web.JSON.BadRequest(err)
 	}
+	var seed *int64
+	if _, err := r.QueryValue(QueryKeySeed); err == nil {
+		parsed, err := web.Int64Value(r.QueryValue(QueryKeySeed))
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+		seed = &parsed
+	}
+	private := false
+	if _, err := r.QueryValue(QueryKeyPrivate); err == nil {
+		private, err = web.BoolValue(r.QueryValue(QueryKeyPrivate))
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+	}
+	casual := false
+	if _, err := r.QueryValue(QueryKeyCasual); err == nil {
+		casual, err = web.BoolValue(r.QueryValue(QueryKeyCasual))
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+	}
+	if seed != nil && !casual {
+		return web.JSON.BadRequest(fmt.Errorf("A seed can only be picked for a casual game"))
+	}
+	var deadline time.Duration
+	if _, err := r.QueryValue(QueryKeyDeadline); err == nil {
+		deadline, err = web.DurationValue(r.QueryValue(QueryKeyDeadline))
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+		if deadline <= 0 {
+			return web.JSON.BadRequest(fmt.Errorf("Move deadline has to be positive"))
+		}
+	}
 	e, err := s.Router.NewEngine(s.Ctx, g, nil)
 	if err != nil {
 		return web.JSON.InternalError(err)
 	}
+	if seed != nil {
+		e.Seed = *seed
+		e.Seeded = true
+	}
+	e.Private = private
+	e.Casual = casual
+	e.MoveDeadline = deadline
 	e = s.Router.GetEngine(e.ID)
 	if e == nil {
 		return web.JSON.NotFound()
@@ -111,6 +214,12 @@ func (s *Server) NewGame(r *web.Ctx) web.Result {
 	if err != nil {
 		return web.JSON.InternalError(err)
 	}
+	if _, err := s.newInvite(e.ID); err != nil {
+		return web.JSON.InternalError(err)
+	}
+	s.publishLobby(s.Ctx, api.LobbyEventCreated, e)
+	e.Observe(s.observeLobby)
+	s.track(e)
 	return web.JSON.Result(e.ID)
 }
 
@@ -132,46 +241,163 @@ func (s *Server) OpenWebSocketsConnection(w http.ResponseWriter, r *http.Request
 	}
 	client := NewWebsocket(userID, username, conn, s.InboundPackets)
 	s.Router.ConnectClient(s.Ctx, client)
+	absent := s.Router.Present(userID)
 	client.Open(s.Ctx)
+	absent()
+	// the closed socket can't be sent to anymore
+	if multi := s.Router.GetClient(userID); multi != nil {
+		multi.Delete(s.Ctx, client)
+	}
 }
 
 type UserGame struct {
 	ID   uuid.UUID
 	Game string
+	// Waiting is set when the game waits on the user's move, Deadline is
+	// when it is due in a correspondence game
+	Waiting  bool
+	Deadline *time.Time `json:",omitempty"`
+	// Standings is only set once the game is over
+	Standings     []game.Standing
+	TeamStandings []game.TeamStanding
 }
 
+// ListUserGames lists the user's games, the ones waiting on them first and
+// then the ones still going, each soonest deadline first
 func (s *Server) ListUserGames(r *web.Ctx) web.Result {
 	userID, _, err := s.CurrentUser(r)
 	if err != nil {
 		return web.JSON.BadRequest(err)
 	}
 	engines := s.Router.ClientEngines(r.Context(), userID)
-	res := make([]UserGame, len(engines))
-	for i, e := range engines {
-		res[i] = UserGame{ID: e.ID, Game: e.Game.Name()}
+	res := make([]UserGame, 0, len(engines))
+	listed := make(map[string]bool)
+	for _, e := range engines {
+		e.Lock()
+		standings, err := e.Standings()
+		var teams []game.TeamStanding
+		if err == nil {
+			teams, err = e.TeamStandings()
+		}
+		waiting := false
+		if err == nil && e.State.Data != nil && !e.State.Data.IsDone() {
+			var acting []uuid.UUID
+			acting, err = game.ActingPlayers(e.State.Data)
+			waiting = hasPlayer(acting, userID)
+		}
+		deadline := e.Deadline()
+		e.Unlock()
+		if err != nil {
+			return web.JSON.InternalError(err)
+		}
+		ug := UserGame{ID: e.ID, Game: e.Game.Name(), Waiting: waiting, Standings: standings, TeamStandings: teams}
+		if !deadline.IsZero() && standings == nil {
+			ug.Deadline = &deadline
+		}
+		res = append(res, ug)
+		listed[e.ID.ToFullString()] = true
 	}
+	// hibernated games aren't in memory, the schedule has what to list
+	for _, entry := range s.schedule.Entries() {
+		if listed[entry.Game.ToFullString()] || !entry.HasPlayer(userID) {
+			continue
+		}
+		deadline := entry.Deadline
+		res = append(res, UserGame{ID: entry.Game, Game: entry.Name, Waiting: entry.IsWaitingOn(userID), Deadline: &deadline})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Waiting != b.Waiting {
+			return a.Waiting
+		}
+		if (a.Standings == nil) != (b.Standings == nil) {
+			return a.Standings == nil
+		}
+		if (a.Deadline == nil) != (b.Deadline == nil) {
+			return a.Deadline != nil
+		}
+		return a.Deadline != nil && a.Deadline.Before(*b.Deadline)
+	})
 	return web.JSON.Result(res)
 }
 
 func (s *Server) JoinGame(r *web.Ctx) web.Result {
-	userID, username, err := s.CurrentUser(r)
+	id, err := web.UUIDValue(r.Param("id"))
 	if err != nil {
 		return web.JSON.BadRequest(err)
 	}
-	id, err := web.UUIDValue(r.Param("id"))
+	res := s.join(r, id)
+	if res != nil {
+		return res
+	}
+	return web.JSON.OK()
+}
+
+// join seats the current user, a table with a password wants it in the
+// body unless the user already sits there. Private tables only take the
+// players invited to them, by id or by code
+func (s *Server) join(r *web.Ctx, id uuid.UUID) web.Result {
+	userID, username, err := s.CurrentUser(r)
 	if err != nil {
 		return web.JSON.BadRequest(err)
 	}
-	e := s.Router.GetEngine(id)
+	e := s.engine(r.Context(), id)
 	if e == nil {
 		return web.JSON.NotFound()
 	}
+	e.Lock()
+	seated := e.GetPlayer(userID) != nil
+	private := e.Private
+	e.Unlock()
+	if !seated {
+		if !s.invitations.CanJoin(id, userID, private) {
+			return web.JSON.Forbidden()
+		}
+		password, err := joinPassword(r)
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+		if err := s.checkPassword(id, password); err != nil {
+			return web.JSON.Forbidden()
+		}
+	}
+	err = s.seat(userID, username, id)
+	if err != nil {
+		return web.JSON.InternalError(err)
+	}
+	return nil
+}
+
+// seat joins the user to the game, connecting a client for them first if
+// they have none
+func (s *Server) seat(userID uuid.UUID, username string, id uuid.UUID) error {
 	if s.Router.GetClient(userID) == nil {
 		s.Router.ConnectClient(s.Ctx, NewWebsocket(userID, username, nil, s.InboundPackets))
 	}
-	err = s.Router.Join(s.Ctx, userID, id)
+	return s.Router.Join(s.Ctx, userID, id)
+}
+
+// SetTeam puts the current user on a team before the game starts
+func (s *Server) SetTeam(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
 	if err != nil {
-		return web.JSON.InternalError(err)
+		return web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	team, err := r.QueryValue(QueryKeyTeam)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	e := s.Router.GetEngine(id)
+	if e == nil {
+		return web.JSON.NotFound()
+	}
+	err = e.AssignTeam(userID, team)
+	if err != nil {
+		return web.JSON.BadRequest(err)
 	}
 	return web.JSON.OK()
 }
@@ -199,18 +425,48 @@ func (s *Server) GetGameState(r *web.Ctx) web.Result {
 	if err != nil {
 		return web.JSON.BadRequest(err)
 	}
-	e := s.Router.GetEngine(id)
+	e := s.engine(r.Context(), id)
+	if e == nil {
+		return web.JSON.NotFound()
+	}
+	// anyone not logged in gets the spectators' view
+	userID, _, _ := s.CurrentUser(r)
+	return s.stateResponse(e, userID)
+}
+
+// GetGameRecord returns the full record of a finished game, it holds the
+// reveal so it is never handed out while the game is still going
+func (s *Server) GetGameRecord(r *web.Ctx) web.Result {
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	e := s.engine(r.Context(), id)
 	if e == nil {
 		return web.JSON.NotFound()
 	}
-	return s.stateResponse(e)
+	e.Lock()
+	defer e.Unlock()
+	data, err := e.GetStateData()
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	if !data.IsDone() {
+		return web.JSON.BadRequest(fmt.Errorf("Game is not over"))
+	}
+	record, err := e.Record()
+	if err != nil {
+		return web.JSON.InternalError(err)
+	}
+	return web.JSON.Result(record)
 }
 
-func (s *Server) stateResponse(e *engine.Engine) web.Result {
+// stateResponse sends the state as the player sees it
+func (s *Server) stateResponse(e *engine.Engine, player uuid.UUID) web.Result {
 	payload := []byte{}
 	data, err := e.GetStateData()
 	if err == nil {
-		obj, err := e.MessageProvider.SerializeState(data)
+		obj, err := e.MessageProvider.SerializeState(game.View(data, player))
 		if err != nil {
 			return web.JSON.InternalError(err)
 		}
@@ -231,7 +487,7 @@ func (s *Server) SendPacket(r *web.Ctx) web.Result {
 		return web.JSON.BadRequest(err)
 	}
 
-	e := s.Router.GetEngine(id)
+	e := s.engine(r.Context(), id)
 	if e == nil {
 		return web.JSON.NotFound()
 	}
@@ -249,5 +505,5 @@ func (s *Server) SendPacket(r *web.Ctx) web.Result {
 	if err != nil {
 		return web.JSON.BadRequest(err)
 	}
-	return s.stateResponse(e)
+	return s.stateResponse(e, userID)
 }
@@ -0,0 +1,132 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/games"
+	correspondence "github.com/mat285/boardgames/pkg/correspondence/v1alpha1"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+)
+
+const (
+	// QueryKeyDeadline makes a correspondence game, each move can take up
+	// to the duration, e.g. 72h
+	QueryKeyDeadline = "deadline"
+)
+
+// observeCorrespondence saves a correspondence game and drops it from
+// memory each time it waits on its players, the schedule keeps its deadline
+// and enough to list it
+func (s *Server) observeCorrespondence(ctx context.Context, e *engine.Engine, event engine.Event) {
+	if !e.IsCorrespondence() {
+		return
+	}
+	log := logger.GetLogger(ctx)
+	switch event.Type {
+	case engine.EventTypeTurn:
+		waiting, _ := event.Body.([]uuid.UUID)
+		e.Lock()
+		entry := correspondence.Entry{
+			Game:     e.ID,
+			Name:     e.Game.Name(),
+			Players:  e.PlayerIDs(),
+			Waiting:  waiting,
+			Deadline: e.Deadline(),
+		}
+		err := e.Save(ctx)
+		e.Unlock()
+		if err != nil {
+			// it stays in memory rather than losing the move
+			logger.MaybeError(log, err)
+			return
+		}
+		if err := s.schedule.Set(ctx, entry); err != nil {
+			logger.MaybeError(log, err)
+			return
+		}
+		s.Router.Hibernate(ctx, e.ID)
+		s.dropInvite(e.ID)
+	case engine.EventTypeOver:
+		_, err := s.schedule.Remove(ctx, e.ID)
+		logger.MaybeError(log, err)
+	}
+}
+
+// expireMove resigns the players that let the deadline pass, the game goes
+// on without them or ends. It stays on the schedule to be tried again if
+// the game can't be loaded or a resignation fails
+func (s *Server) expireMove(ctx context.Context, entry correspondence.Entry) error {
+	e, err := s.wake(ctx, entry.Game, entry.Name)
+	if err != nil {
+		logger.MaybeError(logger.GetLogger(ctx), err)
+		return err
+	}
+	for _, pid := range entry.Waiting {
+		packet, err := e.MessageProvider.MessageResign(pid)
+		if err != nil {
+			logger.MaybeError(logger.GetLogger(ctx), err)
+			return err
+		}
+		packet.Origin = pid
+		packet.Destination = e.ID
+		if err := e.RecieveSync(ctx, *packet); err != nil {
+			logger.MaybeError(logger.GetLogger(ctx), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// engine returns the game, loading it back if it is a hibernated
+// correspondence game
+func (s *Server) engine(ctx context.Context, id uuid.UUID) *engine.Engine {
+	if e := s.Router.GetEngine(id); e != nil {
+		return e
+	}
+	entry, has := s.schedule.Get(id)
+	if !has {
+		return nil
+	}
+	e, err := s.wake(ctx, id, entry.Name)
+	if err != nil {
+		logger.MaybeError(logger.GetLogger(ctx), err)
+		return nil
+	}
+	return e
+}
+
+// wake loads a hibernated game back into the router, only once however
+// many packets for it come in together
+func (s *Server) wake(ctx context.Context, id uuid.UUID, name string) (*engine.Engine, error) {
+	s.wakeLock.Lock()
+	defer s.wakeLock.Unlock()
+	if e := s.Router.GetEngine(id); e != nil {
+		return e, nil
+	}
+	rg, has := games.RegisteredGames()[name]
+	if !has {
+		return nil, fmt.Errorf("Unknown game %s", name)
+	}
+	g, err := rg.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.Router.Wake(ctx, g, s.Store, id)
+	if err != nil {
+		return nil, err
+	}
+	s.track(e)
+	return e, nil
+}
+
+func hasPlayer(players []uuid.UUID, player uuid.UUID) bool {
+	for _, id := range players {
+		if id.Equal(player) {
+			return true
+		}
+	}
+	return false
+}
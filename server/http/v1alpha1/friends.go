@@ -0,0 +1,194 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	model "github.com/mat285/boardgames/pkg/model/v1alpha1"
+	notify "github.com/mat285/boardgames/pkg/notify/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+// GetFriends lists the current user's friends with who is online, and the
+// friend requests either way
+func (s *Server) GetFriends(r *web.Ctx) web.Result {
+	_, username, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	usernames, incoming, outgoing := s.friends.List(username)
+	res := api.Friends{Friends: []api.Friend{}, Incoming: incoming, Outgoing: outgoing}
+	for _, friend := range usernames {
+		id := s.userID(friend)
+		res.Friends = append(res.Friends, api.Friend{Player: id, Username: friend, Online: s.Router.Online(id)})
+	}
+	return web.JSON.Result(res)
+}
+
+// AddFriend sends the user a friend request, or accepts theirs
+func (s *Server) AddFriend(r *web.Ctx) web.Result {
+	_, username, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	friend, _ := r.Param("username")
+	if s.userID(friend) == nil {
+		return web.JSON.NotFound()
+	}
+	if friend == username {
+		return web.JSON.BadRequest(fmt.Errorf("Cannot add yourself as a friend"))
+	}
+	s.friends.Add(username, friend)
+	return web.JSON.OK()
+}
+
+// RemoveFriend unfriends the user, it also drops a request either way
+func (s *Server) RemoveFriend(r *web.Ctx) web.Result {
+	_, username, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	friend, _ := r.Param("username")
+	s.friends.Remove(username, friend)
+	return web.JSON.OK()
+}
+
+// InviteFriend asks a friend to take a seat at the game, only its players
+// can invite and only before it starts
+func (s *Server) InviteFriend(r *web.Ctx) web.Result {
+	userID, username, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	var req api.InviteFriendRequest
+	if err := r.PostBodyAsJSON(&req); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	if !s.friends.AreFriends(username, req.Username) {
+		return web.JSON.BadRequest(fmt.Errorf("%s is not your friend", req.Username))
+	}
+	friendID := s.userID(req.Username)
+	e := s.engine(r.Context(), id)
+	if e == nil {
+		return web.JSON.NotFound()
+	}
+	e.Lock()
+	seated := e.GetPlayer(userID) != nil
+	joined := e.GetPlayer(friendID) != nil
+	open := e.Status() == model.GameStatusOpen
+	name := e.Game.Name()
+	e.Unlock()
+	if !seated {
+		return web.JSON.Forbidden()
+	}
+	if !open {
+		return web.JSON.BadRequest(fmt.Errorf("Game Already Started"))
+	}
+	if joined {
+		return web.JSON.BadRequest(fmt.Errorf("%s already joined", req.Username))
+	}
+
+	res, created := s.invitations.Invite(api.Invitation{
+		Game:         id,
+		GameName:     name,
+		From:         userID,
+		FromUsername: username,
+		To:           friendID,
+	})
+	if !created {
+		return web.JSON.Result(res)
+	}
+
+	s.notify(r.Context(), []notice{{player: friendID, t: api.PacketTypeInvitation, body: res}})
+	s.sendNotification(notify.Notification{
+		Player: friendID,
+		Type:   notify.TypeInvite,
+		Game:   id,
+		Title:  fmt.Sprintf("%s invited you to a game of %s", username, name),
+	})
+	return web.JSON.Result(res)
+}
+
+// GetInvitations lists the invitations the current user hasn't answered,
+// newest first
+func (s *Server) GetInvitations(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	return web.JSON.Result(s.invitations.Pending(userID))
+}
+
+// AcceptInvitation seats the current user at the game they were invited
+// to, a password on the table doesn't apply to them
+func (s *Server) AcceptInvitation(r *web.Ctx) web.Result {
+	userID, username, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	inv, res := s.invitation(r, userID)
+	if res != nil {
+		return res
+	}
+	if s.engine(r.Context(), inv.Game) == nil {
+		return web.JSON.NotFound()
+	}
+	if err := s.seat(userID, username, inv.Game); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	return s.answerInvitation(r, inv.ID, api.InvitationAccepted)
+}
+
+// DeclineInvitation turns the invitation down, the player who sent it is
+// told
+func (s *Server) DeclineInvitation(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	inv, res := s.invitation(r, userID)
+	if res != nil {
+		return res
+	}
+	return s.answerInvitation(r, inv.ID, api.InvitationDeclined)
+}
+
+// invitation returns the pending invitation in the route if it was sent to
+// the player
+func (s *Server) invitation(r *web.Ctx, player uuid.UUID) (*api.Invitation, web.Result) {
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return nil, web.JSON.BadRequest(err)
+	}
+	inv, has := s.invitations.Get(id, player)
+	if !has {
+		return nil, web.JSON.NotFound()
+	}
+	if inv.Status != api.InvitationPending {
+		return nil, web.JSON.BadRequest(fmt.Errorf("Invitation already %s", inv.Status))
+	}
+	return &inv, nil
+}
+
+// answerInvitation sets the status and sends the invitation back to the
+// player who sent it
+func (s *Server) answerInvitation(r *web.Ctx, id uuid.UUID, status api.InvitationStatus) web.Result {
+	res, err := s.invitations.Answer(id, status)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.notify(r.Context(), []notice{{player: res.From, t: api.PacketTypeInvitation, body: res}})
+	return web.JSON.Result(res)
+}
+
+// userID returns the id of the user, nil for one that never logged in
+func (s *Server) userID(username string) uuid.UUID {
+	s.usersLock.Lock()
+	defer s.usersLock.Unlock()
+	return s.Users[username]
+}
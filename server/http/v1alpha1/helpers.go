@@ -6,21 +6,37 @@ import (
 	model "github.com/mat285/boardgames/pkg/model/v1alpha1"
 )
 
+// GameFromEngine describes the game for the lobby, the engine lock has to
+// be held
 func GameFromEngine(e *engine.Engine) *model.Game {
 	if e == nil {
 		return nil
 	}
-	return &model.Game{
-		ID:      e.ID,
-		Game:    e.Game.Name(),
-		Players: PlayersFromPlayers(e.State.Players),
+	players := PlayersFromPlayers(e.GamePlayers())
+	_, max := v1alpha1.GameSeats(e.Game)
+	g := &model.Game{
+		ID:         e.ID,
+		Game:       e.Game.Name(),
+		Variant:    v1alpha1.GameVariant(e.Game),
+		Players:    players,
+		MaxPlayers: max,
+		Status:     e.Status(),
+		Created:    e.Created,
 	}
+	if len(players) > 0 {
+		g.Host = players[0]
+	}
+	if max > 0 && g.Status == model.GameStatusOpen {
+		g.OpenSeats = max - len(players)
+	}
+	return g
 }
 
 func PlayersFromPlayers(players []v1alpha1.Player) []model.Player {
 	ret := make([]model.Player, len(players))
 	for i := range players {
 		ret[i] = model.Player{
+			ID:       players[i].ID,
 			Username: players[i].Username,
 		}
 	}
@@ -0,0 +1,259 @@
+package v1alpha1
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+// inviteAlphabet leaves out letters and digits that read alike, like 0 and
+// O or 1 and I
+const inviteAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// invite is how a game is shared, the code is empty once the host turns it
+// off and the password is salted and hashed
+type invite struct {
+	Game     uuid.UUID
+	Code     string
+	salt     []byte
+	password []byte
+}
+
+// NewInviteCode makes a short code to join a game by, e.g. K7QF-2P
+func NewInviteCode() (string, error) {
+	var code strings.Builder
+	for i := 0; i < 6; i++ {
+		if i == 4 {
+			code.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(inviteAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(inviteAlphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
+
+// normalizeInviteCode lets players type the code in any case and with or
+// without the dash
+func normalizeInviteCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// newInvite hands the game a fresh code, it replaces the old one
+func (s *Server) newInvite(id uuid.UUID) (*invite, error) {
+	s.invitesLock.Lock()
+	defer s.invitesLock.Unlock()
+	inv, has := s.invites[id.ToFullString()]
+	if !has {
+		inv = &invite{Game: id}
+		s.invites[id.ToFullString()] = inv
+	}
+	for {
+		code, err := NewInviteCode()
+		if err != nil {
+			return nil, err
+		}
+		if s.inviteByCode(code) == nil {
+			inv.Code = code
+			return inv, nil
+		}
+	}
+}
+
+// dropInvite forgets the code and password of a game that left memory, a
+// game only takes players before it starts so there is nothing to keep
+func (s *Server) dropInvite(id uuid.UUID) {
+	s.invitesLock.Lock()
+	defer s.invitesLock.Unlock()
+	delete(s.invites, id.ToFullString())
+}
+
+// inviteByCode finds the game the code is for, the invites lock has to be
+// held
+func (s *Server) inviteByCode(code string) *invite {
+	code = normalizeInviteCode(code)
+	if len(code) == 0 {
+		return nil
+	}
+	for _, inv := range s.invites {
+		if normalizeInviteCode(inv.Code) == code {
+			return inv
+		}
+	}
+	return nil
+}
+
+// checkPassword makes sure the player knows the password of the table, if
+// it has one
+func (s *Server) checkPassword(id uuid.UUID, password string) error {
+	s.invitesLock.Lock()
+	defer s.invitesLock.Unlock()
+	inv, has := s.invites[id.ToFullString()]
+	if !has || inv.password == nil {
+		return nil
+	}
+	if subtle.ConstantTimeCompare(hashPassword(inv.salt, password), inv.password) != 1 {
+		return fmt.Errorf("Wrong password")
+	}
+	return nil
+}
+
+func hashPassword(salt []byte, password string) []byte {
+	sum := sha256.Sum256(append(append([]byte{}, salt...), password...))
+	return sum[:]
+}
+
+func (s *Server) inviteResponse(id uuid.UUID) api.Invite {
+	s.invitesLock.Lock()
+	defer s.invitesLock.Unlock()
+	res := api.Invite{Game: id}
+	if inv, has := s.invites[id.ToFullString()]; has {
+		res.Code = inv.Code
+		res.HasPassword = inv.password != nil
+	}
+	return res
+}
+
+// hostEngine returns the engine of the request if the current user is its
+// host
+func (s *Server) hostEngine(r *web.Ctx) (*engine.Engine, web.Result) {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return nil, web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return nil, web.JSON.BadRequest(err)
+	}
+	e := s.Router.GetEngine(id)
+	if e == nil {
+		return nil, web.JSON.NotFound()
+	}
+	seats := e.PlayerIDs()
+	if len(seats) == 0 || !seats[0].Equal(userID) {
+		return nil, web.JSON.Forbidden()
+	}
+	return e, nil
+}
+
+// GetInvite returns the code of the game for its players to share
+func (s *Server) GetInvite(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	e := s.Router.GetEngine(id)
+	if e == nil {
+		return web.JSON.NotFound()
+	}
+	if e.GetPlayer(userID) == nil {
+		return web.JSON.Forbidden()
+	}
+	return web.JSON.Result(s.inviteResponse(id))
+}
+
+// RotateInvite gives the game a new code, the old one stops working. It
+// also turns a disabled code back on
+func (s *Server) RotateInvite(r *web.Ctx) web.Result {
+	e, res := s.hostEngine(r)
+	if res != nil {
+		return res
+	}
+	if _, err := s.newInvite(e.ID); err != nil {
+		return web.JSON.InternalError(err)
+	}
+	return web.JSON.Result(s.inviteResponse(e.ID))
+}
+
+// DisableInvite turns the code off, the game can still be joined by id
+func (s *Server) DisableInvite(r *web.Ctx) web.Result {
+	e, res := s.hostEngine(r)
+	if res != nil {
+		return res
+	}
+	s.invitesLock.Lock()
+	if inv, has := s.invites[e.ID.ToFullString()]; has {
+		inv.Code = ""
+	}
+	s.invitesLock.Unlock()
+	return web.JSON.Result(s.inviteResponse(e.ID))
+}
+
+// SetPassword locks the table behind a password, an empty one takes it off
+// again
+func (s *Server) SetPassword(r *web.Ctx) web.Result {
+	e, res := s.hostEngine(r)
+	if res != nil {
+		return res
+	}
+	var body api.JoinRequest
+	if err := r.PostBodyAsJSON(&body); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return web.JSON.InternalError(err)
+	}
+
+	s.invitesLock.Lock()
+	inv, has := s.invites[e.ID.ToFullString()]
+	if !has {
+		inv = &invite{Game: e.ID}
+		s.invites[e.ID.ToFullString()] = inv
+	}
+	inv.salt, inv.password = nil, nil
+	if len(body.Password) > 0 {
+		inv.salt, inv.password = salt, hashPassword(salt, body.Password)
+	}
+	s.invitesLock.Unlock()
+	return web.JSON.Result(s.inviteResponse(e.ID))
+}
+
+// JoinByCode joins the game the code is for, a private game still only
+// takes the players invited to it
+func (s *Server) JoinByCode(r *web.Ctx) web.Result {
+	code, _ := r.Param("code")
+	s.invitesLock.Lock()
+	inv := s.inviteByCode(code)
+	var id uuid.UUID
+	if inv != nil {
+		id = inv.Game
+	}
+	s.invitesLock.Unlock()
+	if id == nil {
+		return web.JSON.NotFound()
+	}
+	res := s.join(r, id)
+	if res != nil {
+		return res
+	}
+	return web.JSON.Result(id)
+}
+
+// joinPassword reads the password a join request was sent with, joining
+// without a body is joining without one
+func joinPassword(r *web.Ctx) (string, error) {
+	body, err := r.PostBody()
+	if err != nil || len(body) == 0 {
+		return "", err
+	}
+	var req api.JoinRequest
+	err = json.Unmarshal(body, &req)
+	return req.Password, err
+}
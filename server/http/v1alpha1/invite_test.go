@@ -0,0 +1,119 @@
+package v1alpha1_test
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+	server "github.com/mat285/boardgames/server/http/v1alpha1"
+)
+
+func getInvite(it *assert.Assertions, s *server.Server, u *user, id uuid.UUID) api.Invite {
+	var res api.Invite
+	decode(it, call(it, s.GetInvite, u, "/", nil, "id", id.String()), &res)
+	return res
+}
+
+// joinByCode joins as the user with the code and password, an empty
+// password sends no body
+func joinByCode(it *assert.Assertions, s *server.Server, u *user, code, password string) int {
+	var body interface{}
+	if len(password) > 0 {
+		body = api.JoinRequest{Password: password}
+	}
+	return call(it, s.JoinByCode, u, "/", body, "code", code).StatusCode
+}
+
+func TestNewInviteCode(t *testing.T) {
+	it := assert.New(t)
+	format := regexp.MustCompile(`^[2-9A-HJ-NP-Z]{4}-[2-9A-HJ-NP-Z]{2}$`)
+	for i := 0; i < 100; i++ {
+		code, err := server.NewInviteCode()
+		it.Nil(err)
+		it.True(format.MatchString(code), code)
+	}
+}
+
+func TestInvite(t *testing.T) {
+	it := assert.New(t)
+	s := testServer(t)
+	ann, bob, cat, dan := newUser(s, "ann"), newUser(s, "bob"), newUser(s, "cat"), newUser(s, "dan")
+	id := newGame(it, s, ann)
+
+	// only the players see the code and only the host changes it
+	code := getInvite(it, s, ann, id)
+	it.Equal(id, code.Game)
+	it.NotEmpty(code.Code)
+	it.False(code.HasPassword)
+	it.Equal(http.StatusForbidden, call(it, s.GetInvite, bob, "/", nil, "id", id.String()).StatusCode)
+	it.Equal(http.StatusNotFound, joinByCode(it, s, bob, "2222-22", ""))
+
+	// the code can be typed in any case, with or without the dash
+	typed := " " + strings.ToLower(strings.Replace(code.Code, "-", " ", 1)) + " "
+	var joined uuid.UUID
+	decode(it, call(it, s.JoinByCode, bob, "/", nil, "code", typed), &joined)
+	it.Equal(id, joined)
+	it.Equal(code, getInvite(it, s, bob, id))
+	it.Equal(http.StatusForbidden, call(it, s.RotateInvite, bob, "/", nil, "id", id.String()).StatusCode)
+	it.Equal(http.StatusForbidden, call(it, s.DisableInvite, bob, "/", nil, "id", id.String()).StatusCode)
+
+	// a new code turns the old one off
+	var rotated api.Invite
+	decode(it, call(it, s.RotateInvite, ann, "/", nil, "id", id.String()), &rotated)
+	it.NotEqual(code.Code, rotated.Code)
+	it.Equal(http.StatusNotFound, joinByCode(it, s, cat, code.Code, ""))
+
+	// a disabled code joins nobody, the id still does and rotating turns
+	// codes back on
+	var disabled api.Invite
+	decode(it, call(it, s.DisableInvite, ann, "/", nil, "id", id.String()), &disabled)
+	it.Empty(disabled.Code)
+	it.Equal(http.StatusNotFound, joinByCode(it, s, cat, rotated.Code, ""))
+	it.Equal(http.StatusNotFound, joinByCode(it, s, cat, "", ""))
+	decode(it, call(it, s.RotateInvite, ann, "/", nil, "id", id.String()), &rotated)
+	it.NotEmpty(rotated.Code)
+
+	// the password is checked against its salted hash and never handed out
+	it.Equal(http.StatusForbidden, call(it, s.SetPassword, bob, "/", api.JoinRequest{Password: "x"}, "id", id.String()).StatusCode)
+	var locked api.Invite
+	decode(it, call(it, s.SetPassword, ann, "/", api.JoinRequest{Password: "hunter2"}, "id", id.String()), &locked)
+	it.True(locked.HasPassword)
+	it.Equal(http.StatusForbidden, joinByCode(it, s, cat, rotated.Code, ""))
+	it.Equal(http.StatusForbidden, joinByCode(it, s, cat, rotated.Code, "hunter"))
+	it.Equal(http.StatusForbidden, call(it, s.JoinGame, cat, "/", api.JoinRequest{Password: "Hunter2"}, "id", id.String()).StatusCode)
+	it.Equal(http.StatusOK, joinByCode(it, s, cat, rotated.Code, "hunter2"))
+	// players already seated don't need it
+	it.Equal(http.StatusOK, joinByCode(it, s, bob, rotated.Code, ""))
+
+	var open api.Invite
+	decode(it, call(it, s.SetPassword, ann, "/", api.JoinRequest{}, "id", id.String()), &open)
+	it.False(open.HasPassword)
+	it.Equal(http.StatusOK, call(it, s.JoinGame, dan, "/", nil, "id", id.String()).StatusCode)
+
+	// a private table still only takes the players invited to it
+	private := newGame(it, s, ann, "private=true")
+	it.Equal(http.StatusForbidden, joinByCode(it, s, bob, getInvite(it, s, ann, private).Code, ""))
+}
+
+func TestInviteDropped(t *testing.T) {
+	it := assert.New(t)
+	s := testServer(t)
+	ann, bob, cat := newUser(s, "ann"), newUser(s, "bob"), newUser(s, "cat")
+	id := newGame(it, s, ann, "deadline=72h")
+	code := getInvite(it, s, ann, id).Code
+	it.Equal(http.StatusOK, joinByCode(it, s, bob, code, ""))
+
+	// a correspondence game leaves memory on its first turn and its code
+	// goes with it
+	it.Equal(http.StatusOK, call(it, s.StartGame, ann, "/", nil, "id", id.String()).StatusCode)
+	for i := 0; i < 100 && s.Router.GetEngine(id) != nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	it.Nil(s.Router.GetEngine(id))
+	it.Equal(http.StatusNotFound, joinByCode(it, s, cat, code, ""))
+}
@@ -0,0 +1,159 @@
+package v1alpha1
+
+import (
+	"context"
+	"sort"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	model "github.com/mat285/boardgames/pkg/model/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+const (
+	// QueryKeyGame, QueryKeyVariant, QueryKeyStatus and QueryKeyHost filter
+	// the lobby, the status defaults to open games
+	QueryKeyGame    = "game"
+	QueryKeyVariant = "variant"
+	QueryKeyStatus  = "status"
+	QueryKeyHost    = "host"
+	// QueryKeySeats only lists games with at least that many open seats
+	QueryKeySeats  = "seats"
+	QueryKeyOffset = "offset"
+	QueryKeyLimit  = "limit"
+	// QueryKeyPrivate keeps a new game out of the lobby
+	QueryKeyPrivate = "private"
+)
+
+const (
+	DefaultLobbyLimit = 20
+	MaxLobbyLimit     = 100
+)
+
+// ListLobby lists the public games that match the filters, newest first
+func (s *Server) ListLobby(r *web.Ctx) web.Result {
+	query, err := lobbyQuery(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	var matched []model.Game
+	for _, e := range s.Router.Engines() {
+		e.Lock()
+		private := e.Private
+		g := GameFromEngine(e)
+		e.Unlock()
+		if private || !query.Matches(*g) {
+			continue
+		}
+		matched = append(matched, *g)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].Created.Equal(matched[j].Created) {
+			return matched[i].Created.After(matched[j].Created)
+		}
+		return matched[i].ID.String() < matched[j].ID.String()
+	})
+
+	res := api.LobbyResponse{
+		Games:  []model.Game{},
+		Total:  len(matched),
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}
+	if query.Offset < len(matched) {
+		end := query.Offset + query.Limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		res.Games = matched[query.Offset:end]
+	}
+	return web.JSON.Result(res)
+}
+
+func lobbyQuery(r *web.Ctx) (api.LobbyQuery, error) {
+	query := api.LobbyQuery{
+		Status: model.GameStatusOpen,
+		Limit:  DefaultLobbyLimit,
+	}
+	query.Game, _ = r.QueryValue(QueryKeyGame)
+	query.Variant, _ = r.QueryValue(QueryKeyVariant)
+	query.Host, _ = r.QueryValue(QueryKeyHost)
+	if status, err := r.QueryValue(QueryKeyStatus); err == nil {
+		query.Status = model.GameStatus(status)
+	}
+	for key, value := range map[string]*int{
+		QueryKeySeats:  &query.Seats,
+		QueryKeyOffset: &query.Offset,
+		QueryKeyLimit:  &query.Limit,
+	} {
+		if _, err := r.QueryValue(key); err != nil {
+			continue
+		}
+		parsed, err := web.IntValue(r.QueryValue(key))
+		if err != nil {
+			return query, err
+		}
+		*value = parsed
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	if query.Limit <= 0 {
+		query.Limit = DefaultLobbyLimit
+	}
+	if query.Limit > MaxLobbyLimit {
+		query.Limit = MaxLobbyLimit
+	}
+	return query, nil
+}
+
+// subscribeLobby starts or stops pushing lobby events to the client
+func (s *Server) subscribeLobby(client uuid.UUID, subscribe bool) {
+	s.lobbyLock.Lock()
+	defer s.lobbyLock.Unlock()
+	if subscribe {
+		s.lobby[client.ToFullString()] = client
+		return
+	}
+	delete(s.lobby, client.ToFullString())
+}
+
+// observeLobby pushes the changes of a public game to everyone watching the
+// lobby
+func (s *Server) observeLobby(ctx context.Context, e *engine.Engine, event engine.Event) {
+	switch event.Type {
+	case engine.EventTypeJoined:
+		s.publishLobby(ctx, api.LobbyEventSeatFilled, e)
+	case engine.EventTypeStarted:
+		s.publishLobby(ctx, api.LobbyEventStarted, e)
+	}
+}
+
+func (s *Server) publishLobby(ctx context.Context, t api.LobbyEventType, e *engine.Engine) {
+	e.Lock()
+	private := e.Private
+	g := GameFromEngine(e)
+	e.Unlock()
+	if private {
+		return
+	}
+	s.lobbyLock.Lock()
+	subscribers := make([]uuid.UUID, 0, len(s.lobby))
+	for _, id := range s.lobby {
+		subscribers = append(subscribers, id)
+	}
+	s.lobbyLock.Unlock()
+
+	log := logger.GetLogger(ctx)
+	event := api.LobbyEvent{Type: t, Game: *g}
+	for _, id := range subscribers {
+		err := s.respondJSON(ctx, nil, id, api.PacketTypeLobbyEvent, event)
+		if err != nil {
+			// the client went away
+			logger.MaybeError(log, err)
+			s.subscribeLobby(id, false)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	model "github.com/mat285/boardgames/pkg/model/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+	server "github.com/mat285/boardgames/server/http/v1alpha1"
+)
+
+// listLobby returns the page of the lobby for the query
+func listLobby(it *assert.Assertions, s *server.Server, query string) api.LobbyResponse {
+	var res api.LobbyResponse
+	decode(it, call(it, s.ListLobby, nil, "/api/v1alpha1/lobby?"+query, nil), &res)
+	return res
+}
+
+func gameIDs(games []model.Game) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(games))
+	for _, g := range games {
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
+
+// lobbyEvents returns the lobby events the user was pushed
+func lobbyEvents(it *assert.Assertions, u *user) []api.LobbyEvent {
+	var events []api.LobbyEvent
+	for _, packet := range u.received(api.PacketTypeLobbyEvent) {
+		var event api.LobbyEvent
+		it.Nil(json.Unmarshal(packet.Payload, &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestLobby(t *testing.T) {
+	it := assert.New(t)
+	s := testServer(t)
+	ann, bob, cat, dan := newUser(s, "ann"), newUser(s, "bob"), newUser(s, "cat"), newUser(s, "dan")
+	eve := newUser(s, "eve")
+	subscribe := wire.NewPacket(wire.OptPacketType(api.PacketTypeLobbySubscribe), wire.OptPacketOrigin(eve.ID))
+	it.Nil(s.Receive(context.Background(), subscribe))
+
+	// the lobby lists newest first, the games are made apart so the order
+	// doesn't come down to their ids
+	first := newGame(it, s, ann)
+	time.Sleep(time.Millisecond)
+	private := newGame(it, s, bob, "private=true")
+	time.Sleep(time.Millisecond)
+	last := newGame(it, s, cat)
+	it.Equal(http.StatusOK, call(it, s.JoinGame, dan, "/", nil, "id", first.String()).StatusCode)
+
+	// watchers hear about the public games only
+	events := lobbyEvents(it, eve)
+	it.Len(events, 3)
+	it.Equal(api.LobbyEventCreated, events[0].Type)
+	it.Equal(first, events[0].Game.ID)
+	it.Equal(api.LobbyEventCreated, events[1].Type)
+	it.Equal(last, events[1].Game.ID)
+	it.Equal(api.LobbyEventSeatFilled, events[2].Type)
+	it.Equal(first, events[2].Game.ID)
+	it.Len(events[2].Game.Players, 2)
+
+	res := listLobby(it, s, "")
+	it.Equal([]uuid.UUID{last, first}, gameIDs(res.Games))
+	it.Equal(2, res.Total)
+	it.Equal(server.DefaultLobbyLimit, res.Limit)
+	it.Equal("ann", res.Games[1].Host.Username)
+	it.Equal(model.GameStatusOpen, res.Games[1].Status)
+	it.NotEqual(private, res.Games[0].ID)
+
+	// each filter narrows it down
+	it.Equal([]uuid.UUID{first}, gameIDs(listLobby(it, s, "host=ann").Games))
+	it.Empty(listLobby(it, s, "host=bob").Games)
+	it.Equal([]uuid.UUID{last}, gameIDs(listLobby(it, s, "seats=3").Games))
+	it.Len(listLobby(it, s, "seats=2").Games, 2)
+	it.Empty(listLobby(it, s, "seats=4").Games)
+	it.Len(listLobby(it, s, "game=splendor&variant=standard").Games, 2)
+	it.Empty(listLobby(it, s, "variant=other").Games)
+	it.Empty(listLobby(it, s, "game=other").Games)
+
+	// pages keep the total of every match
+	page := listLobby(it, s, "offset=1&limit=1")
+	it.Equal([]uuid.UUID{first}, gameIDs(page.Games))
+	it.Equal(2, page.Total)
+	it.Equal(1, page.Offset)
+	it.Equal(1, page.Limit)
+	it.Empty(listLobby(it, s, "offset=5").Games)
+	it.Equal(server.MaxLobbyLimit, listLobby(it, s, "limit=1000").Limit)
+	it.Equal(server.DefaultLobbyLimit, listLobby(it, s, "limit=0").Limit)
+	it.Equal(http.StatusBadRequest, call(it, s.ListLobby, nil, "/api/v1alpha1/lobby?limit=ten", nil).StatusCode)
+
+	// a started game leaves the open games and watchers are told
+	it.Equal(http.StatusOK, call(it, s.StartGame, ann, "/", nil, "id", first.String()).StatusCode)
+	for i := 0; i < 100 && len(lobbyEvents(it, eve)) < 4; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	events = lobbyEvents(it, eve)
+	it.Len(events, 4)
+	it.Equal(api.LobbyEventStarted, events[3].Type)
+	it.Equal(model.GameStatusPlaying, events[3].Game.Status)
+	it.Equal([]uuid.UUID{last}, gameIDs(listLobby(it, s, "").Games))
+	it.Equal([]uuid.UUID{first}, gameIDs(listLobby(it, s, "status=playing").Games))
+}
@@ -0,0 +1,348 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	"github.com/mat285/boardgames/games"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	matchmaking "github.com/mat285/boardgames/pkg/matchmaking/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+// notice is a packet for a player, collected under the match lock and sent
+// once it is released
+type notice struct {
+	player uuid.UUID
+	t      wire.PacketType
+	body   interface{}
+}
+
+// Queue puts the current user in the queue for a game of the given size,
+// queueing again replaces their ticket
+func (s *Server) Queue(r *web.Ctx) web.Result {
+	userID, username, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	name, _ := r.Param("name")
+	rg, has := games.RegisteredGames()[name]
+	if !has {
+		return web.JSON.NotFound()
+	}
+	var req api.QueueRequest
+	if err := r.PostBodyAsJSON(&req); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	g, err := rg.New(nil)
+	if err != nil {
+		return web.JSON.InternalError(err)
+	}
+	min, max := game.GameSeats(g)
+	if req.Players < min || (max > 0 && req.Players > max) {
+		return web.JSON.BadRequest(fmt.Errorf("%s is played by %d to %d players", name, min, max))
+	}
+	if req.Window < 0 {
+		return web.JSON.BadRequest(fmt.Errorf("Rating window cannot be negative"))
+	}
+
+	s.matchLock.Lock()
+	defer s.matchLock.Unlock()
+	if existing, has := s.tickets[userID.ToFullString()]; has && existing.Status == api.TicketMatched {
+		return web.JSON.BadRequest(fmt.Errorf("Already matched, confirm or leave the queue first"))
+	}
+	ticket := &api.Ticket{
+		Ticket: matchmaking.Ticket{
+			Player:  userID,
+			Game:    name,
+			Players: req.Players,
+			Rating:  s.rating(userID, name),
+			Window:  req.Window,
+			Queued:  time.Now().UTC(),
+		},
+		Username: username,
+		Status:   api.TicketWaiting,
+	}
+	s.tickets[userID.ToFullString()] = ticket
+	return web.JSON.Result(ticket)
+}
+
+// GetTicket returns where the current user is in the queue
+func (s *Server) GetTicket(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.matchLock.Lock()
+	defer s.matchLock.Unlock()
+	ticket, has := s.tickets[userID.ToFullString()]
+	if !has {
+		return web.JSON.NotFound()
+	}
+	return web.JSON.Result(ticket)
+}
+
+// LeaveQueue takes the current user out of the queue, leaving a table that
+// hasn't started yet cancels it for everyone
+func (s *Server) LeaveQueue(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.matchLock.Lock()
+	ticket, has := s.tickets[userID.ToFullString()]
+	var notices []notice
+	if has {
+		delete(s.tickets, userID.ToFullString())
+		if match, ok := s.matches[ticket.Match.ToFullString()]; ticket.Match != nil && ok {
+			notices = s.cancelMatch(match)
+		}
+	}
+	s.matchLock.Unlock()
+	s.notify(s.Ctx, notices)
+	return web.JSON.OK()
+}
+
+// ConfirmMatch accepts the table, the game starts once everyone has
+func (s *Server) ConfirmMatch(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.matchLock.Lock()
+	match, has := s.matches[id.ToFullString()]
+	if !has {
+		s.matchLock.Unlock()
+		return web.JSON.NotFound()
+	}
+	if indexOf(match.Players, userID) < 0 {
+		s.matchLock.Unlock()
+		return web.JSON.Forbidden()
+	}
+	if indexOf(match.Confirmed, userID) < 0 {
+		match.Confirmed = append(match.Confirmed, userID)
+	}
+	ready := len(match.Confirmed) == len(match.Players)
+	var tickets []*api.Ticket
+	if ready {
+		delete(s.matches, id.ToFullString())
+		for _, pid := range match.Players {
+			tickets = append(tickets, s.tickets[pid.ToFullString()])
+			delete(s.tickets, pid.ToFullString())
+		}
+	}
+	s.matchLock.Unlock()
+	if !ready {
+		return web.JSON.Result(match)
+	}
+
+	err = s.startMatch(s.Ctx, match, tickets)
+	if err != nil {
+		return web.JSON.InternalError(err)
+	}
+	return web.JSON.Result(match)
+}
+
+// matchmake forms tables and expires what waited too long until the server
+// stops
+func (s *Server) matchmake() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.Ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.matchmakeTick(s.Ctx, now.UTC())
+		}
+	}
+}
+
+func (s *Server) matchmakeTick(ctx context.Context, now time.Time) {
+	cfg := s.Config.Matchmaking
+	s.matchLock.Lock()
+	var notices []notice
+	for _, match := range s.matches {
+		if now.Sub(match.Created) > cfg.ConfirmTimeoutOrDefault() {
+			notices = append(notices, s.cancelMatch(match)...)
+		}
+	}
+	for key, ticket := range s.tickets {
+		if ticket.Status == api.TicketWaiting && now.Sub(ticket.Queued) > cfg.QueueTimeoutOrDefault() {
+			delete(s.tickets, key)
+			notices = append(notices, notice{player: ticket.Player, t: api.PacketTypeQueueTimeout, body: ticket})
+		}
+	}
+	for _, match := range s.formMatches(now) {
+		s.matches[match.ID.ToFullString()] = match
+		for _, pid := range match.Players {
+			ticket := s.tickets[pid.ToFullString()]
+			ticket.Status = api.TicketMatched
+			ticket.Match = match.ID
+			notices = append(notices, notice{player: pid, t: api.PacketTypeMatchFound, body: match})
+		}
+	}
+	s.matchLock.Unlock()
+	s.notify(ctx, notices)
+}
+
+// formMatches puts the waiting players at tables, the match lock has to be
+// held
+func (s *Server) formMatches(now time.Time) []*api.Match {
+	var waiting []matchmaking.Ticket
+	for _, ticket := range s.tickets {
+		if ticket.Status == api.TicketWaiting {
+			waiting = append(waiting, ticket.Ticket)
+		}
+	}
+	var matches []*api.Match
+	for _, table := range matchmaking.Form(waiting, now, s.Config.Matchmaking.BotWaitOrDefault()) {
+		matches = append(matches, &api.Match{
+			ID:      uuid.V4(),
+			Game:    table.Game,
+			Players: table.Players,
+			Bots:    table.Bots,
+			Created: now,
+		})
+	}
+	return matches
+}
+
+// cancelMatch breaks up the table, players that confirmed go back to the
+// queue where they were and the rest lose their ticket. The match lock has
+// to be held
+func (s *Server) cancelMatch(match *api.Match) []notice {
+	delete(s.matches, match.ID.ToFullString())
+	var notices []notice
+	for _, pid := range match.Players {
+		notices = append(notices, notice{player: pid, t: api.PacketTypeMatchCancelled, body: match})
+		ticket, has := s.tickets[pid.ToFullString()]
+		if !has {
+			continue
+		}
+		if indexOf(match.Confirmed, pid) < 0 {
+			delete(s.tickets, pid.ToFullString())
+			continue
+		}
+		ticket.Status = api.TicketWaiting
+		ticket.Match = nil
+	}
+	return notices
+}
+
+// startMatch creates the game through the registry, seats everyone with
+// bots in the empty seats and starts it. When that fails part way the game
+// and its bots are dropped and the players are told the match is off
+func (s *Server) startMatch(ctx context.Context, match *api.Match, tickets []*api.Ticket) error {
+	rg, has := games.RegisteredGames()[match.Game]
+	if !has {
+		return s.abandonMatch(ctx, match, nil, nil, fmt.Errorf("Unknown game %s", match.Game))
+	}
+	g, err := rg.New(nil)
+	if err != nil {
+		return s.abandonMatch(ctx, match, nil, nil, err)
+	}
+	e, err := s.Router.NewEngine(ctx, g, nil)
+	if err != nil {
+		return s.abandonMatch(ctx, match, nil, nil, err)
+	}
+	// tables from the queue aren't open to the lobby
+	e.Private = true
+	s.track(e)
+	for _, ticket := range tickets {
+		if ticket == nil {
+			continue
+		}
+		if s.Router.GetClient(ticket.Player) == nil {
+			s.Router.ConnectClient(ctx, NewWebsocket(ticket.Player, ticket.Username, nil, s.InboundPackets))
+		}
+		err = s.Router.Join(ctx, ticket.Player, e.ID)
+		if err != nil {
+			return s.abandonMatch(ctx, match, e, nil, err)
+		}
+	}
+	var bots []*BotClient
+	for i := 0; i < match.Bots; i++ {
+		b, err := s.newBot(ctx, fmt.Sprintf("bot-%d", i+1), g)
+		if err != nil {
+			return s.abandonMatch(ctx, match, e, bots, err)
+		}
+		bots = append(bots, b)
+		err = b.Join(ctx, e.ID)
+		if err != nil {
+			return s.abandonMatch(ctx, match, e, bots, err)
+		}
+	}
+	if len(bots) > 0 {
+		e.Observe(dismissBots(bots))
+	}
+	match.Engine = e.ID
+
+	var notices []notice
+	for _, pid := range match.Players {
+		notices = append(notices, notice{player: pid, t: api.PacketTypeMatchStarted, body: match})
+	}
+	s.notify(ctx, notices)
+	go func() {
+		logger.MaybeError(logger.GetLogger(ctx), s.Router.StartEngine(ctx, e.ID))
+	}()
+	return nil
+}
+
+// abandonMatch drops what startMatch set up before it failed and tells the
+// players the match is cancelled, it returns the error
+func (s *Server) abandonMatch(ctx context.Context, match *api.Match, e *engine.Engine, bots []*BotClient, err error) error {
+	for _, b := range bots {
+		b.Disconnect(ctx)
+	}
+	if e != nil {
+		s.Router.DisconnectServer(ctx, e.ID)
+		s.dropInvite(e.ID)
+	}
+	var notices []notice
+	for _, pid := range match.Players {
+		notices = append(notices, notice{player: pid, t: api.PacketTypeMatchCancelled, body: match})
+	}
+	s.notify(ctx, notices)
+	return err
+}
+
+// dismissBots takes the server bots off the router once their game is over
+func dismissBots(bots []*BotClient) engine.Observer {
+	return func(ctx context.Context, _ *engine.Engine, event engine.Event) {
+		if event.Type != engine.EventTypeOver {
+			return
+		}
+		for _, b := range bots {
+			b.Disconnect(ctx)
+		}
+	}
+}
+
+func (s *Server) notify(ctx context.Context, notices []notice) {
+	log := logger.GetLogger(ctx)
+	for _, n := range notices {
+		logger.MaybeError(log, s.respondJSON(ctx, nil, n.player, n.t, n.body))
+	}
+}
+
+func indexOf(ids []uuid.UUID, id uuid.UUID) int {
+	for i := range ids {
+		if ids[i].Equal(id) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,254 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	notify "github.com/mat285/boardgames/pkg/notify/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+const (
+	// QueryKeyUnread only lists the notifications that haven't been read
+	QueryKeyUnread = "unread"
+)
+
+// GetNotifications returns the current user's inbox
+func (s *Server) GetNotifications(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	unread := false
+	if _, err := r.QueryValue(QueryKeyUnread); err == nil {
+		unread, err = web.BoolValue(r.QueryValue(QueryKeyUnread))
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+	}
+	inbox := api.Inbox{
+		Notifications: s.Notifier.Inbox.List(userID, unread),
+		Unread:        len(s.Notifier.Inbox.List(userID, true)),
+	}
+	return web.JSON.Result(inbox)
+}
+
+// ReadNotifications marks notifications in the current user's inbox read
+func (s *Server) ReadNotifications(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	var req api.ReadNotificationsRequest
+	if err := r.PostBodyAsJSON(&req); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.Notifier.Inbox.MarkRead(userID, req.IDs...)
+	return web.JSON.OK()
+}
+
+func (s *Server) GetNotificationPreferences(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	return web.JSON.Result(s.Notifier.Preferences(userID))
+}
+
+// SetNotificationPreferences replaces how the current user is notified, a
+// new email is mailed a token and nothing else until it is confirmed
+func (s *Server) SetNotificationPreferences(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	var prefs notify.Preferences
+	if err := r.PostBodyAsJSON(&prefs); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	if len(prefs.Webhook) > 0 {
+		if s.Config.Notifications.DisableWebhooks {
+			return web.JSON.BadRequest(fmt.Errorf("Webhooks are turned off"))
+		}
+		if err := notify.CheckWebhook(prefs.Webhook, s.Config.Notifications.WebhookHosts); err != nil {
+			return web.JSON.BadRequest(err)
+		}
+	}
+	old := s.Notifier.Preferences(userID)
+	if err := s.Notifier.SetPreferences(userID, prefs); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	saved := s.Notifier.Preferences(userID)
+	if len(saved.Email) > 0 && saved.Email != old.Email {
+		if err := s.sendEmailToken(userID, saved.Email); err != nil {
+			return web.JSON.InternalError(err)
+		}
+	}
+	return web.JSON.Result(saved)
+}
+
+// ConfirmEmail confirms the current user's email with the token mailed to it
+func (s *Server) ConfirmEmail(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	var req api.ConfirmEmailRequest
+	if err := r.PostBodyAsJSON(&req); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	if err := s.Notifier.ConfirmEmail(userID, req.Token); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	return web.JSON.Result(s.Notifier.Preferences(userID))
+}
+
+// sendEmailToken mails the player a new token to confirm the address with,
+// in the background like the notifications. Without a mail server there is
+// nothing to send
+func (s *Server) sendEmailToken(player uuid.UUID, email string) error {
+	var mailer *notify.SMTP
+	for _, channel := range s.Notifier.Channels {
+		if typed, ok := channel.(*notify.SMTP); ok {
+			mailer = typed
+		}
+	}
+	if mailer == nil {
+		return nil
+	}
+	token, err := s.Notifier.NewEmailToken(player)
+	if err != nil {
+		return err
+	}
+	n := notify.Notification{
+		Player: player,
+		Title:  "Confirm your email",
+		Text:   fmt.Sprintf("Confirm this address for your game notifications with the code %s", token),
+		Time:   time.Now().UTC(),
+	}
+	go func() {
+		logger.MaybeError(logger.GetLogger(s.Ctx), mailer.Send(email, n))
+	}()
+	return nil
+}
+
+// observeNotifications tells the players of the game when it starts, when
+// it is their turn and when it is over
+func (s *Server) observeNotifications(ctx context.Context, e *engine.Engine, event engine.Event) {
+	e.Lock()
+	name := e.Game.Name()
+	players := e.PlayerIDs()
+	e.Unlock()
+	switch event.Type {
+	case engine.EventTypeStarted:
+		for _, player := range players {
+			s.sendNotification(notify.Notification{
+				Player: player,
+				Type:   notify.TypeStarted,
+				Game:   e.ID,
+				Title:  fmt.Sprintf("Your game of %s has started", name),
+			})
+		}
+	case engine.EventTypeTurn:
+		acting, _ := event.Body.([]uuid.UUID)
+		e.Lock()
+		deadline := e.Deadline()
+		e.Unlock()
+		for _, player := range acting {
+			n := notify.Notification{
+				Player: player,
+				Type:   notify.TypeYourTurn,
+				Game:   e.ID,
+				Title:  fmt.Sprintf("It's your turn in %s", name),
+			}
+			if !deadline.IsZero() {
+				n.Text = fmt.Sprintf("Make your move by %s", deadline.Format(time.RFC1123))
+			}
+			s.sendNotification(n)
+		}
+	case engine.EventTypeOver:
+		e.Lock()
+		standings, err := e.Standings()
+		e.Unlock()
+		if err != nil {
+			logger.MaybeError(logger.GetLogger(ctx), err)
+		}
+		for _, player := range players {
+			n := notify.Notification{
+				Player: player,
+				Type:   notify.TypeOver,
+				Game:   e.ID,
+				Title:  fmt.Sprintf("Your game of %s is over", name),
+			}
+			if standing := standingOf(standings, player); standing != nil {
+				n.Text = fmt.Sprintf("You finished %s with %d points", ordinal(standing.Place), standing.Points)
+			}
+			s.sendNotification(n)
+		}
+	}
+}
+
+// sendNotification delivers in the background so a slow webhook or mail
+// server never holds up a game, bots are never notified
+func (s *Server) sendNotification(n notify.Notification) {
+	if s.isBot(n.Player) {
+		return
+	}
+	go func() {
+		logger.MaybeError(logger.GetLogger(s.Ctx), s.Notifier.Notify(s.Ctx, n))
+	}()
+}
+
+// flushNotifications sends what was held back for quiet hours once they end
+// for the player, until the server stops
+func (s *Server) flushNotifications() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.Ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			logger.MaybeError(logger.GetLogger(s.Ctx), s.Notifier.Flush(s.Ctx, now.UTC()))
+		}
+	}
+}
+
+func newNotifier(config Notifications) *notify.Notifier {
+	var channels []notify.Channel
+	if !config.DisableWebhooks {
+		channels = append(channels, notify.NewWebhook(config.WebhookTimeout, config.WebhookHosts...))
+	}
+	if len(config.SMTP.Addr) > 0 {
+		email := &notify.SMTP{Addr: config.SMTP.Addr, From: config.SMTP.From}
+		if len(config.SMTP.Username) > 0 {
+			host, _, _ := net.SplitHostPort(config.SMTP.Addr)
+			email.Auth = smtp.PlainAuth("", config.SMTP.Username, config.SMTP.Password, host)
+		}
+		channels = append(channels, email)
+	}
+	return notify.NewNotifier(channels...)
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
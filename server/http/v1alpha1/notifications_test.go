@@ -0,0 +1,27 @@
+package v1alpha1_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	notify "github.com/mat285/boardgames/pkg/notify/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+func TestNotificationEmail(t *testing.T) {
+	it := assert.New(t)
+	s := testServer(t)
+	ann := newUser(s, "ann")
+
+	bad := call(it, s.SetNotificationPreferences, ann, "/", notify.Preferences{Email: "ann at example"})
+	it.Equal(http.StatusBadRequest, bad.StatusCode)
+
+	// nobody confirms their own address
+	var saved notify.Preferences
+	decode(it, call(it, s.SetNotificationPreferences, ann, "/", notify.Preferences{Email: "Ann <ann@example.com>", EmailConfirmed: true}), &saved)
+	it.Equal("ann@example.com", saved.Email)
+	it.False(saved.EmailConfirmed)
+	it.Equal(http.StatusBadRequest, call(it, s.ConfirmEmail, ann, "/", api.ConfirmEmailRequest{Token: "guess"}).StatusCode)
+	it.False(s.Notifier.Preferences(ann.ID).EmailConfirmed)
+}
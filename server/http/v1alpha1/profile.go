@@ -0,0 +1,199 @@
+package v1alpha1
+
+import (
+	"context"
+	"sort"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	"github.com/mat285/boardgames/games"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+// RecentGames is how many games a profile lists
+const RecentGames = 10
+
+// GetProfile returns the player's results worked out from the records of
+// the games they finished
+func (s *Server) GetProfile(r *web.Ctx) web.Result {
+	username, _ := r.Param("name")
+	id := s.GetUserID(username)
+	if id.IsZero() {
+		return web.JSON.NotFound()
+	}
+	records, err := s.playedRecords(r.Context(), id)
+	if err != nil {
+		return web.JSON.InternalError(err)
+	}
+
+	profile := api.Profile{
+		Player:   id,
+		Username: username,
+		Games:    []api.GameProfile{},
+		Recent:   []api.RecentGame{},
+	}
+	byGame := make(map[string][]game.Record)
+	var names []string
+	for _, record := range records {
+		if _, has := byGame[record.Game]; !has {
+			names = append(names, record.Game)
+		}
+		byGame[record.Game] = append(byGame[record.Game], record)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		gp, err := s.gameProfile(id, name, byGame[name])
+		if err != nil {
+			return web.JSON.InternalError(err)
+		}
+		profile.Played += gp.Played
+		profile.Wins += gp.Wins
+		profile.Games = append(profile.Games, gp)
+	}
+	if profile.Played > 0 {
+		profile.WinRate = float64(profile.Wins) / float64(profile.Played)
+	}
+	for i := len(records) - 1; i >= 0 && len(profile.Recent) < RecentGames; i-- {
+		recent := api.RecentGame{
+			ID:      records[i].ID,
+			Game:    records[i].Game,
+			Variant: records[i].Variant,
+			Created: records[i].Created,
+			Players: records[i].Players,
+		}
+		if standing := standingOf(records[i].Standings, id); standing != nil {
+			recent.Place, recent.Points = standing.Place, standing.Points
+		}
+		profile.Recent = append(profile.Recent, recent)
+	}
+	return web.JSON.Result(profile)
+}
+
+func (s *Server) gameProfile(player uuid.UUID, name string, records []game.Record) (api.GameProfile, error) {
+	gp := api.GameProfile{
+		Game:     name,
+		Played:   len(records),
+		Variants: make(map[string]int),
+	}
+	points := 0
+	for _, record := range records {
+		if standing := standingOf(record.Standings, player); standing != nil {
+			points += standing.Points
+			if standing.Place == 1 {
+				gp.Wins++
+			}
+		}
+		gp.Variants[record.Variant]++
+		if gp.FavouriteVariant == "" || gp.Variants[record.Variant] > gp.Variants[gp.FavouriteVariant] {
+			gp.FavouriteVariant = record.Variant
+		}
+	}
+	if gp.Played > 0 {
+		gp.WinRate = float64(gp.Wins) / float64(gp.Played)
+		gp.AverageScore = float64(points) / float64(gp.Played)
+	}
+
+	s.ratingsLock.Lock()
+	if pr, has := s.ratings[name][player.ToFullString()]; has {
+		rated := *pr
+		rated.History = nil
+		gp.Rating = &rated
+	}
+	s.ratingsLock.Unlock()
+
+	rg, has := games.RegisteredGames()[name]
+	if !has {
+		return gp, nil
+	}
+	g, err := rg.New(nil)
+	if err != nil {
+		return gp, err
+	}
+	gp.Stats, err = game.GameStats(g, player, records)
+	return gp, err
+}
+
+// playedRecords loads the records of the games the player finished, oldest
+// first
+func (s *Server) playedRecords(ctx context.Context, player uuid.UUID) ([]game.Record, error) {
+	s.recordsLock.Lock()
+	ids := append([]uuid.UUID{}, s.played[player.ToFullString()]...)
+	s.recordsLock.Unlock()
+	records := make([]game.Record, 0, len(ids))
+	for _, id := range ids {
+		obj, err := s.Store.Load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		record, err := engine.RecordFromObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		if record.Standings == nil {
+			continue
+		}
+		records = append(records, *record)
+	}
+	return records, nil
+}
+
+// observeRecords remembers the finished game for the profiles of its
+// players, the record itself was saved before
+func (s *Server) observeRecords(ctx context.Context, e *engine.Engine, event engine.Event) {
+	if event.Type != engine.EventTypeOver {
+		return
+	}
+	e.Lock()
+	players := e.PlayerIDs()
+	persisted := e.Persist != nil
+	e.Unlock()
+	if !persisted {
+		// there's no record to build the profile from
+		return
+	}
+	s.recordsLock.Lock()
+	defer s.recordsLock.Unlock()
+	for _, p := range players {
+		s.played[p.ToFullString()] = append(s.played[p.ToFullString()], e.ID)
+	}
+	logger.MaybeError(logger.GetLogger(ctx), s.playedStored.save(ctx, s.Store, s.played))
+}
+
+// loadPlayed reads the finished games of each player back from the store
+func (s *Server) loadPlayed(ctx context.Context) error {
+	s.recordsLock.Lock()
+	defer s.recordsLock.Unlock()
+	if err := s.playedStored.load(ctx, s.Store, &s.played); err != nil {
+		return err
+	}
+	if s.played == nil {
+		s.played = make(map[string][]uuid.UUID)
+	}
+	return nil
+}
+
+// track persists the game, keeps its result for the ratings and the
+// profiles of its players, moderates its chat, notifies its players and
+// hibernates it between moves if it is a correspondence game
+func (s *Server) track(e *engine.Engine) {
+	e.Persist = s.Store
+	e.ChatHistory = s.Config.Chat.History
+	e.ChatFilter = s.filterChat
+	e.SpectatorChat = s.Config.Chat.Spectators
+	e.Observe(s.observeRatings)
+	e.Observe(s.observeRecords)
+	e.Observe(s.observeNotifications)
+	e.Observe(s.observeCorrespondence)
+}
+
+func standingOf(standings []game.Standing, player uuid.UUID) *game.Standing {
+	for i := range standings {
+		if standings[i].Player.Equal(player) {
+			return &standings[i]
+		}
+	}
+	return nil
+}
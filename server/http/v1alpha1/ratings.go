@@ -0,0 +1,265 @@
+package v1alpha1
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	rating "github.com/mat285/boardgames/pkg/rating/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+const (
+	// QueryKeyProvisional set to false leaves provisional ratings off the
+	// leaderboard
+	QueryKeyProvisional = "provisional"
+	// QueryKeyCasual creates a game that isn't rated when the server leaves
+	// casual games out
+	QueryKeyCasual = "casual"
+)
+
+const (
+	DefaultLeaderboardLimit = 20
+	MaxLeaderboardLimit     = 100
+)
+
+// Leaderboard lists the rated players of a game, highest first
+func (s *Server) Leaderboard(r *web.Ctx) web.Result {
+	name, _ := r.Param("game")
+	res := api.Leaderboard{
+		Game:    name,
+		Players: []api.PlayerRating{},
+		Limit:   DefaultLeaderboardLimit,
+	}
+	for key, value := range map[string]*int{
+		QueryKeyOffset: &res.Offset,
+		QueryKeyLimit:  &res.Limit,
+	} {
+		if _, err := r.QueryValue(key); err != nil {
+			continue
+		}
+		parsed, err := web.IntValue(r.QueryValue(key))
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+		*value = parsed
+	}
+	if res.Offset < 0 {
+		res.Offset = 0
+	}
+	if res.Limit <= 0 {
+		res.Limit = DefaultLeaderboardLimit
+	}
+	if res.Limit > MaxLeaderboardLimit {
+		res.Limit = MaxLeaderboardLimit
+	}
+	provisional := true
+	if _, err := r.QueryValue(QueryKeyProvisional); err == nil {
+		provisional, err = web.BoolValue(r.QueryValue(QueryKeyProvisional))
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+	}
+
+	s.ratingsLock.Lock()
+	var ranked []api.PlayerRating
+	for _, pr := range s.ratings[name] {
+		if !provisional && pr.Provisional {
+			continue
+		}
+		entry := *pr
+		entry.History = nil
+		ranked = append(ranked, entry)
+	}
+	s.ratingsLock.Unlock()
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Rating.Rating != ranked[j].Rating.Rating {
+			return ranked[i].Rating.Rating > ranked[j].Rating.Rating
+		}
+		return ranked[i].Username < ranked[j].Username
+	})
+
+	res.Total = len(ranked)
+	if res.Offset < len(ranked) {
+		end := res.Offset + res.Limit
+		if end > len(ranked) {
+			end = len(ranked)
+		}
+		res.Players = ranked[res.Offset:end]
+	}
+	return web.JSON.Result(res)
+}
+
+// GetRatings returns the player's rating and its history in every game they
+// played rated
+func (s *Server) GetRatings(r *web.Ctx) web.Result {
+	username, _ := r.Param("username")
+	id := s.GetUserID(username)
+	if id.IsZero() {
+		return web.JSON.NotFound()
+	}
+	s.ratingsLock.Lock()
+	defer s.ratingsLock.Unlock()
+	ratings := []api.PlayerRating{}
+	for _, players := range s.ratings {
+		if pr, has := players[id.ToFullString()]; has {
+			entry := *pr
+			entry.History = append([]api.RatingChange{}, pr.History...)
+			ratings = append(ratings, entry)
+		}
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		return ratings[i].Game < ratings[j].Game
+	})
+	return web.JSON.Result(ratings)
+}
+
+// observeRatings rates the players of a game once it is over, unless the
+// config leaves the game out or its seed was picked
+func (s *Server) observeRatings(ctx context.Context, e *engine.Engine, event engine.Event) {
+	if event.Type != engine.EventTypeOver {
+		return
+	}
+	e.Lock()
+	name := e.Game.Name()
+	casual := e.Casual
+	seeded := e.Seeded
+	players := e.GamePlayers()
+	standings, err := e.Standings()
+	e.Unlock()
+	if err != nil {
+		logger.MaybeError(logger.GetLogger(ctx), err)
+		return
+	}
+	if len(standings) < 2 || seeded || (casual && s.Config.Ratings.ExcludeCasual) {
+		return
+	}
+	if s.Config.Ratings.ExcludeBots {
+		for _, p := range players {
+			if s.isBot(p.ID) {
+				return
+			}
+		}
+	}
+	usernames := make(map[string]string)
+	for _, p := range players {
+		usernames[p.ID.ToFullString()] = p.Username
+	}
+	logger.MaybeError(logger.GetLogger(ctx), s.rate(ctx, name, e.ID, standings, usernames))
+}
+
+// rate updates the ratings of the players of a finished game and saves them
+func (s *Server) rate(ctx context.Context, name string, id uuid.UUID, standings []game.Standing, usernames map[string]string) error {
+	s.ratingsLock.Lock()
+	defer s.ratingsLock.Unlock()
+	players, has := s.ratings[name]
+	if !has {
+		players = make(map[string]*api.PlayerRating)
+		s.ratings[name] = players
+	}
+	current := make([]rating.Rating, len(standings))
+	for i, standing := range standings {
+		current[i] = rating.NewRating()
+		if pr, has := players[standing.Player.ToFullString()]; has {
+			current[i] = pr.Rating
+		}
+	}
+	now := time.Now().UTC()
+	for i, updated := range rating.Rate(standings, current) {
+		key := standings[i].Player.ToFullString()
+		pr, has := players[key]
+		if !has {
+			pr = &api.PlayerRating{Player: standings[i].Player, Game: name}
+			players[key] = pr
+		}
+		pr.Username = usernames[key]
+		pr.Rating = updated
+		pr.Provisional = updated.IsProvisional()
+		pr.History = append(pr.History, api.RatingChange{Game: id, Time: now, Rating: updated})
+	}
+	return s.saveRatings(ctx)
+}
+
+// rating is the player's rating in the game rounded for matchmaking
+func (s *Server) rating(player uuid.UUID, name string) int {
+	s.ratingsLock.Lock()
+	defer s.ratingsLock.Unlock()
+	if pr, has := s.ratings[name][player.ToFullString()]; has {
+		return int(math.Round(pr.Rating.Rating))
+	}
+	return rating.DefaultRating
+}
+
+// newBot connects a server bot and remembers it so its games can be left
+// out of the ratings
+func (s *Server) newBot(ctx context.Context, username string, g game.Game) (*BotClient, error) {
+	b, err := NewServerBot(ctx, s.Router, username, g)
+	if err != nil {
+		return nil, err
+	}
+	return b, s.markBot(ctx, b.ID)
+}
+
+// markBot remembers the player is a bot and saves it
+func (s *Server) markBot(ctx context.Context, id uuid.UUID) error {
+	s.ratingsLock.Lock()
+	defer s.ratingsLock.Unlock()
+	if s.bots[id.ToFullString()] {
+		return nil
+	}
+	s.bots[id.ToFullString()] = true
+	return s.saveRatings(ctx)
+}
+
+// unmarkBot forgets the player was a bot and saves it
+func (s *Server) unmarkBot(ctx context.Context, id uuid.UUID) error {
+	s.ratingsLock.Lock()
+	defer s.ratingsLock.Unlock()
+	if !s.bots[id.ToFullString()] {
+		return nil
+	}
+	delete(s.bots, id.ToFullString())
+	return s.saveRatings(ctx)
+}
+
+func (s *Server) isBot(id uuid.UUID) bool {
+	s.ratingsLock.Lock()
+	defer s.ratingsLock.Unlock()
+	return s.bots[id.ToFullString()]
+}
+
+// ratingsRecord is how the ratings and the bots are kept in the store
+type ratingsRecord struct {
+	Ratings map[string]map[string]*api.PlayerRating
+	Bots    map[string]bool
+}
+
+// saveRatings writes the ratings and the bots to the store, the ratings lock
+// has to be held
+func (s *Server) saveRatings(ctx context.Context) error {
+	return s.ratingsStored.save(ctx, s.Store, ratingsRecord{Ratings: s.ratings, Bots: s.bots})
+}
+
+// loadRatings reads the ratings and the bots back from the store
+func (s *Server) loadRatings(ctx context.Context) error {
+	s.ratingsLock.Lock()
+	defer s.ratingsLock.Unlock()
+	record := ratingsRecord{Ratings: s.ratings, Bots: s.bots}
+	if err := s.ratingsStored.load(ctx, s.Store, &record); err != nil {
+		return err
+	}
+	s.ratings, s.bots = record.Ratings, record.Bots
+	if s.ratings == nil {
+		s.ratings = make(map[string]map[string]*api.PlayerRating)
+	}
+	if s.bots == nil {
+		s.bots = make(map[string]bool)
+	}
+	return nil
+}
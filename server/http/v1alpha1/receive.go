@@ -24,6 +24,16 @@ func (s *Server) Receive(ctx context.Context, packet wire.Packet) error {
 		return s.respondJSON(ctx, packet.ID, packet.Origin, api.PacketTypeListGamesResponse, resp)
 	case api.PacketTypeNewGameRequest:
 	case api.PacketTypeJoinGameRequest:
+	case api.PacketTypeLobbySubscribe:
+		s.subscribeLobby(packet.Origin, true)
+	case api.PacketTypeLobbyUnsubscribe:
+		s.subscribeLobby(packet.Origin, false)
+	case api.PacketTypeTournamentSubscribe, api.PacketTypeTournamentUnsubscribe:
+		var sub api.TournamentSubscription
+		if err := json.Unmarshal(packet.Payload, &sub); err != nil {
+			return err
+		}
+		s.subscribeTournament(packet.Origin, sub.Tournament, packet.Type == api.PacketTypeTournamentSubscribe)
 	default:
 		return fmt.Errorf("Unsupported packet type %d", packet.Type)
 	}
@@ -0,0 +1,162 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+// SeriesStatus is a series with where everyone stands across its games
+type SeriesStatus struct {
+	game.Series
+	Standings []game.Standing
+	Done      bool
+	Winners   []uuid.UUID
+}
+
+// Rematch sets up a new game with the same players and config once a game
+// is over, it becomes the next game of the series the game was part of
+func (s *Server) Rematch(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	e := s.Router.GetEngine(id)
+	if e == nil {
+		return web.JSON.NotFound()
+	}
+	if e.GetPlayer(userID) == nil {
+		return web.JSON.Forbidden()
+	}
+	e.Lock()
+	next := e.Next
+	e.Unlock()
+	if next != nil {
+		// someone else asked first
+		return web.JSON.Result(next)
+	}
+	rematch, err := s.Router.Rematch(s.Ctx, id)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	if _, err := s.newInvite(rematch.ID); err != nil {
+		return web.JSON.InternalError(err)
+	}
+	s.publishLobby(s.Ctx, api.LobbyEventCreated, rematch)
+
+	s.seriesLock.Lock()
+	defer s.seriesLock.Unlock()
+	if series := s.seriesOf(id); series != nil {
+		s.updateSeries(series)
+		if !series.IsDone() {
+			err = series.AddGame(rematch.ID)
+			if err != nil {
+				return web.JSON.InternalError(err)
+			}
+		}
+	}
+	return web.JSON.Result(rematch.ID)
+}
+
+// NewSeries starts a series with the game as its first game
+func (s *Server) NewSeries(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	format, err := r.QueryValue(QueryKeyFormat)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	target, err := web.IntValue(r.QueryValue(QueryKeyTarget))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	e := s.Router.GetEngine(id)
+	if e == nil {
+		return web.JSON.NotFound()
+	}
+	if e.GetPlayer(userID) == nil {
+		return web.JSON.Forbidden()
+	}
+
+	s.seriesLock.Lock()
+	defer s.seriesLock.Unlock()
+	if s.seriesOf(id) != nil {
+		return web.JSON.BadRequest(fmt.Errorf("Game is already part of a series"))
+	}
+	series, err := game.NewSeries(game.SeriesFormat(format), target, e.PlayerIDs())
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	err = series.AddGame(id)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.Series[series.ID.ToFullString()] = series
+	return web.JSON.Result(series.ID)
+}
+
+func (s *Server) GetSeries(r *web.Ctx) web.Result {
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.seriesLock.Lock()
+	defer s.seriesLock.Unlock()
+	series, has := s.Series[id.ToFullString()]
+	if !has {
+		return web.JSON.NotFound()
+	}
+	s.updateSeries(series)
+	return web.JSON.Result(SeriesStatus{
+		Series:    *series,
+		Standings: series.Standings(),
+		Done:      series.IsDone(),
+		Winners:   series.Winners(),
+	})
+}
+
+// seriesOf finds the series the game is part of, the series lock has to be
+// held
+func (s *Server) seriesOf(id uuid.UUID) *game.Series {
+	for _, series := range s.Series {
+		if series.Has(id) {
+			return series
+		}
+	}
+	return nil
+}
+
+// updateSeries picks up the results of games that finished since, the
+// series lock has to be held
+func (s *Server) updateSeries(series *game.Series) {
+	for _, g := range series.Games {
+		if g.Standings != nil {
+			continue
+		}
+		e := s.Router.GetEngine(g.ID)
+		if e == nil {
+			continue
+		}
+		e.Lock()
+		standings, err := e.Standings()
+		e.Unlock()
+		if err != nil || standings == nil {
+			continue
+		}
+		logger.MaybeError(logger.GetLogger(s.Ctx), series.Finish(g.ID, standings))
+	}
+}
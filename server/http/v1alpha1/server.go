@@ -2,14 +2,23 @@ package v1alpha1
 
 import (
 	"context"
+	"regexp"
 	"sync"
+	"time"
 
 	"github.com/blend/go-sdk/logger"
 	"github.com/blend/go-sdk/uuid"
 	"github.com/blend/go-sdk/web"
 	obj "github.com/mat285/boardgames/pkg/core/v1alpha1"
+	correspondence "github.com/mat285/boardgames/pkg/correspondence/v1alpha1"
+	friends "github.com/mat285/boardgames/pkg/friends/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	notify "github.com/mat285/boardgames/pkg/notify/v1alpha1"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+	tournament "github.com/mat285/boardgames/pkg/tournament/v1alpha1"
 	"github.com/mat285/boardgames/pkg/websockets"
 	"github.com/mat285/boardgames/pkg/wire/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
 	core "github.com/mat285/boardgames/server/core/v1alpha1"
 )
 
@@ -24,12 +33,61 @@ type Server struct {
 	App    *web.App
 
 	Router         *core.EngineRouter
+	Store          persist.Interface
+	Notifier       *notify.Notifier
 	InboundPackets chan websockets.Packet
 	stop           chan struct{}
 	Polls          map[string]*PollClient
 
 	usersLock sync.Mutex
 	Users     map[string]uuid.UUID
+
+	seriesLock sync.Mutex
+	Series     map[string]*game.Series
+
+	// lobby holds the clients subscribed to lobby events
+	lobbyLock sync.Mutex
+	lobby     map[string]uuid.UUID
+
+	// invites holds the join code and password of each game
+	invitesLock sync.Mutex
+	invites     map[string]*invite
+
+	// tickets are keyed by player and matches by id
+	matchLock sync.Mutex
+	tickets   map[string]*api.Ticket
+	matches   map[string]*api.Match
+
+	// tournamentWatchers holds the clients subscribed to each tournament
+	tournamentsLock    sync.Mutex
+	tournaments        map[string]*tournament.Tournament
+	tournamentWatchers map[string]map[string]uuid.UUID
+
+	// ratings are keyed by game and then player, bots holds the server bots
+	// and the players that logged in as one. Both are kept in the store
+	ratingsLock   sync.Mutex
+	ratings       map[string]map[string]*api.PlayerRating
+	bots          map[string]bool
+	ratingsStored stored
+
+	// played lists the finished games of each player, oldest first, it is
+	// kept in the store
+	recordsLock  sync.Mutex
+	played       map[string][]uuid.UUID
+	playedStored stored
+
+	// chatSent holds when each player last talked, for the rate limit
+	chatLock  sync.Mutex
+	chatSent  map[string][]time.Time
+	chatWords *regexp.Regexp
+
+	// schedule keeps the deadlines of the hibernated correspondence games
+	schedule *correspondence.Scheduler
+	wakeLock sync.Mutex
+
+	// friends and the invitations between them, by username and by id
+	friends     *friends.Friends
+	invitations *friends.Invitations
 }
 
 func New(ctx context.Context, config Config) *Server {
@@ -38,10 +96,34 @@ func New(ctx context.Context, config Config) *Server {
 		Ctx:            ctx,
 		Config:         config,
 		Router:         core.NewEngineRouter(),
+		Store:          persist.NewMemory(),
+		Notifier:       newNotifier(config.Notifications),
 		InboundPackets: make(chan websockets.Packet, 16),
 		stop:           make(chan struct{}),
 		Users:          make(map[string]uuid.UUID),
+		Series:         make(map[string]*game.Series),
+		lobby:          make(map[string]uuid.UUID),
+		invites:        make(map[string]*invite),
+		tickets:        make(map[string]*api.Ticket),
+		matches:        make(map[string]*api.Match),
+
+		tournaments:        make(map[string]*tournament.Tournament),
+		tournamentWatchers: make(map[string]map[string]uuid.UUID),
+
+		ratings:       make(map[string]map[string]*api.PlayerRating),
+		bots:          make(map[string]bool),
+		ratingsStored: stored{ID: RatingsID},
+
+		played:       make(map[string][]uuid.UUID),
+		playedStored: stored{ID: PlayedID},
+
+		chatSent:  make(map[string][]time.Time),
+		chatWords: wordFilter(config.Chat.Filter),
+
+		friends:     friends.NewFriends(),
+		invitations: friends.NewInvitations(),
 	}
+	s.schedule = correspondence.NewScheduler(s.Store, s.expireMove)
 	return s
 }
 
@@ -62,7 +144,21 @@ func (s *Server) Start() error {
 	s.App = app
 
 	s.App.Register(s)
+	// deadlines that passed while the server was down are enforced first
+	s.schedule.Store = s.Store
+	if err := s.schedule.Load(s.Ctx); err != nil {
+		return err
+	}
+	if err := s.loadRatings(s.Ctx); err != nil {
+		return err
+	}
+	if err := s.loadPlayed(s.Ctx); err != nil {
+		return err
+	}
+	go s.schedule.Run(s.Ctx)
 	go s.receivePackets()
+	go s.matchmake()
+	go s.flushNotifications()
 
 	s.Users["test"] = uuid.V4()
 	s.Users["test2"] = uuid.V4()
@@ -80,7 +176,10 @@ func (s *Server) receivePackets() {
 		case <-s.stop:
 			return
 		case p := <-s.InboundPackets:
-			err := s.Router.Receive(s.Ctx, v1alpha1.FromWebsocket(p))
+			packet := v1alpha1.FromWebsocket(p)
+			// hibernated games are loaded back for their packets
+			s.engine(s.Ctx, packet.Destination)
+			err := s.Router.Receive(s.Ctx, packet)
 			if err != nil {
 				logger.MaybeError(log, err)
 			}
@@ -0,0 +1,113 @@
+package v1alpha1_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	"github.com/blend/go-sdk/webutil"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	wire "github.com/mat285/boardgames/pkg/wire/v1alpha1"
+	core "github.com/mat285/boardgames/server/core/v1alpha1"
+	server "github.com/mat285/boardgames/server/http/v1alpha1"
+)
+
+// testServer is a server that never listens, the handlers are called
+// straight from the tests. It stops with the test
+func testServer(t *testing.T) *server.Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s := server.New(ctx, server.Config{})
+	if err := s.Router.ConnectServer(ctx, core.PipeReceiver(s.ID, s)); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// user is logged in with a client connected that keeps every packet it
+// is sent
+type user struct {
+	sync.Mutex
+	game.Player
+	packets []wire.Packet
+}
+
+func newUser(s *server.Server, username string) *user {
+	u := &user{Player: game.Player{ID: s.GetOrSetUserID(username), Username: username}}
+	s.Router.ConnectClient(context.Background(), u)
+	return u
+}
+
+func (u *user) Send(_ context.Context, packet wire.Packet) error {
+	u.Lock()
+	defer u.Unlock()
+	u.packets = append(u.packets, packet)
+	return nil
+}
+
+// received returns the packets of the type sent so far
+func (u *user) received(t wire.PacketType) []wire.Packet {
+	u.Lock()
+	defer u.Unlock()
+	var packets []wire.Packet
+	for _, packet := range u.packets {
+		if packet.Type == t {
+			packets = append(packets, packet)
+		}
+	}
+	return packets
+}
+
+// call runs the handler as the user, nil for nobody logged in, with the
+// route params given as key value pairs. A non nil body is sent as json
+func call(it *assert.Assertions, handler web.Action, u *user, target string, body interface{}, params ...string) *web.JSONResult {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		it.Nil(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(data))
+	if u != nil {
+		cookie, err := json.Marshal(u.Player)
+		it.Nil(err)
+		req.AddCookie(&http.Cookie{Name: "splendor_user", Value: base64.StdEncoding.EncodeToString(cookie)})
+	}
+	route := web.RouteParameters{}
+	for i := 0; i+1 < len(params); i += 2 {
+		route[params[i]] = params[i+1]
+	}
+	w := webutil.NewStatusResponseWriter(httptest.NewRecorder())
+	res, ok := handler(web.NewCtx(w, req, web.OptCtxRouteParams(route))).(*web.JSONResult)
+	it.True(ok)
+	return res
+}
+
+// decode reads the response of a result into v
+func decode(it *assert.Assertions, res *web.JSONResult, v interface{}) {
+	it.Equal(http.StatusOK, res.StatusCode, res.Response)
+	data, err := json.Marshal(res.Response)
+	it.Nil(err)
+	it.Nil(json.Unmarshal(data, v))
+}
+
+// newGame creates a splendor game hosted by the user, the query is added
+// to the request as is
+func newGame(it *assert.Assertions, s *server.Server, host *user, query ...string) uuid.UUID {
+	target := "/api/v1alpha1/games/splendor/new"
+	if len(query) > 0 {
+		target += "?" + strings.Join(query, "&")
+	}
+	var id uuid.UUID
+	decode(it, call(it, s.NewGame, host, target, struct{}{}, "name", "splendor"), &id)
+	return id
+}
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/blend/go-sdk/uuid"
+	"github.com/mat285/boardgames/pkg/apiversions"
+	persist "github.com/mat285/boardgames/pkg/persist/v1alpha1"
+)
+
+var (
+	// RatingsID is where the ratings with their history and the bots are
+	// kept in the store
+	RatingsID = uuid.MustParse("9e6b2d41-58c7-4a0f-b3e2-1d7c5a8f6e90")
+	// PlayedID is where the finished games of each player are kept in the
+	// store, the records themselves are kept under the game's id
+	PlayedID = uuid.MustParse("2f8a6c13-d94e-4b7a-a0c5-7e31b9d4f28c")
+)
+
+// stored is a server wide object kept in the store under a fixed id, its
+// version goes up with every save. Its owner's lock has to be held
+type stored struct {
+	ID      uuid.UUID
+	version uint64
+}
+
+// save writes the data to the store as json
+func (o *stored) save(ctx context.Context, store persist.Interface, data interface{}) error {
+	if store == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	o.version++
+	_, err = store.CheckAndSet(ctx, persist.Object{
+		Meta: persist.Meta{
+			ID:            o.ID,
+			APIVersion:    apiversions.Latest,
+			ObjectVersion: o.version,
+		},
+		Data: encoded,
+	})
+	return err
+}
+
+// load reads the object back into data, a store without it leaves data
+// alone
+func (o *stored) load(ctx context.Context, store persist.Interface, data interface{}) error {
+	if store == nil {
+		return nil
+	}
+	obj, err := store.Load(ctx, o.ID)
+	if persist.IsError(err, persist.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// stores that don't keep the go type hand back the raw json
+	encoded, ok := obj.Data.([]byte)
+	if !ok {
+		encoded, err = json.Marshal(obj.Data)
+		if err != nil {
+			return err
+		}
+	}
+	if err := json.Unmarshal(encoded, data); err != nil {
+		return err
+	}
+	o.version = obj.ObjectVersion
+	return nil
+}
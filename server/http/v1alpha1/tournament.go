@@ -0,0 +1,277 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/uuid"
+	"github.com/blend/go-sdk/web"
+	"github.com/mat285/boardgames/games"
+	engine "github.com/mat285/boardgames/pkg/engine/v1alpha1"
+	game "github.com/mat285/boardgames/pkg/game/v1alpha1"
+	tournament "github.com/mat285/boardgames/pkg/tournament/v1alpha1"
+	api "github.com/mat285/boardgames/server/api/v1alpha1"
+)
+
+// NewTournament sets up a tournament of the game organized by the current
+// user, nothing is played until they start it
+func (s *Server) NewTournament(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	name, _ := r.Param("name")
+	rg, has := games.RegisteredGames()[name]
+	if !has {
+		return web.JSON.NotFound()
+	}
+	var req api.NewTournamentRequest
+	if err := r.PostBodyAsJSON(&req); err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	// make sure the tables can be set up before anyone is entered
+	g, err := newTournamentGame(rg, req.Config)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	min, max := game.GameSeats(g)
+	if req.TableSize == 0 {
+		req.TableSize = min
+	}
+	if req.TableSize < min || (max > 0 && req.TableSize > max) {
+		return web.JSON.BadRequest(fmt.Errorf("%s is played by %d to %d players", name, min, max))
+	}
+	players := make([]game.Player, len(req.Players))
+	for i, username := range req.Players {
+		players[i] = game.Player{ID: s.GetOrSetUserID(username), Username: username}
+	}
+	t, err := tournament.New(req.Name, name, req.Format, userID, players, req.TableSize, req.Rounds)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	t.Config = req.Config
+
+	s.tournamentsLock.Lock()
+	defer s.tournamentsLock.Unlock()
+	s.tournaments[t.ID.ToFullString()] = t
+	return web.JSON.Result(tournamentStatus(t))
+}
+
+// GetTournament returns the rounds and standings of a tournament
+func (s *Server) GetTournament(r *web.Ctx) web.Result {
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.tournamentsLock.Lock()
+	defer s.tournamentsLock.Unlock()
+	t, has := s.tournaments[id.ToFullString()]
+	if !has {
+		return web.JSON.NotFound()
+	}
+	return web.JSON.Result(tournamentStatus(t))
+}
+
+// StartTournament pairs the first round and starts its games, only the
+// organizer can
+func (s *Server) StartTournament(r *web.Ctx) web.Result {
+	userID, _, err := s.CurrentUser(r)
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	id, err := web.UUIDValue(r.Param("id"))
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.tournamentsLock.Lock()
+	t, has := s.tournaments[id.ToFullString()]
+	if !has {
+		s.tournamentsLock.Unlock()
+		return web.JSON.NotFound()
+	}
+	if !t.Organizer.Equal(userID) {
+		s.tournamentsLock.Unlock()
+		return web.JSON.Forbidden()
+	}
+	round, err := t.Start()
+	if err == nil {
+		err = s.playRound(s.Ctx, t, round)
+	}
+	status := tournamentStatus(t)
+	notices := s.tournamentNotices(t, status)
+	s.tournamentsLock.Unlock()
+	if err != nil {
+		return web.JSON.BadRequest(err)
+	}
+	s.notify(s.Ctx, notices)
+	return web.JSON.Result(status)
+}
+
+// playRound creates and starts a game for every table of the round that
+// isn't a bye, the tournaments lock has to be held
+func (s *Server) playRound(ctx context.Context, t *tournament.Tournament, round *tournament.Round) error {
+	rg, has := games.RegisteredGames()[t.Game]
+	if !has {
+		return fmt.Errorf("Unknown game %s", t.Game)
+	}
+	usernames := make(map[string]string)
+	for _, p := range t.Players {
+		usernames[p.ID.ToFullString()] = p.Username
+	}
+	for i := range round.Tables {
+		table := &round.Tables[i]
+		if table.IsBye() {
+			continue
+		}
+		g, err := newTournamentGame(rg, t.Config)
+		if err != nil {
+			return err
+		}
+		e, err := s.Router.NewEngine(ctx, g, nil)
+		if err != nil {
+			return err
+		}
+		// tournament tables aren't open to the lobby
+		e.Private = true
+		for _, pid := range table.Players {
+			if s.Router.GetClient(pid) == nil {
+				s.Router.ConnectClient(ctx, NewWebsocket(pid, usernames[pid.ToFullString()], nil, s.InboundPackets))
+			}
+			err = s.Router.Join(ctx, pid, e.ID)
+			if err != nil {
+				return err
+			}
+		}
+		table.Game = e.ID
+		s.track(e)
+		e.Observe(s.observeTournament)
+		go func(id uuid.UUID) {
+			logger.MaybeError(logger.GetLogger(ctx), s.Router.StartEngine(ctx, id))
+		}(e.ID)
+	}
+	return nil
+}
+
+// observeTournament records the result of a tournament game once it is
+// over, the last result of a round starts the next one
+func (s *Server) observeTournament(ctx context.Context, e *engine.Engine, event engine.Event) {
+	if event.Type != engine.EventTypeOver {
+		return
+	}
+	e.Lock()
+	standings, err := e.Standings()
+	e.Unlock()
+	log := logger.GetLogger(ctx)
+	if err != nil {
+		logger.MaybeError(log, err)
+		return
+	}
+
+	s.tournamentsLock.Lock()
+	t := s.tournamentOf(e.ID)
+	if t == nil {
+		s.tournamentsLock.Unlock()
+		return
+	}
+	next, err := t.Record(e.ID, standings)
+	if err == nil && next != nil {
+		err = s.playRound(ctx, t, next)
+	}
+	notices := s.tournamentNotices(t, tournamentStatus(t))
+	s.tournamentsLock.Unlock()
+	logger.MaybeError(log, err)
+	s.notify(ctx, notices)
+}
+
+// tournamentOf finds the tournament the game is played in, the tournaments
+// lock has to be held
+func (s *Server) tournamentOf(id uuid.UUID) *tournament.Tournament {
+	for _, t := range s.tournaments {
+		round := t.Current()
+		if round == nil {
+			continue
+		}
+		for _, table := range round.Tables {
+			if table.Game != nil && table.Game.Equal(id) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// subscribeTournament starts or stops pushing the updates of a tournament
+// to the client, its players and organizer get them either way
+func (s *Server) subscribeTournament(client, id uuid.UUID, subscribe bool) {
+	s.tournamentsLock.Lock()
+	defer s.tournamentsLock.Unlock()
+	key := id.ToFullString()
+	if !subscribe {
+		delete(s.tournamentWatchers[key], client.ToFullString())
+		return
+	}
+	if s.tournamentWatchers[key] == nil {
+		s.tournamentWatchers[key] = make(map[string]uuid.UUID)
+	}
+	s.tournamentWatchers[key][client.ToFullString()] = client
+}
+
+// tournamentNotices addresses the status to everyone following the
+// tournament, the tournaments lock has to be held
+func (s *Server) tournamentNotices(t *tournament.Tournament, status api.TournamentStatus) []notice {
+	seen := make(map[string]bool)
+	var notices []notice
+	add := func(id uuid.UUID) {
+		if seen[id.ToFullString()] {
+			return
+		}
+		seen[id.ToFullString()] = true
+		notices = append(notices, notice{player: id, t: api.PacketTypeTournamentUpdate, body: status})
+	}
+	add(t.Organizer)
+	for _, p := range t.Players {
+		add(p.ID)
+	}
+	for _, id := range s.tournamentWatchers[t.ID.ToFullString()] {
+		add(id)
+	}
+	return notices
+}
+
+// tournamentStatus copies the rounds so the status can be sent once the
+// tournaments lock is released
+func tournamentStatus(t *tournament.Tournament) api.TournamentStatus {
+	copied := *t
+	copied.Played = make([]tournament.Round, len(t.Played))
+	for i, round := range t.Played {
+		copied.Played[i] = tournament.Round{
+			Number: round.Number,
+			Tables: append([]tournament.Table{}, round.Tables...),
+		}
+	}
+	return api.TournamentStatus{
+		Tournament: copied,
+		Standings:  t.Standings(),
+		Done:       t.IsDone(),
+		Winners:    t.Winners(),
+	}
+}
+
+// newTournamentGame creates a game for a table with the config of the
+// tournament, an empty config plays the game's defaults
+func newTournamentGame(rg games.RegisteredGame, raw json.RawMessage) (game.Game, error) {
+	cfg := rg.Config()
+	if cfg != nil && len(raw) > 0 {
+		ptr := reflect.New(reflect.TypeOf(cfg))
+		ptr.Elem().Set(reflect.ValueOf(cfg))
+		err := json.Unmarshal(raw, ptr.Interface())
+		if err != nil {
+			return nil, err
+		}
+		cfg = ptr.Elem().Interface()
+	}
+	return rg.New(cfg)
+}